@@ -0,0 +1,301 @@
+// Package auth provides credentials for security schemes of generated clients.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/vitaminniy/go-lib-http/retry"
+)
+
+// ErrNoCredentials is returned when none of operation security requirements
+// can be satisfied with configured credentials.
+var ErrNoCredentials = errors.New("auth: no credentials")
+
+// Location is where API key is passed.
+type Location string
+
+// Locations of API key as declared in the spec.
+const (
+	InHeader Location = "header"
+	InQuery  Location = "query"
+	InCookie Location = "cookie"
+)
+
+// APIKeyProvider provides API key.
+type APIKeyProvider interface {
+	APIKey(ctx context.Context) (string, error)
+}
+
+// TokenProvider provides bearer token.
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// BasicProvider provides basic auth credentials.
+type BasicProvider interface {
+	Basic(ctx context.Context) (username, password string, err error)
+}
+
+// Invalidator is implemented by providers caching credentials, e.g.
+// ClientCredentials, to drop them after upstream rejected them.
+type Invalidator interface {
+	Invalidate()
+}
+
+// APIKeyFunc is an APIKeyProvider function.
+type APIKeyFunc func(ctx context.Context) (string, error)
+
+// APIKey implements APIKeyProvider.
+func (f APIKeyFunc) APIKey(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// TokenFunc is a TokenProvider function.
+type TokenFunc func(ctx context.Context) (string, error)
+
+// Token implements TokenProvider.
+func (f TokenFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// BasicFunc is a BasicProvider function.
+type BasicFunc func(ctx context.Context) (username, password string, err error)
+
+// Basic implements BasicProvider.
+func (f BasicFunc) Basic(ctx context.Context) (string, string, error) {
+	return f(ctx)
+}
+
+// StaticAPIKey returns provider of constant API key.
+func StaticAPIKey(key string) APIKeyProvider {
+	return APIKeyFunc(func(context.Context) (string, error) {
+		return key, nil
+	})
+}
+
+// StaticToken returns provider of constant bearer token.
+func StaticToken(token string) TokenProvider {
+	return TokenFunc(func(context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// StaticBasic returns provider of constant basic auth credentials.
+func StaticBasic(username, password string) BasicProvider {
+	return BasicFunc(func(context.Context) (string, string, error) {
+		return username, password, nil
+	})
+}
+
+// Credentials authorize request according to a single security scheme.
+type Credentials interface {
+	Apply(ctx context.Context, req *http.Request) error
+}
+
+// CredentialsFunc is a Credentials function.
+type CredentialsFunc func(ctx context.Context, req *http.Request) error
+
+// Apply implements Credentials.
+func (f CredentialsFunc) Apply(ctx context.Context, req *http.Request) error {
+	return f(ctx, req)
+}
+
+// cachedCredentials are credentials of provider implementing Invalidator.
+type cachedCredentials struct {
+	Credentials
+	Invalidator
+}
+
+// withInvalidator returns credentials invalidating provider if it caches
+// credentials.
+func withInvalidator(credentials Credentials, provider any) Credentials {
+	if invalidator, ok := provider.(Invalidator); ok {
+		return cachedCredentials{Credentials: credentials, Invalidator: invalidator}
+	}
+
+	return credentials
+}
+
+// APIKey returns credentials passing API key in header, query parameter or
+// cookie with the given name.
+func APIKey(in Location, name string, provider APIKeyProvider) Credentials {
+	return withInvalidator(CredentialsFunc(func(ctx context.Context, req *http.Request) error {
+		key, err := provider.APIKey(ctx)
+		if err != nil {
+			return fmt.Errorf("auth: could not get api key: %w", err)
+		}
+
+		switch in {
+		case InHeader:
+			req.Header.Set(name, key)
+		case InQuery:
+			query := req.URL.Query()
+			query.Set(name, key)
+			req.URL.RawQuery = query.Encode()
+		case InCookie:
+			req.AddCookie(&http.Cookie{Name: name, Value: key})
+		default:
+			return retry.Abort(fmt.Errorf("auth: unsupported api key location %q", in))
+		}
+
+		return nil
+	}), provider)
+}
+
+// Bearer returns credentials passing token in Authorization header.
+func Bearer(provider TokenProvider) Credentials {
+	return withInvalidator(CredentialsFunc(func(ctx context.Context, req *http.Request) error {
+		token, err := provider.Token(ctx)
+		if err != nil {
+			return fmt.Errorf("auth: could not get token: %w", err)
+		}
+
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		return nil
+	}), provider)
+}
+
+// Basic returns credentials passing basic auth in Authorization header.
+func Basic(provider BasicProvider) Credentials {
+	return withInvalidator(CredentialsFunc(func(ctx context.Context, req *http.Request) error {
+		username, password, err := provider.Basic(ctx)
+		if err != nil {
+			return fmt.Errorf("auth: could not get basic credentials: %w", err)
+		}
+
+		req.SetBasicAuth(username, password)
+
+		return nil
+	}), provider)
+}
+
+// Requirement lists names of security schemes which must be applied together.
+// Empty requirement means authorization is optional.
+type Requirement []string
+
+// Authorize applies credentials of the first requirement which schemes all
+// have credentials configured. Requests of operations without requirements
+// are left as is.
+//
+// ErrNoCredentials is marked with retry.Abort as it won't go away on retry.
+func Authorize(
+	ctx context.Context,
+	req *http.Request,
+	credentials map[string]Credentials,
+	requirements []Requirement,
+) error {
+	if len(requirements) == 0 {
+		return nil
+	}
+
+	requirement, ok := applicable(credentials, requirements)
+	if !ok {
+		names := make([]string, 0, len(requirements))
+		for _, requirement := range requirements {
+			names = append(names, strings.Join(requirement, "+"))
+		}
+
+		return retry.Abort(fmt.Errorf("%w: want one of %s", ErrNoCredentials, strings.Join(names, ", ")))
+	}
+
+	for _, scheme := range requirement {
+		if err := credentials[scheme].Apply(ctx, req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Do authorizes req with Authorize and sends it with send. When upstream
+// rejects request with 401 Unauthorized and applied credentials are cached,
+// i.e. implement Invalidator, they are invalidated and request is authorized
+// and sent once again.
+func Do(
+	ctx context.Context,
+	req *http.Request,
+	credentials map[string]Credentials,
+	requirements []Requirement,
+	send func(*http.Request) (*http.Response, error),
+) (*http.Response, error) {
+	requirement, _ := applicable(credentials, requirements)
+
+	var invalidators []Invalidator
+
+	for _, scheme := range requirement {
+		if invalidator, ok := credentials[scheme].(Invalidator); ok {
+			invalidators = append(invalidators, invalidator)
+		}
+	}
+
+	// Request can't be sent again without body copy.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		invalidators = nil
+	}
+
+	// Request is authorized again from scratch, e.g. without cookie set by
+	// rejected credentials.
+	var unauthorized *http.Request
+	if len(invalidators) > 0 {
+		unauthorized = req.Clone(ctx)
+	}
+
+	if err := Authorize(ctx, req, credentials, requirements); err != nil {
+		return nil, err
+	}
+
+	resp, err := send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || len(invalidators) == 0 {
+		return resp, err
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	for _, invalidator := range invalidators {
+		invalidator.Invalidate()
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("auth: could not copy request body: %w", err)
+		}
+
+		unauthorized.Body = body
+	}
+
+	if err := Authorize(ctx, unauthorized, credentials, requirements); err != nil {
+		return nil, err
+	}
+
+	return send(unauthorized)
+}
+
+// applicable returns the first requirement which schemes all have
+// credentials.
+func applicable(credentials map[string]Credentials, requirements []Requirement) (Requirement, bool) {
+	for _, requirement := range requirements {
+		if satisfied(credentials, requirement) {
+			return requirement, true
+		}
+	}
+
+	return nil, false
+}
+
+func satisfied(credentials map[string]Credentials, requirement Requirement) bool {
+	for _, scheme := range requirement {
+		if credentials[scheme] == nil {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,233 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/vitaminniy/go-lib-http/retry"
+)
+
+var ErrProviderFailed = errors.New("provider failed")
+
+func TestAPIKey(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		in    Location
+		check func(req *http.Request) string
+	}{
+		{in: InHeader, check: func(req *http.Request) string { return req.Header.Get("X-Api-Key") }},
+		{in: InQuery, check: func(req *http.Request) string { return req.URL.Query().Get("X-Api-Key") }},
+		{in: InCookie, check: func(req *http.Request) string {
+			cookie, err := req.Cookie("X-Api-Key")
+			if err != nil {
+				return ""
+			}
+
+			return cookie.Value
+		}},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(string(c.in), func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/?limit=1", nil)
+
+			if err := APIKey(c.in, "X-Api-Key", StaticAPIKey("key")).Apply(context.Background(), req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := c.check(req); got != "key" {
+				t.Fatalf("key mismatch: want %q; got %q", "key", got)
+			}
+
+			if got := req.URL.Query().Get("limit"); got != "1" {
+				t.Fatalf("query was modified: %q", req.URL.RawQuery)
+			}
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	t.Parallel()
+
+	credentials := map[string]Credentials{
+		"bearer": Bearer(StaticToken("token")),
+		"basic":  Basic(StaticBasic("user", "pass")),
+		"apiKey": APIKey(InHeader, "X-Api-Key", StaticAPIKey("key")),
+		"broken": Bearer(TokenFunc(func(context.Context) (string, error) {
+			return "", ErrProviderFailed
+		})),
+	}
+
+	cases := []struct {
+		name          string
+		requirements  []Requirement
+		authorization string
+		apiKey        string
+		err           error
+	}{
+		{name: "no security"},
+		{name: "optional", requirements: []Requirement{{}}},
+		{
+			name:          "first satisfied",
+			requirements:  []Requirement{{"oauth2"}, {"bearer"}, {"basic"}},
+			authorization: "Bearer token",
+		},
+		{
+			name:          "all schemes of requirement",
+			requirements:  []Requirement{{"basic", "apiKey"}},
+			authorization: "Basic dXNlcjpwYXNz",
+			apiKey:        "key",
+		},
+		{
+			name:         "not satisfied",
+			requirements: []Requirement{{"oauth2"}, {"bearer", "oauth2"}},
+			err:          ErrNoCredentials,
+		},
+		{
+			name:         "provider error",
+			requirements: []Requirement{{"broken"}},
+			err:          ErrProviderFailed,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			err := Authorize(context.Background(), req, credentials, c.requirements)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("error mismatch: want %v; got %v", c.err, err)
+			}
+
+			if errors.Is(err, ErrNoCredentials) && !retry.IsAborted(err) {
+				t.Fatal("missing credentials error is not aborted")
+			}
+
+			if got := req.Header.Get("Authorization"); got != c.authorization {
+				t.Fatalf("authorization mismatch: want %q; got %q", c.authorization, got)
+			}
+
+			if got := req.Header.Get("X-Api-Key"); got != c.apiKey {
+				t.Fatalf("api key mismatch: want %q; got %q", c.apiKey, got)
+			}
+		})
+	}
+}
+
+// cachedToken is a TokenProvider issuing "token-1", "token-2" and so on after
+// every invalidation.
+type cachedToken struct {
+	issued int
+}
+
+func (c *cachedToken) Token(context.Context) (string, error) {
+	return "token-" + strconv.Itoa(c.issued+1), nil
+}
+
+func (c *cachedToken) Invalidate() {
+	c.issued++
+}
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		provider TokenProvider
+		accepted string
+		want     []string
+		status   int
+	}{
+		{
+			name:     "accepted",
+			provider: &cachedToken{},
+			accepted: "Bearer token-1",
+			want:     []string{"Bearer token-1"},
+			status:   http.StatusOK,
+		},
+		{
+			name:     "invalidated",
+			provider: &cachedToken{},
+			accepted: "Bearer token-2",
+			want:     []string{"Bearer token-1", "Bearer token-2"},
+			status:   http.StatusOK,
+		},
+		{
+			name:     "retried once",
+			provider: &cachedToken{},
+			accepted: "Bearer token-3",
+			want:     []string{"Bearer token-1", "Bearer token-2"},
+			status:   http.StatusUnauthorized,
+		},
+		{
+			name:     "not cached",
+			provider: StaticToken("token"),
+			want:     []string{"Bearer token"},
+			status:   http.StatusUnauthorized,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			credentials := map[string]Credentials{"oauth2": Bearer(c.provider)}
+
+			req, err := http.NewRequestWithContext(
+				context.Background(), http.MethodPost, "http://example.com", strings.NewReader("body"),
+			)
+			if err != nil {
+				t.Fatalf("could not prepare request: %v", err)
+			}
+
+			var got []string
+
+			resp, err := Do(context.Background(), req, credentials, []Requirement{{"oauth2"}}, func(req *http.Request) (*http.Response, error) {
+				body, err := io.ReadAll(req.Body)
+				if err != nil || string(body) != "body" {
+					t.Fatalf("body mismatch: %q: %v", body, err)
+				}
+
+				authorization := req.Header.Get("Authorization")
+				got = append(got, authorization)
+
+				rec := httptest.NewRecorder()
+				if authorization != c.accepted {
+					rec.WriteHeader(http.StatusUnauthorized)
+				}
+
+				return rec.Result(), nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != c.status {
+				t.Fatalf("status mismatch: want %d; got %d", c.status, resp.StatusCode)
+			}
+
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("authorization mismatch: want %v; got %v", c.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRefreshBefore is how long before expiry cached token is refreshed by
+// default.
+const DefaultRefreshBefore = time.Second * 30
+
+// DefaultLifetime is a lifetime of tokens issued without expires_in by
+// default.
+const DefaultLifetime = time.Minute * 5
+
+// ErrTokenRequest is returned when token endpoint rejects request.
+var ErrTokenRequest = errors.New("auth: token request failed")
+
+// redacted replaces secrets in logs and encoded configs.
+const redacted = "REDACTED"
+
+// Secret is a credential which is redacted when formatted or encoded, so
+// configs may be logged and dumped safely. It's decoded as plain string.
+type Secret string
+
+// String implements fmt.Stringer.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+
+	return redacted
+}
+
+// GoString implements fmt.GoStringer for %#v verb.
+func (s Secret) GoString() string {
+	return strconv.Quote(s.String())
+}
+
+// MarshalText implements encoding.TextMarshaler used by JSON and YAML
+// encoders.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ClientCredentialsConfig configures OAuth2 client credentials flow.
+type ClientCredentialsConfig struct {
+	TokenURL     string   `json:"token_url,omitempty" yaml:"token_url,omitempty"`
+	ClientID     string   `json:"client_id,omitempty" yaml:"client_id,omitempty"`
+	ClientSecret Secret   `json:"client_secret,omitempty" yaml:"client_secret,omitempty"`
+	Scopes       []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	// RefreshBefore is how long before expiry token is refreshed;
+	// DefaultRefreshBefore is used if zero. It's capped at half of token
+	// lifetime, so short-lived tokens are reused as well.
+	RefreshBefore time.Duration `json:"refresh_before,omitempty" yaml:"refresh_before,omitempty"`
+	// DefaultLifetime is a lifetime of tokens issued without expires_in;
+	// DefaultLifetime constant is used if zero.
+	DefaultLifetime time.Duration `json:"default_lifetime,omitempty" yaml:"default_lifetime,omitempty"`
+}
+
+// ClientCredentials is a TokenProvider implementing OAuth2 client credentials
+// flow. Tokens are cached and refreshed before expiry.
+type ClientCredentials struct {
+	cfg    ClientCredentialsConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu    sync.Mutex
+	token string
+	// refresh is when cached token is refreshed.
+	refresh time.Time
+}
+
+// NewClientCredentials creates new client credentials token provider. Tokens
+// are requested with client or http.DefaultClient if it's nil.
+func NewClientCredentials(cfg ClientCredentialsConfig, client *http.Client) *ClientCredentials {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	if cfg.RefreshBefore == 0 {
+		cfg.RefreshBefore = DefaultRefreshBefore
+	}
+
+	if cfg.DefaultLifetime == 0 {
+		cfg.DefaultLifetime = DefaultLifetime
+	}
+
+	return &ClientCredentials{
+		cfg:    cfg,
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// Token implements TokenProvider. Concurrent callers wait for a single token
+// request.
+func (c *ClientCredentials) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && c.now().Before(c.refresh) {
+		return c.token, nil
+	}
+
+	token, expiresIn, err := c.request(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if expiresIn <= 0 {
+		expiresIn = c.cfg.DefaultLifetime
+	}
+
+	c.token = token
+	c.refresh = c.now().Add(expiresIn - min(c.cfg.RefreshBefore, expiresIn/2))
+
+	return token, nil
+}
+
+// Invalidate drops cached token, e.g. after upstream rejected it.
+func (c *ClientCredentials) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.token = ""
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (c *ClientCredentials) request(ctx context.Context) (string, time.Duration, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.cfg.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("auth: could not prepare token request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// Client credentials are form-encoded before basic auth, see RFC 6749
+	// section 2.3.1.
+	req.SetBasicAuth(url.QueryEscape(c.cfg.ClientID), url.QueryEscape(string(c.cfg.ClientSecret)))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("auth: could not request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	const maxBody = 1 << 20
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		return "", 0, fmt.Errorf("auth: could not read token response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("%w: status %d: %q", ErrTokenRequest, resp.StatusCode, string(raw))
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(raw, &token); err != nil {
+		return "", 0, fmt.Errorf("auth: could not decode token response: %w", err)
+	}
+
+	if token.AccessToken == "" {
+		return "", 0, fmt.Errorf("%w: empty access token", ErrTokenRequest)
+	}
+
+	if token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer") {
+		return "", 0, fmt.Errorf("%w: unsupported token type %q", ErrTokenRequest, token.TokenType)
+	}
+
+	return token.AccessToken, time.Duration(token.ExpiresIn) * time.Second, nil
+}
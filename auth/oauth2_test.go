@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tokenServer is a fake token endpoint issuing tokens "token-1", "token-2"
+// and so on.
+type tokenServer struct {
+	*httptest.Server
+
+	issued    atomic.Int32
+	expiresIn int64
+}
+
+func newTokenServer(t *testing.T, expiresIn int64) *tokenServer {
+	t.Helper()
+
+	srv := &tokenServer{expiresIn: expiresIn}
+	srv.Server = httptest.NewServer(http.HandlerFunc(srv.serve))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func (s *tokenServer) serve(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	// Credentials are form-encoded, see RFC 6749 section 2.3.1.
+	id, _ = url.QueryUnescape(id)
+	secret, _ = url.QueryUnescape(secret)
+
+	if !ok || id != "client" || secret != "s3cr%t" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+
+		return
+	}
+
+	if r.PostFormValue("grant_type") != "client_credentials" || r.PostFormValue("scope") != "read write" {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid_request"}`))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "token-" + strconv.Itoa(int(s.issued.Add(1))),
+		"token_type":   "Bearer",
+		"expires_in":   s.expiresIn,
+	})
+}
+
+func TestClientCredentials(t *testing.T) {
+	t.Parallel()
+
+	srv := newTokenServer(t, 120)
+
+	provider := NewClientCredentials(ClientCredentialsConfig{
+		TokenURL:     srv.URL,
+		ClientID:     "client",
+		ClientSecret: "s3cr%t",
+		Scopes:       []string{"read", "write"},
+	}, srv.Client())
+
+	now := time.Now()
+	provider.now = func() time.Time { return now }
+
+	expect := func(want string) {
+		t.Helper()
+
+		got, err := provider.Token(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got != want {
+			t.Fatalf("token mismatch: want %q; got %q", want, got)
+		}
+	}
+
+	expect("token-1")
+
+	// Token is cached until it's about to expire.
+	now = now.Add(time.Second * 80)
+	expect("token-1")
+
+	now = now.Add(time.Second * 10)
+	expect("token-2")
+
+	provider.Invalidate()
+	expect("token-3")
+}
+
+func TestClientCredentialsRejected(t *testing.T) {
+	t.Parallel()
+
+	srv := newTokenServer(t, 120)
+
+	provider := NewClientCredentials(ClientCredentialsConfig{
+		TokenURL:     srv.URL,
+		ClientID:     "client",
+		ClientSecret: "wrong",
+	}, srv.Client())
+
+	if _, err := provider.Token(context.Background()); !errors.Is(err, ErrTokenRequest) {
+		t.Fatalf("error mismatch: want %v; got %v", ErrTokenRequest, err)
+	}
+}
+
+func TestClientCredentialsWithoutExpiry(t *testing.T) {
+	t.Parallel()
+
+	srv := newTokenServer(t, 0)
+
+	provider := NewClientCredentials(ClientCredentialsConfig{
+		TokenURL:        srv.URL,
+		ClientID:        "client",
+		ClientSecret:    "s3cr%t",
+		Scopes:          []string{"read", "write"},
+		DefaultLifetime: time.Minute,
+	}, srv.Client())
+
+	now := time.Now()
+	provider.now = func() time.Time { return now }
+
+	// Token is cached for default lifetime.
+	for _, step := range []struct {
+		after time.Duration
+		want  string
+	}{
+		{after: 0, want: "token-1"},
+		{after: time.Second * 29, want: "token-1"},
+		{after: time.Second, want: "token-2"},
+	} {
+		now = now.Add(step.after)
+
+		got, err := provider.Token(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got != step.want {
+			t.Fatalf("token mismatch: want %q; got %q", step.want, got)
+		}
+	}
+}
+
+func TestClientCredentialsShortExpiry(t *testing.T) {
+	t.Parallel()
+
+	srv := newTokenServer(t, 10)
+
+	provider := NewClientCredentials(ClientCredentialsConfig{
+		TokenURL:     srv.URL,
+		ClientID:     "client",
+		ClientSecret: "s3cr%t",
+		Scopes:       []string{"read", "write"},
+	}, srv.Client())
+
+	now := time.Now()
+	provider.now = func() time.Time { return now }
+
+	// Refresh margin is capped at half of token lifetime, so token is reused
+	// although it expires sooner than DefaultRefreshBefore.
+	for _, step := range []struct {
+		after time.Duration
+		want  string
+	}{
+		{after: 0, want: "token-1"},
+		{after: time.Second * 4, want: "token-1"},
+		{after: time.Second, want: "token-2"},
+		{after: time.Second * 4, want: "token-2"},
+	} {
+		now = now.Add(step.after)
+
+		got, err := provider.Token(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got != step.want {
+			t.Fatalf("token mismatch: want %q; got %q", step.want, got)
+		}
+	}
+}
+
+func TestSecret(t *testing.T) {
+	t.Parallel()
+
+	cfg := ClientCredentialsConfig{ClientID: "client", ClientSecret: "s3cr%t"}
+
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("could not encode config: %v", err)
+	}
+
+	for _, formatted := range []string{fmt.Sprintf("%v", cfg), fmt.Sprintf("%+v", cfg), fmt.Sprintf("%#v", cfg), string(raw)} {
+		if strings.Contains(formatted, "s3cr%t") {
+			t.Fatalf("secret is not redacted: %s", formatted)
+		}
+	}
+
+	var decoded ClientCredentialsConfig
+	if err := json.Unmarshal([]byte(`{"client_secret":"s3cr%t"}`), &decoded); err != nil {
+		t.Fatalf("could not decode config: %v", err)
+	}
+
+	if decoded.ClientSecret != "s3cr%t" {
+		t.Fatalf("secret mismatch: want %q; got %q", "s3cr%t", string(decoded.ClientSecret))
+	}
+}
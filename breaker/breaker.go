@@ -0,0 +1,157 @@
+// Package breaker provides circuit breaker to stop calling failing services.
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned when breaker does not allow calls.
+var ErrOpen = errors.New("breaker: circuit is open")
+
+// Config controls circuit breaker behaviour.
+type Config struct {
+	// Failures is a number of consecutive failures which opens the breaker.
+	// Breaker is disabled when set to 0.
+	Failures uint `json:"failures,omitempty" yaml:"failures,omitempty"`
+	// Cooldown is an interval breaker stays open before letting a single
+	// probe call through.
+	Cooldown time.Duration `json:"cooldown,omitempty" yaml:"cooldown,omitempty"`
+}
+
+// Enabled reports whether breaker is enabled.
+func (cfg *Config) Enabled() bool {
+	return cfg.Failures > 0
+}
+
+// State is a circuit breaker state.
+type State int
+
+const (
+	// StateClosed lets all calls through.
+	StateClosed State = iota
+	// StateOpen rejects all calls.
+	StateOpen
+	// StateHalfOpen lets a single probe call through.
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// New creates new circuit breaker. Nil is returned when breaker is disabled;
+// nil breaker lets all calls through.
+func New(cfg Config) *Breaker {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	return &Breaker{
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+// Breaker is a consecutive failures circuit breaker.
+type Breaker struct {
+	cfg Config
+	now func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures uint
+	openedAt time.Time
+}
+
+// Allow returns ErrOpen if call is not allowed. Caller must Record result or
+// Cancel every allowed call.
+func (b *Breaker) Allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateClosed:
+		return nil
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.cfg.Cooldown {
+			return ErrOpen
+		}
+
+		b.state = StateHalfOpen
+
+		return nil
+	case StateHalfOpen:
+		// Probe call is in flight already.
+		return ErrOpen
+	default:
+		return nil
+	}
+}
+
+// Record records result of allowed call.
+func (b *Breaker) Record(success bool) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		b.state = StateClosed
+		b.failures = 0
+
+		return
+	}
+
+	b.failures++
+
+	if b.state == StateHalfOpen || b.failures >= b.cfg.Failures {
+		b.state = StateOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Cancel releases allowed call abandoned by caller, e.g. canceled, without
+// counting it as success or failure. Released probe call lets the next call
+// probe instead.
+func (b *Breaker) Cancel() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen {
+		// Cooldown has passed, so the next call is allowed as a probe.
+		b.state = StateOpen
+	}
+}
+
+// State returns current breaker state.
+func (b *Breaker) State() State {
+	if b == nil {
+		return StateClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
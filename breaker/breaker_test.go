@@ -0,0 +1,140 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newTestBreaker(cfg Config) (*Breaker, *clock) {
+	clk := &clock{now: time.Unix(0, 0)}
+
+	b := New(cfg)
+	b.now = clk.Now
+
+	return b, clk
+}
+
+func allow(t *testing.T, b *Breaker, want error) {
+	t.Helper()
+
+	if err := b.Allow(); !errors.Is(err, want) {
+		t.Fatalf("allow mismatch: want %v; got %v", want, err)
+	}
+}
+
+func TestDisabledBreaker(t *testing.T) {
+	t.Parallel()
+
+	b := New(Config{})
+	if b != nil {
+		t.Fatal("expected disabled breaker to be nil")
+	}
+
+	for range 10 {
+		allow(t, b, nil)
+		b.Record(false)
+	}
+
+	if b.State() != StateClosed {
+		t.Fatalf("state mismatch: want %v; got %v", StateClosed, b.State())
+	}
+}
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	t.Parallel()
+
+	b, _ := newTestBreaker(Config{Failures: 2, Cooldown: time.Second})
+
+	allow(t, b, nil)
+	b.Record(false)
+	allow(t, b, nil)
+	b.Record(true)
+
+	// Success resets failures counter.
+	allow(t, b, nil)
+	b.Record(false)
+
+	if b.State() != StateClosed {
+		t.Fatalf("state mismatch: want %v; got %v", StateClosed, b.State())
+	}
+
+	allow(t, b, nil)
+	b.Record(false)
+
+	if b.State() != StateOpen {
+		t.Fatalf("state mismatch: want %v; got %v", StateOpen, b.State())
+	}
+
+	allow(t, b, ErrOpen)
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	t.Parallel()
+
+	b, clk := newTestBreaker(Config{Failures: 1, Cooldown: time.Second})
+
+	allow(t, b, nil)
+	b.Record(false)
+
+	clk.now = clk.now.Add(time.Second)
+
+	// Only single probe is allowed.
+	allow(t, b, nil)
+	allow(t, b, ErrOpen)
+
+	if b.State() != StateHalfOpen {
+		t.Fatalf("state mismatch: want %v; got %v", StateHalfOpen, b.State())
+	}
+
+	// Failed probe opens breaker again.
+	b.Record(false)
+	allow(t, b, ErrOpen)
+
+	clk.now = clk.now.Add(time.Second)
+
+	allow(t, b, nil)
+	b.Record(true)
+
+	if b.State() != StateClosed {
+		t.Fatalf("state mismatch: want %v; got %v", StateClosed, b.State())
+	}
+
+	allow(t, b, nil)
+}
+
+func TestBreakerCancel(t *testing.T) {
+	t.Parallel()
+
+	b, clk := newTestBreaker(Config{Failures: 1, Cooldown: time.Second})
+
+	allow(t, b, nil)
+	b.Cancel()
+
+	if b.State() != StateClosed {
+		t.Fatalf("state mismatch: want %v; got %v", StateClosed, b.State())
+	}
+
+	allow(t, b, nil)
+	b.Record(false)
+
+	clk.now = clk.now.Add(time.Second)
+
+	// Canceled probe lets the next call probe.
+	allow(t, b, nil)
+	b.Cancel()
+	allow(t, b, nil)
+	b.Record(true)
+
+	if b.State() != StateClosed {
+		t.Fatalf("state mismatch: want %v; got %v", StateClosed, b.State())
+	}
+}
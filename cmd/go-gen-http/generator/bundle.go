@@ -0,0 +1,357 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errRecursiveRef = errors.New("recursive reference")
+
+// bundler copies schemas of other documents into spec components.
+type bundler struct {
+	resolver *refResolver
+	// imports map documents to Go import paths of packages declaring their
+	// schemas.
+	imports   map[string]string
+	spec      *refDocument
+	documents map[string]*refDocument
+	// schemas is a components.schemas mapping of spec.
+	schemas *yaml.Node
+	// names are canonical names of component schemas.
+	names map[string]bool
+	// bundled are component keys of bundled references by location and
+	// pointer.
+	bundled map[string]string
+	// inlining are references being inlined to detect recursion.
+	inlining map[string]bool
+	changed  bool
+}
+
+// BundleRefs copies schemas referenced from other files and URLs into spec
+// components, so they are generated as local ones, and inlines other
+// external references. Identical references share one component; it's named
+// after the referenced schema unless the name is taken, e.g. by a local
+// schema, then it's prefixed with document name: "Error" of "common.yaml"
+// becomes "CommonError". Schemas of documents mapped by Imports are used
+// from those packages instead. Spec is returned as is if it has no external
+// references.
+func (c *SpecConfig) BundleRefs(raw []byte) ([]byte, error) {
+	spec, err := c.specDocument(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &bundler{
+		resolver:  c.refResolver(),
+		imports:   make(map[string]string, len(c.Imports)),
+		spec:      spec,
+		documents: map[string]*refDocument{spec.location: spec},
+		names:     make(map[string]bool),
+		bundled:   make(map[string]string),
+		inlining:  make(map[string]bool),
+	}
+
+	for location, importPath := range c.Imports {
+		if !isRemote(location) {
+			if location, err = filepath.Abs(location); err != nil {
+				return nil, fmt.Errorf("could not resolve import %q: %w", location, err)
+			}
+		}
+
+		b.imports[location] = importPath
+	}
+
+	root := spec.root.Content[0]
+	components := mappingValue(root, "components")
+
+	if schemas := mappingValue(components, "schemas"); schemas != nil {
+		b.schemas = schemas
+
+		for _, pair := range mappingPairs(schemas) {
+			b.names[canonize(pair.key)] = true
+		}
+	}
+
+	for _, pair := range mappingPairs(root) {
+		if pair.key != "components" {
+			if err := b.walk(pair.value, spec, false); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		for _, component := range mappingPairs(pair.value) {
+			schema := component.key == "schemas"
+
+			for _, item := range mappingPairs(component.value) {
+				if err := b.walk(item.value, spec, schema); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if !b.changed {
+		return raw, nil
+	}
+
+	result, err := yaml.Marshal(spec.root)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode spec: %w", err)
+	}
+
+	return result, nil
+}
+
+// walk resolves external references of node; schema reports whether node is
+// a schema.
+func (b *bundler) walk(node *yaml.Node, doc *refDocument, schema bool) error {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			if err := b.walk(child, doc, false); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		if ref := mappingValue(node, "$ref"); ref != nil && ref.Kind == yaml.ScalarNode {
+			return b.resolve(node, ref.Value, doc, schema)
+		}
+
+		for _, pair := range mappingPairs(node) {
+			if err := b.walkChild(pair.key, pair.value, doc, schema); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// walkChild walks value of key; subschemas are told apart from other schema
+// keywords.
+func (b *bundler) walkChild(key string, value *yaml.Node, doc *refDocument, schema bool) error {
+	switch {
+	case isDataKeyword(key):
+		return nil
+	case !schema && namedKeys[key]:
+		// Names may look like keywords, e.g. x-request-id header; values
+		// are schemas except headers.
+		for _, pair := range mappingPairs(value) {
+			if err := b.walk(pair.value, doc, key != "headers"); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case !schema:
+		return b.walk(value, doc, key == "schema")
+	}
+
+	switch key {
+	case "items", "additionalProperties", "not":
+		return b.walk(value, doc, true)
+	case "properties", "patternProperties", "definitions", "$defs":
+		for _, pair := range mappingPairs(value) {
+			if err := b.walk(pair.value, doc, true); err != nil {
+				return err
+			}
+		}
+	case "allOf", "oneOf", "anyOf", "prefixItems":
+		for _, child := range value.Content {
+			if err := b.walk(child, doc, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolve rewrites reference of node found in doc: schemas are bundled and
+// referenced locally, other values are inlined.
+func (b *bundler) resolve(node *yaml.Node, ref string, doc *refDocument, schema bool) error {
+	location, pointer, _ := strings.Cut(ref, "#")
+
+	key := doc.location
+	if location != "" {
+		key = resolveLocation(doc.base, location)
+	}
+
+	if key == b.spec.location {
+		if location != "" || doc != b.spec {
+			setMappingValue(node, "$ref", "#"+pointer)
+			b.changed = true
+		}
+
+		return nil
+	}
+
+	target, err := b.document(key)
+	if err != nil {
+		return fmt.Errorf("could not resolve %q in %s: %w", ref, doc.label, err)
+	}
+
+	value, err := resolvePointer(target.root, pointer)
+	if err != nil {
+		return fmt.Errorf("could not resolve %q in %s: %w", ref, doc.label, err)
+	}
+
+	b.changed = true
+
+	if schema {
+		name, err := b.bundle(target, pointer, value)
+		if err != nil {
+			return err
+		}
+
+		setMappingValue(node, "$ref", "#/components/schemas/"+name)
+
+		return nil
+	}
+
+	id := key + "#" + pointer
+	if b.inlining[id] {
+		return fmt.Errorf("could not inline %q in %s: %w", ref, doc.label, errRecursiveRef)
+	}
+
+	b.inlining[id] = true
+	defer delete(b.inlining, id)
+
+	inlined := copyNode(value)
+	if err := b.walk(inlined, target, false); err != nil {
+		return err
+	}
+
+	*node = *inlined
+
+	return nil
+}
+
+// bundle adds schema of document to spec components and returns its key.
+func (b *bundler) bundle(doc *refDocument, pointer string, value *yaml.Node) (string, error) {
+	id := doc.location + "#" + pointer
+	if name, ok := b.bundled[id]; ok {
+		return name, nil
+	}
+
+	tokens := pointerTokens(pointer)
+
+	original := documentName(doc.location)
+	if len(tokens) > 0 {
+		original = tokens[len(tokens)-1]
+	}
+
+	// Whole document is already named after it, so it's only numbered.
+	name := canonize(original)
+	if b.names[name] && len(tokens) > 0 {
+		name = canonize(documentName(doc.location)) + name
+	}
+
+	for i, prefix := 2, name; b.names[name]; i++ {
+		name = fmt.Sprintf("%s%d", prefix, i)
+	}
+
+	b.names[name] = true
+	b.bundled[id] = name
+
+	if b.schemas == nil {
+		root := b.spec.root.Content[0]
+
+		components := mappingValue(root, "components")
+		if components == nil {
+			components = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "components"}, components)
+		}
+
+		b.schemas = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		components.Content = append(components.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "schemas"}, b.schemas)
+	}
+
+	importPath, imported := b.imports[doc.location]
+
+	schema := copyNode(value)
+	if imported {
+		schema = importedSchema(value, importPath+"#"+schemaGoName(original, value))
+	}
+
+	// Schema is added before ones it references, so components follow the
+	// order they're referenced in.
+	b.schemas.Content = append(b.schemas.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}, schema)
+
+	if !imported {
+		if err := b.walk(schema, doc, true); err != nil {
+			return "", err
+		}
+	}
+
+	return name, nil
+}
+
+// document returns loaded document at location.
+func (b *bundler) document(location string) (*refDocument, error) {
+	if doc, ok := b.documents[location]; ok {
+		return doc, nil
+	}
+
+	doc, err := b.resolver.load(location)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(doc.root.Content) == 0 {
+		return nil, fmt.Errorf("%s: %w", doc.label, errEmptySpec)
+	}
+
+	b.documents[location] = doc
+
+	return doc, nil
+}
+
+// schemaGoName returns Go name of schema declared in other package: its
+// x-go-name or canonized key.
+func schemaGoName(key string, schema *yaml.Node) string {
+	if name := scalarValue(mappingValue(schema, extGoName)); name != "" {
+		return name
+	}
+
+	return canonize(key)
+}
+
+// importedSchema returns schema of existing Go type keeping its JSON type.
+func importedSchema(schema *yaml.Node, goType string) *yaml.Node {
+	result := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+
+	if typ := mappingValue(schema, "type"); typ != nil {
+		result.Content = append(result.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "type"}, copyNode(typ))
+	}
+
+	setMappingValue(result, extGoType, goType)
+
+	return result
+}
+
+// documentName returns file name of document without extension, e.g.
+// "common" for "../common.yaml".
+func documentName(location string) string {
+	name := path.Base(filepath.ToSlash(location))
+
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+func copyNode(node *yaml.Node) *yaml.Node {
+	result := *node
+	result.Content = make([]*yaml.Node, 0, len(node.Content))
+
+	for _, child := range node.Content {
+		result.Content = append(result.Content, copyNode(child))
+	}
+
+	return &result
+}
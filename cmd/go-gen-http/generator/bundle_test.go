@@ -0,0 +1,347 @@
+package generator
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const bundleSpec = `
+openapi: 3.0.0
+info: {title: Example Service, version: 1.0.0}
+paths:
+  /messages:
+    get:
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: 'https://example.com/schemas/common.yaml#/components/schemas/Message'}
+        400:
+          description: Bad request
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Error'}
+        404:
+          $ref: 'errors.yaml#/components/responses/NotFound'
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: 'https://example.com/schemas/common.yaml#/components/schemas/Message'}
+      responses:
+        201:
+          description: Created
+          content:
+            application/json:
+              schema: {$ref: 'user.yaml'}
+components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        code: {type: integer}
+`
+
+const bundleErrorsSpec = `
+components:
+  responses:
+    NotFound:
+      description: Not found
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/Error'}
+  schemas:
+    Error:
+      type: object
+      x-go-name: Failure
+      properties:
+        message: {type: string}
+`
+
+const bundleUserSpec = `
+type: object
+properties:
+  name: {type: string}
+`
+
+// bundleDir returns references directory of refsDir with bundled specs.
+func bundleDir(t *testing.T) string {
+	t.Helper()
+
+	dir := refsDir(t)
+
+	for name, content := range map[string]string{
+		"api.yaml":    bundleSpec,
+		"errors.yaml": bundleErrorsSpec,
+		"user.yaml":   bundleUserSpec,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("could not write %s: %v", name, err)
+		}
+	}
+
+	return dir
+}
+
+func bundleRefs(t *testing.T, spec SpecConfig, raw string) *yaml.Node {
+	t.Helper()
+
+	result, err := spec.BundleRefs([]byte(raw))
+	if err != nil {
+		t.Fatalf("could not bundle references: %v", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(result, &root); err != nil {
+		t.Fatalf("could not decode bundled spec: %v", err)
+	}
+
+	return &root
+}
+
+func pointerValue(t *testing.T, root *yaml.Node, pointer string) string {
+	t.Helper()
+
+	node, err := resolvePointer(root, pointer)
+	if err != nil {
+		t.Fatalf("could not resolve %s: %v", pointer, err)
+	}
+
+	return node.Value
+}
+
+func TestBundleRefs(t *testing.T) {
+	t.Parallel()
+
+	dir := bundleDir(t)
+
+	spec := SpecConfig{
+		Spec:       filepath.Join(dir, "api.yaml"),
+		RemoteRefs: ptr(false),
+		Refs:       map[string]string{"https://example.com/schemas/": filepath.Join(dir, "vendor")},
+	}
+
+	root := bundleRefs(t, spec, bundleSpec)
+
+	for pointer, want := range map[string]string{
+		"/paths/~1messages/get/responses/200/content/application~1json/schema/$ref":  "#/components/schemas/Message",
+		"/paths/~1messages/post/requestBody/content/application~1json/schema/$ref":   "#/components/schemas/Message",
+		"/paths/~1messages/post/responses/201/content/application~1json/schema/$ref": "#/components/schemas/User2",
+		"/paths/~1messages/get/responses/400/content/application~1json/schema/$ref":  "#/components/schemas/Error",
+		// Response is inlined, its schema collides with local Error.
+		"/paths/~1messages/get/responses/404/description":                           "Not found",
+		"/paths/~1messages/get/responses/404/content/application~1json/schema/$ref": "#/components/schemas/ErrorsError",
+		"/components/schemas/Message/properties/author/$ref":                        "#/components/schemas/User",
+		"/components/schemas/ErrorsError/x-go-name":                                 "Failure",
+		"/components/schemas/User/properties/name/type":                             "string",
+		"/components/schemas/User2/properties/name/type":                            "string",
+	} {
+		if got := pointerValue(t, root, pointer); got != want {
+			t.Fatalf("%s mismatch: want %q; got %q", pointer, want, got)
+		}
+	}
+
+	schemas, err := resolvePointer(root, "/components/schemas")
+	if err != nil {
+		t.Fatalf("could not resolve schemas: %v", err)
+	}
+
+	var names []string
+	for _, pair := range mappingPairs(schemas) {
+		names = append(names, pair.key)
+	}
+
+	want := []string{"Error", "Message", "User", "ErrorsError", "User2"}
+	if len(names) != len(want) {
+		t.Fatalf("schemas mismatch: want %v; got %v", want, names)
+	}
+
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("schemas mismatch: want %v; got %v", want, names)
+		}
+	}
+}
+
+func TestBundleRefsFetchOnce(t *testing.T) {
+	t.Parallel()
+
+	dir := bundleDir(t)
+
+	var (
+		mu       sync.Mutex
+		requests = make(map[string]int)
+	)
+
+	files := http.StripPrefix("/schemas/", http.FileServer(http.Dir(filepath.Join(dir, "vendor"))))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+
+		files.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	raw := []byte(strings.ReplaceAll(bundleSpec, "https://example.com", srv.URL))
+	spec := SpecConfig{Spec: filepath.Join(dir, "api.yaml")}
+
+	if err := spec.CheckRefs(raw); err != nil {
+		t.Fatalf("could not check references: %v", err)
+	}
+
+	if _, err := spec.BundleRefs(raw); err != nil {
+		t.Fatalf("could not bundle references: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, path := range []string{"/schemas/common.yaml", "/schemas/users.yaml"} {
+		if requests[path] != 1 {
+			t.Fatalf("%s is fetched %d times; want once", path, requests[path])
+		}
+	}
+}
+
+func TestBundleRefsImports(t *testing.T) {
+	t.Parallel()
+
+	dir := bundleDir(t)
+
+	spec := SpecConfig{
+		Spec:       filepath.Join(dir, "api.yaml"),
+		RemoteRefs: ptr(false),
+		Refs:       map[string]string{"https://example.com/schemas/": filepath.Join(dir, "vendor")},
+		Imports: map[string]string{
+			filepath.Join(dir, "errors.yaml"):         "example.com/errors",
+			"https://example.com/schemas/common.yaml": "example.com/common",
+		},
+	}
+
+	root := bundleRefs(t, spec, bundleSpec)
+
+	for pointer, want := range map[string]string{
+		"/components/schemas/Message/type":          "object",
+		"/components/schemas/Message/x-go-type":     "example.com/common#Message",
+		"/components/schemas/ErrorsError/x-go-type": "example.com/errors#Failure",
+	} {
+		if got := pointerValue(t, root, pointer); got != want {
+			t.Fatalf("%s mismatch: want %q; got %q", pointer, want, got)
+		}
+	}
+
+	// Imported schemas are not walked, so author of Message is not bundled
+	// and User of user.yaml keeps its name.
+	if _, err := resolvePointer(root, "/components/schemas/User2"); err == nil {
+		t.Fatalf("unexpected schema referenced by imported one")
+	}
+
+	if got := pointerValue(t, root, "/components/schemas/User/properties/name/type"); got != "string" {
+		t.Fatalf("user mismatch: want %q; got %q", "string", got)
+	}
+}
+
+func TestBundleRefsLocal(t *testing.T) {
+	t.Parallel()
+
+	const raw = `
+openapi: 3.0.0
+info: {title: Example Service, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    Message: {$ref: '#/components/schemas/Text'}
+    Text: {type: string}
+`
+
+	spec := SpecConfig{Spec: "api.yaml", RemoteRefs: ptr(false)}
+
+	result, err := spec.BundleRefs([]byte(raw))
+	if err != nil {
+		t.Fatalf("could not bundle references: %v", err)
+	}
+
+	if string(result) != raw {
+		t.Fatalf("spec mismatch: want %q; got %q", raw, result)
+	}
+}
+
+func TestBundleRefsRecursive(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	const raw = `
+openapi: 3.0.0
+info: {title: Example Service, version: 1.0.0}
+paths:
+  /ping:
+    get:
+      parameters:
+        - $ref: 'params.yaml#/Ping'
+`
+
+	const params = `
+Ping: {$ref: '#/Pong'}
+Pong: {$ref: '#/Ping'}
+`
+
+	if err := os.WriteFile(filepath.Join(dir, "params.yaml"), []byte(params), 0o644); err != nil {
+		t.Fatalf("could not write params: %v", err)
+	}
+
+	spec := SpecConfig{Spec: filepath.Join(dir, "api.yaml"), RemoteRefs: ptr(false)}
+
+	if _, err := spec.BundleRefs([]byte(raw)); !errors.Is(err, errRecursiveRef) {
+		t.Fatalf("error mismatch: want %v; got %v", errRecursiveRef, err)
+	}
+}
+
+func TestBundleRefsKeywordNames(t *testing.T) {
+	t.Parallel()
+
+	dir := bundleDir(t)
+
+	const raw = `
+openapi: 3.0.0
+info: {title: Example Service, version: 1.0.0}
+paths:
+  /notes:
+    get:
+      responses:
+        200:
+          description: OK
+          headers:
+            x-request-id:
+              schema: {$ref: 'user.yaml#/properties/name'}
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  example: {$ref: 'user.yaml'}
+`
+
+	spec := SpecConfig{Spec: filepath.Join(dir, "api.yaml"), RemoteRefs: ptr(false)}
+
+	root := bundleRefs(t, spec, raw)
+
+	for pointer, want := range map[string]string{
+		"/paths/~1notes/get/responses/200/headers/x-request-id/schema/$ref":                         "#/components/schemas/Name",
+		"/paths/~1notes/get/responses/200/content/application~1json/schema/properties/example/$ref": "#/components/schemas/User",
+	} {
+		if got := pointerValue(t, root, pointer); got != want {
+			t.Fatalf("%s mismatch: want %q; got %q", pointer, want, got)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileName is the default name of generator config file.
+const ConfigFileName = "go-gen-http.yaml"
+
+var (
+	errInvalidConfig   = errors.New("invalid config")
+	errUnknownOverride = errors.New("override matches nothing in spec")
+	errEmptySpec       = errors.New("empty spec")
+)
+
+// ConfigFile declares clients generated by go-gen-http, so go:generate
+// directive stays a one-liner and settings are reviewed with code.
+type ConfigFile struct {
+	Specs []SpecConfig `yaml:"specs"`
+}
+
+// SpecConfig declares client generated from spec. Paths are relative to
+// config file.
+type SpecConfig struct {
+	// Spec is a path of OpenAPI document.
+	Spec string `yaml:"spec"`
+	// Client is a name of generated client; it's canonized.
+	Client string `yaml:"client"`
+	// Package is a name of generated package; lower-cased client name is
+	// used if it's not set.
+	Package string `yaml:"package"`
+	// Output is a path of generated file and OutputDir is a directory of
+	// generated files; stdout is used if neither is set.
+	Output    string `yaml:"output"`
+	OutputDir string `yaml:"outputDir"`
+
+	Server bool `yaml:"server"`
+	Mock   bool `yaml:"mock"`
+	// SubClients adds per-tag sub-clients to flat API.
+	SubClients bool `yaml:"subClients"`
+	// Fake and Validation are generated unless disabled.
+	Fake       *bool `yaml:"fake"`
+	Validation *bool `yaml:"validation"`
+
+	Filter `yaml:",inline"`
+
+	// Names override Go names as x-go-name extension does.
+	Names NameOverrides `yaml:"names"`
+	// Types map component schemas to existing Go types as x-go-type
+	// extension does, e.g. "Timestamp: time.Time".
+	Types map[string]string `yaml:"types"`
+
+	// RemoteRefs allows fetching remote references not mapped by Refs; it's
+	// on unless disabled.
+	RemoteRefs *bool `yaml:"remoteRefs"`
+	// Refs map remote URLs, or URL prefixes ending with "/", to local files
+	// or directories, so builds don't depend on network.
+	Refs map[string]string `yaml:"refs"`
+	// BasePath is a directory relative references of spec are resolved
+	// against; spec directory is used if it's not set.
+	BasePath string `yaml:"basePath"`
+	// Imports map referenced files or URLs to Go import paths of packages
+	// declaring their schemas, so shared models live in one package.
+	Imports map[string]string `yaml:"imports"`
+
+	// resolver is shared by reference checks and bundling, so referenced
+	// documents are read or fetched once.
+	resolver *refResolver
+}
+
+// NameOverrides map spec elements to Go names.
+type NameOverrides struct {
+	// Operations are keyed by operationId or method and path, e.g.
+	// "GET /messages/{id}".
+	Operations map[string]string `yaml:"operations"`
+	// Schemas are keyed by component schema name.
+	Schemas map[string]string `yaml:"schemas"`
+}
+
+// LoadConfigFile reads config file and resolves its paths relative to the
+// file directory.
+func LoadConfigFile(name string) (*ConfigFile, error) {
+	raw, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(raw))
+	decoder.KnownFields(true)
+
+	var file ConfigFile
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("could not decode config: %w", err)
+	}
+
+	if len(file.Specs) == 0 {
+		return nil, fmt.Errorf("%w: no specs declared", errInvalidConfig)
+	}
+
+	dir := filepath.Dir(name)
+
+	for i := range file.Specs {
+		spec := &file.Specs[i]
+
+		if err := spec.validate(); err != nil {
+			return nil, fmt.Errorf("spec #%d: %w", i+1, err)
+		}
+
+		spec.Spec = resolvePath(dir, spec.Spec)
+		spec.Output = resolvePath(dir, spec.Output)
+		spec.OutputDir = resolvePath(dir, spec.OutputDir)
+		spec.BasePath = resolvePath(dir, spec.BasePath)
+
+		for remote, name := range spec.Refs {
+			spec.Refs[remote] = resolvePath(dir, name)
+		}
+
+		if spec.Imports != nil {
+			imports := make(map[string]string, len(spec.Imports))
+
+			for location, importPath := range spec.Imports {
+				if !isRemote(location) {
+					location = resolvePath(dir, location)
+				}
+
+				imports[location] = importPath
+			}
+
+			spec.Imports = imports
+		}
+	}
+
+	return &file, nil
+}
+
+func (c *SpecConfig) validate() error {
+	switch {
+	case c.Spec == "":
+		return fmt.Errorf("%w: spec must be set", errInvalidConfig)
+	case c.Client == "":
+		return fmt.Errorf("%w: client must be set", errInvalidConfig)
+	case c.Output != "" && c.OutputDir != "":
+		return fmt.Errorf("%w: output and outputDir are mutually exclusive", errInvalidConfig)
+	case c.Mock && c.Fake != nil && !*c.Fake:
+		return fmt.Errorf("%w: %w", errInvalidConfig, errMockWithoutFake)
+	}
+
+	if _, err := c.Filter.compile(); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidConfig, err)
+	}
+
+	for remote := range c.Refs {
+		if !isRemote(remote) {
+			return fmt.Errorf("%w: ref %q must be http or https URL", errInvalidConfig, remote)
+		}
+	}
+
+	return nil
+}
+
+func resolvePath(dir, name string) string {
+	if name == "" || filepath.IsAbs(name) {
+		return name
+	}
+
+	return filepath.Join(dir, name)
+}
+
+// Generator returns generator configured by spec settings.
+func (c *SpecConfig) Generator() Generator {
+	return Generator{
+		Server:         c.Server,
+		Mock:           c.Mock,
+		Package:        c.Package,
+		SkipFake:       c.Fake != nil && !*c.Fake,
+		SkipValidation: c.Validation != nil && !*c.Validation,
+		Filter:         c.Filter,
+		SubClients:     c.SubClients,
+	}
+}
+
+// ApplyOverrides sets x-go-name and x-go-type extensions declared by Names
+// and Types in raw spec. Overrides matching nothing are reported, so renamed
+// operations and schemas are not silently ignored.
+func (c *SpecConfig) ApplyOverrides(raw []byte) ([]byte, error) {
+	if len(c.Names.Operations) == 0 && len(c.Names.Schemas) == 0 && len(c.Types) == 0 {
+		return raw, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("could not decode spec: %w", err)
+	}
+
+	if len(doc.Content) == 0 {
+		return nil, errEmptySpec
+	}
+
+	root := doc.Content[0]
+	applied := make(map[string]bool)
+
+	for _, item := range mappingPairs(mappingValue(root, "paths")) {
+		for _, op := range mappingPairs(item.value) {
+			if !slices.Contains(operationMethods, op.key) {
+				continue
+			}
+
+			key := strings.ToUpper(op.key) + " " + item.key
+
+			name, ok := c.Names.Operations[key]
+			if !ok {
+				key = scalarValue(mappingValue(op.value, "operationId"))
+				name, ok = c.Names.Operations[key]
+			}
+
+			if ok {
+				setMappingValue(op.value, extGoName, name)
+				applied["operation "+key] = true
+			}
+		}
+	}
+
+	schemas := mappingValue(mappingValue(root, "components"), "schemas")
+
+	for _, schema := range mappingPairs(schemas) {
+		if name, ok := c.Names.Schemas[schema.key]; ok {
+			setMappingValue(schema.value, extGoName, name)
+			applied["schema "+schema.key] = true
+		}
+
+		if typ, ok := c.Types[schema.key]; ok {
+			setMappingValue(schema.value, extGoType, typ)
+			applied["type "+schema.key] = true
+		}
+	}
+
+	var unknown []string
+
+	for kind, overrides := range map[string]map[string]string{
+		"operation": c.Names.Operations,
+		"schema":    c.Names.Schemas,
+		"type":      c.Types,
+	} {
+		for key := range overrides {
+			if !applied[kind+" "+key] {
+				unknown = append(unknown, fmt.Sprintf("%s %q", kind, key))
+			}
+		}
+	}
+
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+
+		return nil, fmt.Errorf("%w: %s", errUnknownOverride, strings.Join(unknown, ", "))
+	}
+
+	result, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode spec: %w", err)
+	}
+
+	return result, nil
+}
+
+// Path item keys declaring operations.
+//
+//nolint:gochecknoglobals // Read-only list.
+var operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+type mappingPair struct {
+	key   string
+	value *yaml.Node
+}
+
+// mappingPairs returns keys and values of YAML mapping node.
+func mappingPairs(node *yaml.Node) []mappingPair {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	result := make([]mappingPair, 0, len(node.Content)/2)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		result = append(result, mappingPair{key: node.Content[i].Value, value: node.Content[i+1]})
+	}
+
+	return result
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for _, pair := range mappingPairs(node) {
+		if pair.key == key {
+			return pair.value
+		}
+	}
+
+	return nil
+}
+
+func scalarValue(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+
+	return node.Value
+}
+
+func setMappingValue(node *yaml.Node, key, value string) {
+	scalar := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = scalar
+			return
+		}
+	}
+
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, scalar)
+}
@@ -0,0 +1,295 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+)
+
+func TestLoadConfigFile(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		config  string
+		want    *ConfigFile
+		wantErr string
+	}{
+		{
+			name: "specs",
+			config: `
+specs:
+  - spec: api.yaml
+    client: MessageService
+    outputDir: messageservice
+    server: true
+    subClients: true
+    fake: true
+    validation: false
+    include: {tags: [messages], paths: [/messages/**]}
+    exclude: {operations: [deleteMessage, GET /health], operationIds: [^admin], deprecated: true}
+    names:
+      operations: {getMessage: Get}
+      schemas: {message_v2: Message}
+    types: {Timestamp: time.Time}
+    remoteRefs: false
+    refs: {https://example.com/schemas/: vendor/schemas}
+    basePath: specs
+    imports: {vendor/schemas/common.yaml: example.com/common, https://example.com/users.yaml: example.com/users}
+  - spec: /specs/users.yaml
+    client: UserService
+    package: users
+    output: users.go
+`,
+			want: &ConfigFile{Specs: []SpecConfig{
+				{
+					Spec:       "testdata/api.yaml",
+					Client:     "MessageService",
+					OutputDir:  "testdata/messageservice",
+					Server:     true,
+					SubClients: true,
+					Fake:       ptr(true),
+					Validation: ptr(false),
+					Filter: Filter{
+						Include: Selector{Tags: []string{"messages"}, Paths: []string{"/messages/**"}},
+						Exclude: Selector{
+							Operations:   []string{"deleteMessage", "GET /health"},
+							OperationIDs: []string{"^admin"},
+							Deprecated:   true,
+						},
+					},
+					Names: NameOverrides{
+						Operations: map[string]string{"getMessage": "Get"},
+						Schemas:    map[string]string{"message_v2": "Message"},
+					},
+					Types:      map[string]string{"Timestamp": "time.Time"},
+					RemoteRefs: ptr(false),
+					Refs:       map[string]string{"https://example.com/schemas/": "testdata/vendor/schemas"},
+					BasePath:   "testdata/specs",
+					Imports: map[string]string{
+						"testdata/vendor/schemas/common.yaml": "example.com/common",
+						"https://example.com/users.yaml":      "example.com/users",
+					},
+				},
+				{
+					Spec:    "/specs/users.yaml",
+					Client:  "UserService",
+					Package: "users",
+					Output:  "testdata/users.go",
+				},
+			}},
+		},
+		{
+			name:    "unknown field",
+			config:  "specs: [{spec: api.yaml, client: A, outptu: a.go}]",
+			wantErr: "field outptu not found",
+		},
+		{
+			name:    "no specs",
+			config:  "specs: []",
+			wantErr: "no specs declared",
+		},
+		{
+			name:    "no client",
+			config:  "specs: [{spec: api.yaml}]",
+			wantErr: "spec #1: invalid config: client must be set",
+		},
+		{
+			name:    "both outputs",
+			config:  "specs: [{spec: api.yaml, client: A, output: a.go, outputDir: a}]",
+			wantErr: "output and outputDir are mutually exclusive",
+		},
+		{
+			name:    "invalid pattern",
+			config:  "specs: [{spec: api.yaml, client: A, include: {operationIds: ['(get']}}]",
+			wantErr: `could not compile include: invalid operationId pattern "(get"`,
+		},
+		{
+			name:    "local ref",
+			config:  "specs: [{spec: api.yaml, client: A, refs: {common.yaml: vendor/common.yaml}}]",
+			wantErr: `ref "common.yaml" must be http or https URL`,
+		},
+		{
+			name:    "mock without fake",
+			config:  "specs: [{spec: api.yaml, client: A, mock: true, fake: false}]",
+			wantErr: "mock requires fake",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			name := filepath.Join(dir, ConfigFileName)
+
+			if err := os.WriteFile(name, []byte(c.config), 0o644); err != nil {
+				t.Fatalf("could not write config: %v", err)
+			}
+
+			got, err := LoadConfigFile(name)
+			if c.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+					t.Fatalf("error mismatch: want %q; got %v", c.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Relative paths are resolved against temporary directory.
+			for i := range c.want.Specs {
+				spec := &c.want.Specs[i]
+				spec.Spec = strings.Replace(spec.Spec, "testdata", dir, 1)
+				spec.Output = strings.Replace(spec.Output, "testdata", dir, 1)
+				spec.OutputDir = strings.Replace(spec.OutputDir, "testdata", dir, 1)
+				spec.BasePath = strings.Replace(spec.BasePath, "testdata", dir, 1)
+
+				for remote, name := range spec.Refs {
+					spec.Refs[remote] = strings.Replace(name, "testdata", dir, 1)
+				}
+
+				if spec.Imports != nil {
+					imports := make(map[string]string, len(spec.Imports))
+					for location, importPath := range spec.Imports {
+						imports[strings.Replace(location, "testdata", dir, 1)] = importPath
+					}
+
+					spec.Imports = imports
+				}
+			}
+
+			if !reflect.DeepEqual(c.want, got) {
+				t.Fatalf("config mismatch:\nwant %+v\ngot  %+v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestSpecConfigGenerator(t *testing.T) {
+	t.Parallel()
+
+	spec := SpecConfig{Server: true, Package: "api", Fake: ptr(false), Validation: ptr(true)}
+
+	want := Generator{Server: true, Package: "api", SkipFake: true}
+	if got := spec.Generator(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("generator mismatch: want %+v; got %+v", want, got)
+	}
+}
+
+const overridesSpec = `
+openapi: 3.0.0
+info: {title: Example Service, version: 1.0.0}
+paths:
+  /messages/{id}:
+    get:
+      operationId: getMessage
+      parameters:
+        - {in: path, name: id, required: true, schema: {type: string}}
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/message_v2'}
+  /messages:
+    post:
+      responses: {}
+components:
+  schemas:
+    message_v2:
+      type: object
+      properties:
+        created: {$ref: '#/components/schemas/Timestamp'}
+    Timestamp: {type: string, format: date-time}
+`
+
+func TestApplyOverrides(t *testing.T) {
+	t.Parallel()
+
+	spec := SpecConfig{
+		Names: NameOverrides{
+			Operations: map[string]string{"getMessage": "Get", "POST /messages": "Create"},
+			Schemas:    map[string]string{"message_v2": "Message"},
+		},
+		Types: map[string]string{"Timestamp": "time.Time"},
+	}
+
+	raw, err := spec.ApplyOverrides([]byte(overridesSpec))
+	if err != nil {
+		t.Fatalf("could not apply overrides: %v", err)
+	}
+
+	doc, err := libopenapi.NewDocument(raw)
+	if err != nil {
+		t.Fatalf("could not parse spec: %v", err)
+	}
+
+	model, errs := doc.BuildV3Model()
+	if len(errs) > 0 {
+		t.Fatalf("could not build model: %v", errs)
+	}
+
+	ctx := context.Background()
+
+	paths, err := CollectPaths(ctx, model.Model.Paths, Filter{})
+	if err != nil {
+		t.Fatalf("could not collect paths: %v", err)
+	}
+
+	if got := []string{paths[0].CanonicalName, paths[1].CanonicalName}; !reflect.DeepEqual(got, []string{"Get", "Create"}) {
+		t.Fatalf("operation names mismatch: %v", got)
+	}
+
+	if got := paths[0].Response.Codes[0].Name; got != "Message" {
+		t.Fatalf("response type mismatch: want %q; got %q", "Message", got)
+	}
+
+	properties := collectProperties(ctx, model.Model.Components.Schemas.GetOrZero("message_v2").Schema(), "")
+	if got := properties[0].Type; got != "time.Time" {
+		t.Fatalf("property type mismatch: want %q; got %q", "time.Time", got)
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+
+		spec := SpecConfig{
+			Names: NameOverrides{Operations: map[string]string{"listMessages": "List", "get /messages/{id}": "Get"}},
+			Types: map[string]string{"Time": "time.Time"},
+		}
+
+		_, err := spec.ApplyOverrides([]byte(overridesSpec))
+		if !errors.Is(err, errUnknownOverride) {
+			t.Fatalf("error mismatch: want %v; got %v", errUnknownOverride, err)
+		}
+
+		const want = `operation "get /messages/{id}", operation "listMessages", type "Time"`
+		if !strings.HasSuffix(err.Error(), want) {
+			t.Fatalf("message mismatch: want %q; got %q", want, err.Error())
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		t.Parallel()
+
+		raw, err := (&SpecConfig{}).ApplyOverrides([]byte(overridesSpec))
+		if err != nil || string(raw) != overridesSpec {
+			t.Fatalf("spec is modified without overrides: %v", err)
+		}
+	})
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
@@ -0,0 +1,205 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	// diffContext is a number of unchanged lines around changes.
+	diffContext = 3
+	// maxDiffCells limits memory of line matching; bigger changes are shown
+	// as removal of old lines followed by addition of new ones.
+	maxDiffCells = 1 << 22
+)
+
+// diffLine is a line of old or new text; kind is ' ' for unchanged lines,
+// '-' for removed and '+' for added ones.
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// unifiedDiff returns unified diff turning old into new; it's empty if they
+// are equal. Labels name old and new texts, e.g. "/dev/null" for missing
+// file.
+func unifiedDiff(oldLabel, newLabel string, old, new []byte) []byte {
+	if bytes.Equal(old, new) {
+		return nil
+	}
+
+	lines := diffLines(splitLines(old), splitLines(new))
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", oldLabel, newLabel)
+
+	for start := 0; start < len(lines); {
+		first := nextChange(lines, start)
+		if first == len(lines) {
+			break
+		}
+
+		// Changes separated by up to two contexts share a hunk.
+		last := first
+		for next := nextChange(lines, last+1); next < len(lines) && next-last-1 <= 2*diffContext; {
+			last = next
+			next = nextChange(lines, last+1)
+		}
+
+		from := max(0, first-diffContext)
+		to := min(len(lines), last+diffContext+1)
+
+		writeHunk(&buf, lines, from, to)
+
+		start = to
+	}
+
+	return buf.Bytes()
+}
+
+// splitLines splits text into lines keeping line endings.
+func splitLines(text []byte) []string {
+	lines := strings.SplitAfter(string(text), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines matches lines of old and new texts by their longest common
+// subsequence.
+func diffLines(old, new []string) []diffLine {
+	prefix := 0
+	for prefix < len(old) && prefix < len(new) && old[prefix] == new[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(new)-prefix &&
+		old[len(old)-1-suffix] == new[len(new)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]diffLine, 0, len(old)+len(new))
+
+	for _, line := range old[:prefix] {
+		result = append(result, diffLine{kind: ' ', text: line})
+	}
+
+	result = append(result, diffMiddle(old[prefix:len(old)-suffix], new[prefix:len(new)-suffix])...)
+
+	for _, line := range old[len(old)-suffix:] {
+		result = append(result, diffLine{kind: ' ', text: line})
+	}
+
+	return result
+}
+
+func diffMiddle(old, new []string) []diffLine {
+	result := make([]diffLine, 0, len(old)+len(new))
+
+	if len(old)*len(new) > maxDiffCells {
+		for _, line := range old {
+			result = append(result, diffLine{kind: '-', text: line})
+		}
+
+		for _, line := range new {
+			result = append(result, diffLine{kind: '+', text: line})
+		}
+
+		return result
+	}
+
+	// lcs[i][j] is a length of common subsequence of old[i:] and new[j:].
+	width := len(new) + 1
+	lcs := make([]int32, (len(old)+1)*width)
+
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(new) - 1; j >= 0; j-- {
+			if old[i] == new[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else {
+				lcs[i*width+j] = max(lcs[(i+1)*width+j], lcs[i*width+j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(old) || j < len(new) {
+		switch {
+		case i < len(old) && j < len(new) && old[i] == new[j]:
+			result = append(result, diffLine{kind: ' ', text: old[i]})
+			i++
+			j++
+		case j == len(new) || (i < len(old) && lcs[(i+1)*width+j] >= lcs[i*width+j+1]):
+			result = append(result, diffLine{kind: '-', text: old[i]})
+			i++
+		default:
+			result = append(result, diffLine{kind: '+', text: new[j]})
+			j++
+		}
+	}
+
+	return result
+}
+
+// nextChange returns index of the first changed line starting from start.
+func nextChange(lines []diffLine, start int) int {
+	for i := start; i < len(lines); i++ {
+		if lines[i].kind != ' ' {
+			return i
+		}
+	}
+
+	return len(lines)
+}
+
+func writeHunk(buf *bytes.Buffer, lines []diffLine, from, to int) {
+	oldStart, newStart := 0, 0
+
+	for _, line := range lines[:from] {
+		if line.kind != '+' {
+			oldStart++
+		}
+
+		if line.kind != '-' {
+			newStart++
+		}
+	}
+
+	oldCount, newCount := 0, 0
+
+	for _, line := range lines[from:to] {
+		if line.kind != '+' {
+			oldCount++
+		}
+
+		if line.kind != '-' {
+			newCount++
+		}
+	}
+
+	// Ranges start with the first line of hunk or with the line preceding
+	// empty one.
+	if oldCount > 0 {
+		oldStart++
+	}
+
+	if newCount > 0 {
+		newStart++
+	}
+
+	fmt.Fprintf(buf, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+
+	for _, line := range lines[from:to] {
+		_ = buf.WriteByte(line.kind)
+		_, _ = buf.WriteString(line.text)
+
+		if !strings.HasSuffix(line.text, "\n") {
+			_, _ = buf.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
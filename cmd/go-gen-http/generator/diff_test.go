@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	lines := func(values ...string) []byte {
+		return []byte(strings.Join(values, "\n") + "\n")
+	}
+
+	cases := []struct {
+		name string
+		old  []byte
+		new  []byte
+		want string
+	}{
+		{
+			name: "equal",
+			old:  lines("a", "b"),
+			new:  lines("a", "b"),
+		},
+		{
+			name: "separate hunks",
+			old:  lines("1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"),
+			new:  lines("0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "eleven", "12"),
+			want: "--- a\n+++ b\n" +
+				"@@ -1,3 +1,4 @@\n+0\n 1\n 2\n 3\n" +
+				"@@ -8,5 +9,5 @@\n 8\n 9\n 10\n-11\n+eleven\n 12\n",
+		},
+		{
+			name: "shared hunk",
+			old:  lines("1", "2", "3", "4", "5", "6", "7", "8"),
+			new:  lines("x", "2", "3", "4", "5", "6", "7", "y"),
+			want: "--- a\n+++ b\n" +
+				"@@ -1,8 +1,8 @@\n-1\n+x\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+y\n",
+		},
+		{
+			name: "new file",
+			new:  lines("a"),
+			want: "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+a\n",
+		},
+		{
+			name: "missing newline",
+			old:  []byte("a\nb"),
+			new:  []byte("a\nb\n"),
+			want: "--- a\n+++ b\n" +
+				"@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+	}
+
+	for _, c := range cases {
+		if got := string(unifiedDiff("a", "b", c.old, c.new)); got != c.want {
+			t.Fatalf("%s mismatch:\nwant\n%s\ngot\n%s", c.name, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3high "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// Example is a response served by generated mock.
+type Example struct {
+	Code int
+	// JSON is an encoded response body.
+	JSON string
+}
+
+// collectExample returns example of the first successful JSON response. It's
+// taken from media example or examples; otherwise it's synthesized from the
+// schema. Nil is returned if operation has no successful JSON response.
+func collectExample(ctx context.Context, responses *v3high.Responses) (*Example, error) {
+	if responses == nil {
+		return nil, nil
+	}
+
+	for code := range orderedmap.Iterate(ctx, responses.Codes) {
+		httpcode, err := strconv.Atoi(code.Key())
+		if err != nil {
+			return nil, fmt.Errorf("could not parse code %q: %w", code.Key(), err)
+		}
+
+		if httpcode >= http.StatusBadRequest || code.Value().Content == nil {
+			continue
+		}
+
+		media := code.Value().Content.GetOrZero("application/json")
+		if media == nil || media.Schema == nil {
+			continue
+		}
+
+		value, err := mediaExample(ctx, media)
+		if err != nil {
+			return nil, fmt.Errorf("invalid response %q example: %w", code.Key(), err)
+		}
+
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("could not encode response %q example: %w", code.Key(), err)
+		}
+
+		return &Example{Code: httpcode, JSON: string(raw)}, nil
+	}
+
+	return nil, nil
+}
+
+func mediaExample(ctx context.Context, media *v3high.MediaType) (any, error) {
+	if media.Example != nil {
+		return decodeNode(media.Example)
+	}
+
+	if pair := orderedmap.First(media.Examples); pair != nil && pair.Value().Value != nil {
+		return decodeNode(pair.Value().Value)
+	}
+
+	synthesizer := exampleSynthesizer{visiting: make(map[string]bool)}
+
+	return synthesizer.synthesize(ctx, media.Schema)
+}
+
+// decodeNode decodes YAML node to value encodable as JSON.
+func decodeNode(node *yaml.Node) (any, error) {
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, fmt.Errorf("could not decode example: %w", err)
+	}
+
+	return value, nil
+}
+
+// Examples of string formats.
+//
+//nolint:gochecknoglobals // Read-only lookup table.
+var formatExamples = map[string]string{
+	"date":      "2006-01-02",
+	"date-time": "2006-01-02T15:04:05Z",
+	"email":     "user@example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"uri":       "https://example.com",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+}
+
+type exampleSynthesizer struct {
+	// visiting breaks recursive schemas; properties referring to schema
+	// being synthesized are omitted.
+	visiting map[string]bool
+}
+
+// synthesize returns schema example, default or first enum value if they are
+// declared, and value built from schema type otherwise.
+func (s *exampleSynthesizer) synthesize(ctx context.Context, proxy *base.SchemaProxy) (any, error) {
+	if proxy == nil {
+		return nil, nil
+	}
+
+	schema := proxy.Schema()
+	if schema == nil {
+		return nil, nil
+	}
+
+	switch {
+	case schema.Example != nil:
+		return decodeNode(schema.Example)
+	case len(schema.Examples) > 0:
+		return decodeNode(schema.Examples[0])
+	case schema.Default != nil:
+		return decodeNode(schema.Default)
+	case len(schema.Enum) > 0:
+		return decodeNode(schema.Enum[0])
+	}
+
+	id := schemaID(proxy)
+
+	s.visiting[id] = true
+	defer delete(s.visiting, id)
+
+	if len(schema.AllOf) > 0 {
+		return s.synthesizeAllOf(ctx, schema.AllOf)
+	}
+
+	if len(schema.OneOf) > 0 {
+		return s.synthesize(ctx, schema.OneOf[0])
+	}
+
+	if len(schema.AnyOf) > 0 {
+		return s.synthesize(ctx, schema.AnyOf[0])
+	}
+
+	typ := ""
+	if len(schema.Type) > 0 {
+		typ = schema.Type[0]
+	}
+
+	switch {
+	case typ == "object" || schema.Properties != nil:
+		return s.synthesizeObject(ctx, schema)
+	case typ == "array":
+		return s.synthesizeArray(ctx, schema)
+	case typ == "string":
+		if value, ok := formatExamples[schema.Format]; ok {
+			return value, nil
+		}
+
+		return "string", nil
+	case typ == "integer":
+		if schema.Minimum != nil {
+			return int64(*schema.Minimum), nil
+		}
+
+		return 0, nil
+	case typ == "number":
+		if schema.Minimum != nil {
+			return *schema.Minimum, nil
+		}
+
+		return 0, nil
+	case typ == "boolean":
+		return false, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (s *exampleSynthesizer) synthesizeObject(ctx context.Context, schema *base.Schema) (any, error) {
+	result := make(map[string]any, orderedmap.Len(schema.Properties))
+
+	for property := range orderedmap.Iterate(ctx, schema.Properties) {
+		if s.visiting[schemaID(property.Value())] {
+			continue
+		}
+
+		value, err := s.synthesize(ctx, property.Value())
+		if err != nil {
+			return nil, fmt.Errorf("invalid property %q: %w", property.Key(), err)
+		}
+
+		result[property.Key()] = value
+	}
+
+	return result, nil
+}
+
+func (s *exampleSynthesizer) synthesizeArray(ctx context.Context, schema *base.Schema) (any, error) {
+	if schema.Items == nil || !schema.Items.IsA() {
+		return []any{}, nil
+	}
+
+	if s.visiting[schemaID(schema.Items.A)] {
+		return []any{}, nil
+	}
+
+	item, err := s.synthesize(ctx, schema.Items.A)
+	if err != nil {
+		return nil, fmt.Errorf("invalid items: %w", err)
+	}
+
+	count := max(1, int(resolveptr(schema.MinItems)))
+	result := make([]any, 0, count)
+
+	for range count {
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+// synthesizeAllOf merges objects synthesized from schemas.
+func (s *exampleSynthesizer) synthesizeAllOf(ctx context.Context, schemas []*base.SchemaProxy) (any, error) {
+	result := make(map[string]any)
+
+	for _, proxy := range schemas {
+		value, err := s.synthesize(ctx, proxy)
+		if err != nil {
+			return nil, err
+		}
+
+		object, ok := value.(map[string]any)
+		if !ok {
+			return value, nil
+		}
+
+		for key, value := range object {
+			result[key] = value
+		}
+	}
+
+	return result, nil
+}
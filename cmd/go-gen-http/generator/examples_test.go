@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+)
+
+const examplesSpec = `
+openapi: 3.0.0
+info: {title: Example Service, version: 1.0.0}
+paths:
+  /example:
+    get:
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Node'}
+              example: {name: root}
+  /examples:
+    get:
+      responses:
+        201:
+          description: Created
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Node'}
+              examples:
+                first: {value: {name: first}}
+                second: {value: {name: second}}
+  /synthesized:
+    get:
+      responses:
+        404:
+          description: Not found
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Node'}
+        200:
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Node'}
+  /failure:
+    get:
+      responses:
+        500:
+          description: Internal error
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Node'}
+components:
+  schemas:
+    Node:
+      type: object
+      properties:
+        name: {type: string, example: node}
+        id: {type: string, format: uuid}
+        kind: {type: string, enum: [leaf, branch]}
+        weight: {type: number, minimum: 1.5}
+        depth: {type: integer, default: 3}
+        leaf: {type: boolean}
+        children:
+          type: array
+          minItems: 2
+          items: {$ref: '#/components/schemas/Node'}
+        tags:
+          type: array
+          items: {type: string}
+`
+
+func TestCollectExample(t *testing.T) {
+	t.Parallel()
+
+	doc, err := libopenapi.NewDocument([]byte(examplesSpec))
+	if err != nil {
+		t.Fatalf("could not parse spec: %v", err)
+	}
+
+	model, errs := doc.BuildV3Model()
+	if len(errs) > 0 {
+		t.Fatalf("could not build model: %v", errs)
+	}
+
+	paths, err := CollectPaths(context.Background(), model.Model.Paths, Filter{})
+	if err != nil {
+		t.Fatalf("could not collect paths: %v", err)
+	}
+
+	want := map[string]*Example{
+		"GETExample":  {Code: 200, JSON: `{"name":"root"}`},
+		"GETExamples": {Code: 201, JSON: `{"name":"first"}`},
+		"GETSynthesized": {
+			Code: 200,
+			JSON: `{"children":[],"depth":3,"id":"00000000-0000-0000-0000-000000000000","kind":"leaf","leaf":false,` +
+				`"name":"node","tags":["string"],"weight":1.5}`,
+		},
+		"GETFailure": nil,
+	}
+
+	for _, path := range paths {
+		got := path.Response.Example
+
+		switch expected := want[path.CanonicalName]; {
+		case expected == nil && got != nil:
+			t.Fatalf("%s example mismatch: want nil; got %+v", path.CanonicalName, *got)
+		case expected == nil:
+		case got == nil || *got != *expected:
+			t.Fatalf("%s example mismatch: want %+v; got %+v", path.CanonicalName, *expected, got)
+		}
+	}
+}
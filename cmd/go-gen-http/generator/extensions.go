@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// Spec extensions controlling generated method defaults. Both can be set on
+// the document root to apply to all methods and on operations.
+const (
+	// extTimeout is a method timeout, e.g. "250ms".
+	extTimeout = "x-timeout"
+	// extRetries is a number of retries after the first failed attempt.
+	extRetries = "x-retries"
+)
+
+// extSensitive marks parameter or schema property holding secret, e.g. a
+// token; its values are redacted in logs. Properties with "format: password"
+// are sensitive as well.
+const extSensitive = "x-sensitive"
+
+const formatPassword = "password"
+
+// extGoName overrides generated Go name of operation, schema, property or
+// parameter.
+const extGoName = "x-go-name"
+
+// extGoType replaces generated type of schema or property with existing Go
+// type, e.g. "time.Time" or "github.com/google/uuid.UUID"; the package of
+// qualified type is imported.
+const extGoType = "x-go-type"
+
+// MethodDefaults are method settings declared with spec extensions.
+type MethodDefaults struct {
+	Timeout time.Duration
+	Retries uint
+}
+
+// IsZero reports whether defaults are not set.
+func (d MethodDefaults) IsZero() bool {
+	return d == MethodDefaults{}
+}
+
+// Attempts returns total number of attempts including the first one.
+func (d MethodDefaults) Attempts() uint {
+	return d.Retries + 1
+}
+
+func collectMethodDefaults(
+	extensions *orderedmap.Map[string, *yaml.Node],
+) (MethodDefaults, error) {
+	var defaults MethodDefaults
+
+	if extensions == nil {
+		return defaults, nil
+	}
+
+	if node := extensions.GetOrZero(extTimeout); node != nil {
+		var raw string
+		if err := node.Decode(&raw); err != nil {
+			return defaults, fmt.Errorf("could not decode %s: %w", extTimeout, err)
+		}
+
+		timeout, err := time.ParseDuration(raw)
+		if err != nil {
+			return defaults, fmt.Errorf("could not parse %s: %w", extTimeout, err)
+		}
+
+		if timeout < 0 {
+			return defaults, fmt.Errorf("%s must not be negative: %q", extTimeout, raw)
+		}
+
+		defaults.Timeout = timeout
+	}
+
+	if node := extensions.GetOrZero(extRetries); node != nil {
+		if err := node.Decode(&defaults.Retries); err != nil {
+			return defaults, fmt.Errorf("could not decode %s: %w", extRetries, err)
+		}
+	}
+
+	return defaults, nil
+}
+
+func isSensitive(
+	extensions *orderedmap.Map[string, *yaml.Node],
+	format string,
+) (bool, error) {
+	if format == formatPassword {
+		return true, nil
+	}
+
+	if extensions == nil {
+		return false, nil
+	}
+
+	node := extensions.GetOrZero(extSensitive)
+	if node == nil {
+		return false, nil
+	}
+
+	var sensitive bool
+	if err := node.Decode(&sensitive); err != nil {
+		return false, fmt.Errorf("could not decode %s: %w", extSensitive, err)
+	}
+
+	return sensitive, nil
+}
+
+// extensionValue returns scalar value of extension or empty string if it's
+// not set.
+func extensionValue(extensions *orderedmap.Map[string, *yaml.Node], key string) string {
+	if extensions == nil {
+		return ""
+	}
+
+	node := extensions.GetOrZero(key)
+	if node == nil {
+		return ""
+	}
+
+	return node.Value
+}
@@ -0,0 +1,134 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+func extensions(t *testing.T, raw map[string]string) *orderedmap.Map[string, *yaml.Node] {
+	t.Helper()
+
+	result := orderedmap.New[string, *yaml.Node]()
+
+	for key, value := range raw {
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(value), &node); err != nil {
+			t.Fatalf("could not parse %q: %v", value, err)
+		}
+
+		// Unmarshal returns document node; extension values are its content.
+		result.Set(key, node.Content[0])
+	}
+
+	return result
+}
+
+func TestCollectMethodDefaults(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		extensions map[string]string
+		want       MethodDefaults
+		wantErr    bool
+	}{
+		{
+			name:       "no extensions",
+			extensions: nil,
+			want:       MethodDefaults{},
+		},
+		{
+			name: "timeout and retries",
+			extensions: map[string]string{
+				"x-timeout": "250ms",
+				"x-retries": "2",
+			},
+			want: MethodDefaults{
+				Timeout: time.Millisecond * 250,
+				Retries: 2,
+			},
+		},
+		{
+			name: "unrelated extension",
+			extensions: map[string]string{
+				"x-go-name": "Foo",
+			},
+			want: MethodDefaults{},
+		},
+		{
+			name:       "invalid timeout",
+			extensions: map[string]string{"x-timeout": "soon"},
+			wantErr:    true,
+		},
+		{
+			name:       "negative timeout",
+			extensions: map[string]string{"x-timeout": "-1s"},
+			wantErr:    true,
+		},
+		{
+			name:       "negative retries",
+			extensions: map[string]string{"x-retries": "-1"},
+			wantErr:    true,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			var ext *orderedmap.Map[string, *yaml.Node]
+			if c.extensions != nil {
+				ext = extensions(t, c.extensions)
+			}
+
+			got, err := collectMethodDefaults(ext)
+			if c.wantErr != (err != nil) {
+				t.Fatalf("error mismatch: want error %t; got %v", c.wantErr, err)
+			}
+
+			if !c.wantErr && c.want != got {
+				t.Fatalf("mismatch: want %+v; got %+v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestIsSensitive(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		extensions map[string]string
+		format     string
+		want       bool
+		wantErr    bool
+	}{
+		{name: "plain", want: false},
+		{name: "password format", format: "password", want: true},
+		{name: "marked", extensions: map[string]string{extSensitive: "true"}, want: true},
+		{name: "unmarked", extensions: map[string]string{extSensitive: "false"}, want: false},
+		{name: "invalid", extensions: map[string]string{extSensitive: "maybe"}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := isSensitive(extensions(t, c.extensions), c.format)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("error mismatch: want error %v; got %v", c.wantErr, err)
+			}
+
+			if got != c.want {
+				t.Fatalf("mismatch: want %v; got %v", c.want, got)
+			}
+		})
+	}
+}
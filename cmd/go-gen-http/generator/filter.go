@@ -0,0 +1,264 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3high "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// Selector matches operations meeting any of its criteria.
+type Selector struct {
+	// Tags match operations having any of them.
+	Tags []string `yaml:"tags"`
+	// Operations match operations by operationId or by method and path, e.g.
+	// "GET /messages/{id}".
+	Operations []string `yaml:"operations"`
+	// Paths are globs matching operation path: "*" matches any part of a
+	// path segment and "**" matches any number of segments, e.g.
+	// "/admin/**".
+	Paths []string `yaml:"paths"`
+	// OperationIDs are regular expressions matching operationId.
+	OperationIDs []string `yaml:"operationIds"`
+	// Deprecated matches deprecated operations.
+	Deprecated bool `yaml:"deprecated"`
+}
+
+// IsZero reports whether selector has no criteria.
+func (s Selector) IsZero() bool {
+	return len(s.Tags) == 0 && len(s.Operations) == 0 && len(s.Paths) == 0 &&
+		len(s.OperationIDs) == 0 && !s.Deprecated
+}
+
+// Filter selects generated operations: operations matching Include, or all
+// operations if it's empty, except ones matching Exclude. Only component
+// schemas referenced by selected operations are generated if filter is set.
+type Filter struct {
+	Include Selector `yaml:"include"`
+	Exclude Selector `yaml:"exclude"`
+}
+
+// IsZero reports whether filter selects all operations.
+func (f Filter) IsZero() bool {
+	return f.Include.IsZero() && f.Exclude.IsZero()
+}
+
+// operationMatcher is a compiled Selector.
+type operationMatcher struct {
+	selector     Selector
+	paths        []*regexp.Regexp
+	operationIDs []*regexp.Regexp
+}
+
+func (s Selector) compile() (*operationMatcher, error) {
+	matcher := &operationMatcher{selector: s}
+
+	for _, glob := range s.Paths {
+		matcher.paths = append(matcher.paths, compileGlob(glob))
+	}
+
+	for _, pattern := range s.OperationIDs {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid operationId pattern %q: %w", pattern, err)
+		}
+
+		matcher.operationIDs = append(matcher.operationIDs, re)
+	}
+
+	return matcher, nil
+}
+
+// compileGlob returns regular expression matching the whole path by glob.
+func compileGlob(glob string) *regexp.Regexp {
+	sb := &strings.Builder{}
+	_, _ = sb.WriteString("^")
+
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**"):
+			_, _ = sb.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			_, _ = sb.WriteString("[^/]*")
+		default:
+			_, _ = sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+
+	_, _ = sb.WriteString("$")
+
+	return regexp.MustCompile(sb.String())
+}
+
+func (m *operationMatcher) match(method, url string, op *v3high.Operation) bool {
+	for _, tag := range op.Tags {
+		if slices.Contains(m.selector.Tags, tag) {
+			return true
+		}
+	}
+
+	for _, operation := range m.selector.Operations {
+		if op.OperationId != "" && operation == op.OperationId {
+			return true
+		}
+
+		opMethod, opURL, ok := strings.Cut(operation, " ")
+		if ok && strings.EqualFold(opMethod, method) && opURL == url {
+			return true
+		}
+	}
+
+	for _, re := range m.paths {
+		if re.MatchString(url) {
+			return true
+		}
+	}
+
+	for _, re := range m.operationIDs {
+		if op.OperationId != "" && re.MatchString(op.OperationId) {
+			return true
+		}
+	}
+
+	return m.selector.Deprecated && resolveptr(op.Deprecated)
+}
+
+// filterMatcher is a compiled Filter.
+type filterMatcher struct {
+	include *operationMatcher
+	exclude *operationMatcher
+}
+
+func (f Filter) compile() (*filterMatcher, error) {
+	include, err := f.Include.compile()
+	if err != nil {
+		return nil, fmt.Errorf("could not compile include: %w", err)
+	}
+
+	exclude, err := f.Exclude.compile()
+	if err != nil {
+		return nil, fmt.Errorf("could not compile exclude: %w", err)
+	}
+
+	return &filterMatcher{include: include, exclude: exclude}, nil
+}
+
+// selects reports whether operation is selected.
+func (m *filterMatcher) selects(method, url string, op *v3high.Operation) bool {
+	if !m.include.selector.IsZero() && !m.include.match(method, url, op) {
+		return false
+	}
+
+	return !m.exclude.match(method, url, op)
+}
+
+// referencedSchemas returns keys of component schemas referenced by
+// operations of paths directly or through other schemas.
+func referencedSchemas(ctx context.Context, paths []Path) map[string]bool {
+	result := make(map[string]bool)
+
+	var visit func(proxy *base.SchemaProxy)
+
+	visit = func(proxy *base.SchemaProxy) {
+		if proxy == nil {
+			return
+		}
+
+		if reference := proxy.GetReference(); reference != "" {
+			key := reference[strings.LastIndex(reference, "/")+1:]
+			if result[key] {
+				return
+			}
+
+			result[key] = true
+		}
+
+		schema := proxy.Schema()
+		if schema == nil {
+			return
+		}
+
+		for property := range orderedmap.Iterate(ctx, schema.Properties) {
+			visit(property.Value())
+		}
+
+		for _, proxies := range [][]*base.SchemaProxy{schema.AllOf, schema.OneOf, schema.AnyOf} {
+			for _, proxy := range proxies {
+				visit(proxy)
+			}
+		}
+
+		if schema.Items != nil && schema.Items.IsA() {
+			visit(schema.Items.A)
+		}
+
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.IsA() {
+			visit(schema.AdditionalProperties.A)
+		}
+	}
+
+	visitContent := func(content *orderedmap.Map[string, *v3high.MediaType]) {
+		for media := range orderedmap.Iterate(ctx, content) {
+			visit(media.Value().Schema)
+		}
+	}
+
+	for _, path := range paths {
+		op := path.operation
+		if op == nil {
+			continue
+		}
+
+		for _, param := range op.Parameters {
+			visit(param.Schema)
+		}
+
+		if op.RequestBody != nil {
+			visitContent(op.RequestBody.Content)
+		}
+
+		if op.Responses == nil {
+			continue
+		}
+
+		if op.Responses.Default != nil {
+			visitContent(op.Responses.Default.Content)
+		}
+
+		for code := range orderedmap.Iterate(ctx, op.Responses.Codes) {
+			visitContent(code.Value().Content)
+		}
+	}
+
+	return result
+}
+
+// filterComponents returns copy of components with schemas limited to keys.
+func filterComponents(
+	ctx context.Context,
+	components *v3high.Components,
+	keys map[string]bool,
+) *v3high.Components {
+	if components == nil || components.Schemas == nil {
+		return components
+	}
+
+	schemas := orderedmap.New[string, *base.SchemaProxy]()
+
+	for pair := range orderedmap.Iterate(ctx, components.Schemas) {
+		if keys[pair.Key()] {
+			schemas.Set(pair.Key(), pair.Value())
+		}
+	}
+
+	result := *components
+	result.Schemas = schemas
+
+	return &result
+}
@@ -0,0 +1,251 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	v3high "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+const filterSpec = `
+openapi: 3.0.0
+info: {title: Example Service, version: 1.0.0}
+paths:
+  /messages:
+    get:
+      operationId: listMessages
+      tags: [messages]
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/MessageList'}
+    post:
+      operationId: createMessage
+      tags: [messages]
+      deprecated: true
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Message'}
+      responses: {}
+  /health:
+    get:
+      responses: {}
+  /admin/users/{id}:
+    get:
+      operationId: adminGetUser
+      tags: [users, admin]
+      parameters:
+        - {in: path, name: id, required: true, schema: {type: string}}
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/User'}
+        404:
+          description: Not found
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Error'}
+  /admin/stats:
+    get:
+      operationId: adminStats
+      responses: {}
+components:
+  schemas:
+    MessageList:
+      type: object
+      properties:
+        items:
+          type: array
+          items: {$ref: '#/components/schemas/Message'}
+    Message:
+      type: object
+      properties:
+        author: {$ref: '#/components/schemas/User'}
+        text: {type: string}
+    User:
+      type: object
+      properties:
+        name: {type: string}
+        manager: {$ref: '#/components/schemas/User'}
+    Error:
+      type: object
+      properties:
+        message: {type: string}
+    Unused:
+      type: object
+      properties:
+        id: {type: string}
+`
+
+func filterModel(t *testing.T) *v3high.Document {
+	t.Helper()
+
+	doc, err := libopenapi.NewDocument([]byte(filterSpec))
+	if err != nil {
+		t.Fatalf("could not parse spec: %v", err)
+	}
+
+	model, errs := doc.BuildV3Model()
+	if len(errs) > 0 {
+		t.Fatalf("could not build model: %v", errs)
+	}
+
+	return &model.Model
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	model := filterModel(t)
+
+	cases := []struct {
+		name    string
+		filter  Filter
+		want    []string
+		schemas []string
+	}{
+		{
+			name: "all",
+			want: []string{"ListMessages", "CreateMessage", "GETHealth", "AdminGetUser", "AdminStats"},
+		},
+		{
+			name:    "include tag",
+			filter:  Filter{Include: Selector{Tags: []string{"admin"}}},
+			want:    []string{"AdminGetUser"},
+			schemas: []string{"User", "Error"},
+		},
+		{
+			name:    "include operations",
+			filter:  Filter{Include: Selector{Operations: []string{"listMessages", "get /health"}}},
+			want:    []string{"ListMessages", "GETHealth"},
+			schemas: []string{"MessageList", "Message", "User"},
+		},
+		{
+			name:   "include path glob",
+			filter: Filter{Include: Selector{Paths: []string{"/admin/**"}}},
+			want:   []string{"AdminGetUser", "AdminStats"},
+		},
+		{
+			name:   "include segment glob",
+			filter: Filter{Include: Selector{Paths: []string{"/admin/*"}}},
+			want:   []string{"AdminStats"},
+		},
+		{
+			name:   "include operationId pattern",
+			filter: Filter{Include: Selector{OperationIDs: []string{"^admin"}}},
+			want:   []string{"AdminGetUser", "AdminStats"},
+		},
+		{
+			name:    "exclude deprecated",
+			filter:  Filter{Exclude: Selector{Deprecated: true}},
+			want:    []string{"ListMessages", "GETHealth", "AdminGetUser", "AdminStats"},
+			schemas: []string{"MessageList", "Message", "User", "Error"},
+		},
+		{
+			name: "include and exclude",
+			filter: Filter{
+				Include: Selector{Tags: []string{"messages"}},
+				Exclude: Selector{Operations: []string{"POST /messages"}, Paths: []string{"/health"}},
+			},
+			want: []string{"ListMessages"},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			paths, err := CollectPaths(ctx, model.Paths, c.filter)
+			if err != nil {
+				t.Fatalf("could not collect paths: %v", err)
+			}
+
+			got := make([]string, 0)
+			for _, path := range paths {
+				got = append(got, path.CanonicalName)
+			}
+
+			if !reflect.DeepEqual(c.want, got) {
+				t.Fatalf("paths mismatch: want %v; got %v", c.want, got)
+			}
+
+			if c.schemas == nil {
+				return
+			}
+
+			components := filterComponents(ctx, model.Components, referencedSchemas(ctx, paths))
+
+			schemas := make([]string, 0)
+			for pair := range orderedmap.Iterate(ctx, components.Schemas) {
+				schemas = append(schemas, pair.Key())
+			}
+
+			if !reflect.DeepEqual(c.schemas, schemas) {
+				t.Fatalf("schemas mismatch: want %v; got %v", c.schemas, schemas)
+			}
+		})
+	}
+}
+
+func TestFilterInvalid(t *testing.T) {
+	t.Parallel()
+
+	filter := Filter{Exclude: Selector{OperationIDs: []string{"(admin"}}}
+
+	_, err := CollectPaths(context.Background(), filterModel(t).Paths, filter)
+	if err == nil || !strings.Contains(err.Error(), `invalid operationId pattern "(admin"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGenerateFiltered(t *testing.T) {
+	t.Parallel()
+
+	g := Generator{Filter: Filter{Include: Selector{Tags: []string{"admin"}}}}
+	if err := g.Generate(context.Background(), *filterModel(t), "ExampleService", []string{"go-gen-http"}); err != nil {
+		t.Fatalf("could not generate: %v", err)
+	}
+
+	source, err := g.Source()
+	if err != nil {
+		t.Fatalf("could not generate source: %v", err)
+	}
+
+	for _, want := range []string{"type User struct", "type Error struct", "func (cl *ExampleService) AdminGetUser("} {
+		if !bytes.Contains(source, []byte(want)) {
+			t.Fatalf("source misses %s", want)
+		}
+	}
+
+	for _, unwanted := range []string{"type Message struct", "type Unused struct", "ListMessages"} {
+		if bytes.Contains(source, []byte(unwanted)) {
+			t.Fatalf("source contains %s", unwanted)
+		}
+	}
+}
+
+func TestGenerateFilteredNone(t *testing.T) {
+	t.Parallel()
+
+	g := Generator{Filter: Filter{Include: Selector{Tags: []string{"nonexistent"}}}}
+
+	err := g.Generate(context.Background(), *filterModel(t), "ExampleService", []string{"go-gen-http"})
+	if !errors.Is(err, errNoOperations) {
+		t.Fatalf("error mismatch: want %v; got %v", errNoOperations, err)
+	}
+}
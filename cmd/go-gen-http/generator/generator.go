@@ -4,8 +4,8 @@ import (
 	"bytes"
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
-	"go/format"
 	"log"
 	"slices"
 	"strings"
@@ -17,6 +17,14 @@ import (
 )
 
 var (
+	//go:embed templates/header.tmpl
+	rawHeaderTemplate string
+	headerTemplate    = mustparse("header", rawHeaderTemplate)
+
+	//go:embed templates/api.tmpl
+	rawAPITemplate string
+	apiTemplate    = mustparse("api", rawAPITemplate)
+
 	//go:embed templates/client.tmpl
 	rawClientTemplate string
 	clientTemplate    = mustparse("client", rawClientTemplate)
@@ -29,18 +37,47 @@ var (
 	rawConfigTemplate string
 	configTemplate    = mustparse("config", rawConfigTemplate)
 
+	//go:embed templates/fake.tmpl
+	rawFakeTemplate string
+	fakeTemplate    = mustparse("fake", rawFakeTemplate)
+
+	//go:embed templates/schemas.tmpl
+	rawSchemasTemplate string
+	schemasTemplate    = mustparse("schemas", rawSchemasTemplate)
+
+	//go:embed templates/server.tmpl
+	rawServerTemplate string
+	serverTemplate    = mustparse("server", rawServerTemplate)
+
+	//go:embed templates/mock.tmpl
+	rawMockTemplate string
+	mockTemplate    = mustparse("mock", rawMockTemplate)
+
 	//go:embed templates/request.tmpl
 	rawRequestTemplate string
 	requestTemplate    = mustparse("request", rawRequestTemplate)
+
+	//go:embed templates/subclient.tmpl
+	rawSubClientTemplate string
+	subClientTemplate    = mustparse("subclient", rawSubClientTemplate)
 )
 
 func mustparse(name, tmpl string) *template.Template {
-	return must(template.New(name).Parse(tmpl))
+	return must(template.New(name).Funcs(templateFuncs).Parse(tmpl))
+}
+
+//nolint:gochecknoglobals // Template functions must be available on parse.
+var templateFuncs = template.FuncMap{
+	"duration":    durationLiteral,
+	"stringSlice": stringSliceLiteral,
+	"string":      stringLiteral,
 }
 
 type RequestBody struct {
 	Name     string
 	Required bool
+	// Reference reports whether body is a component with generated type.
+	Reference bool
 }
 
 type ResponseCode struct {
@@ -54,8 +91,65 @@ type Property struct {
 	Tag  string
 }
 
+// Names of generated files; operations are written to files named after
+// their first tag or to client file if they have no tags.
+const (
+	clientFile = "client.go"
+	configFile = "config.go"
+	modelsFile = "models.go"
+	fakeFile   = "fake.go"
+	serverFile = "server.go"
+	mockFile   = "mock.go"
+)
+
 type Generator struct {
-	buf bytes.Buffer
+	// Server enables generation of server interface and handler.
+	Server bool
+	// Mock enables generation of server handler serving spec examples; it
+	// implies Server.
+	Mock bool
+	// Package is the name of generated package; lower-cased client name is
+	// used if it's not set.
+	Package string
+	// SkipFake disables generation of client fake; it's required by Mock.
+	SkipFake bool
+	// SkipValidation disables schema constraint checks; generated Validate
+	// methods report no violations.
+	SkipValidation bool
+	// Filter selects generated operations.
+	Filter Filter
+	// SubClients enables generation of per-tag sub-clients, e.g.
+	// client.Messages().List(ctx, request), in addition to flat API.
+	SubClients bool
+
+	header bytes.Buffer
+	// files are bodies of generated files in order of generation.
+	files []generatedFile
+}
+
+type generatedFile struct {
+	name string
+	body *bytes.Buffer
+}
+
+// File is a formatted generated file.
+type File struct {
+	Name   string
+	Source []byte
+}
+
+// file returns body of generated file with name.
+func (g *Generator) file(name string) *bytes.Buffer {
+	for _, file := range g.files {
+		if file.name == name {
+			return file.body
+		}
+	}
+
+	body := &bytes.Buffer{}
+	g.files = append(g.files, generatedFile{name: name, body: body})
+
+	return body
 }
 
 func (g *Generator) Generate(
@@ -66,45 +160,147 @@ func (g *Generator) Generate(
 ) error {
 	client = canonize(client)
 
-	paths, err := CollectPaths(ctx, doc.Paths)
+	if g.Mock && g.SkipFake {
+		return errMockWithoutFake
+	}
+
+	paths, err := CollectPaths(ctx, doc.Paths, g.Filter)
 	if err != nil {
 		return fmt.Errorf("could not collect paths: %w", err)
 	}
 
+	if g.SkipValidation {
+		for i := range paths {
+			paths[i].Request.Checks = nil
+		}
+	}
+
 	if len(paths) == 0 {
-		return nil
+		if !g.Filter.IsZero() {
+			return fmt.Errorf("%w: filter selected none", errNoOperations)
+		}
+
+		return errNoOperations
+	}
+
+	defaults, err := collectMethodDefaults(doc.Extensions)
+	if err != nil {
+		return fmt.Errorf("could not collect default method settings: %w", err)
+	}
+
+	components := doc.Components
+	if components == nil {
+		components = &v3high.Components{}
 	}
 
-	if err := g.generateClient(client, args); err != nil {
+	if !g.Filter.IsZero() {
+		components = filterComponents(ctx, components, referencedSchemas(ctx, paths))
+	}
+
+	schemes := collectSecuritySchemes(ctx, components)
+	applySecurity(doc, schemes, paths)
+
+	var subClients []SubClient
+	if g.SubClients {
+		subClients = collectSubClients(client, paths)
+	}
+
+	if err := checkNames(ctx, client, schemes, paths, subClients, components); err != nil {
+		return fmt.Errorf("could not name generated code: %w", err)
+	}
+
+	pkg := g.Package
+	if pkg == "" {
+		pkg = packageName(client)
+	}
+
+	imports, err := collectTypeImports(ctx, components)
+	if err != nil {
+		return fmt.Errorf("could not import types: %w", err)
+	}
+
+	if err := headerTemplate.Execute(&g.header, map[string]any{
+		"Package": pkg,
+		"CodeGen": strings.Join(args, " "),
+		"Imports": imports,
+	}); err != nil {
+		return fmt.Errorf("could not generate header: %w", err)
+	}
+
+	if err := g.generateClient(client, schemes); err != nil {
 		return fmt.Errorf("could not generate client: %w", err)
 	}
 
-	if err := g.generateConfig(paths); err != nil {
+	if err := g.generateAPI(client, paths, subClients); err != nil {
+		return fmt.Errorf("could not generate client interface: %w", err)
+	}
+
+	if err := g.generateConfig(client, defaults, paths); err != nil {
 		return fmt.Errorf("could not generate config: %w", err)
 	}
 
-	if err := g.generateComponents(ctx, doc.Components); err != nil {
+	if err := g.generateComponents(ctx, components); err != nil {
 		return fmt.Errorf("could not generate components: %w", err)
 	}
 
+	if err := g.generateSchemas(ctx, components); err != nil {
+		return fmt.Errorf("could not generate schemas: %w", err)
+	}
+
 	if err := g.generateMethods(client, paths); err != nil {
 		return fmt.Errorf("could not generate methods: %w", err)
 	}
 
+	if err := g.generateSubClients(subClients); err != nil {
+		return fmt.Errorf("could not generate sub-clients: %w", err)
+	}
+
+	if !g.SkipFake {
+		if err := g.generateFake(client, paths, subClients); err != nil {
+			return fmt.Errorf("could not generate fake: %w", err)
+		}
+	}
+
+	if g.Server || g.Mock {
+		if err := g.generateServer(client, paths); err != nil {
+			return fmt.Errorf("could not generate server: %w", err)
+		}
+	}
+
+	if g.Mock {
+		if err := g.generateMock(client, paths); err != nil {
+			return fmt.Errorf("could not generate mock: %w", err)
+		}
+	}
+
 	return nil
 }
 
-func (g *Generator) generateClient(name string, args []string) error {
-	return clientTemplate.Execute(&g.buf, map[string]string{
-		"ClientName": name,
-		"Package":    strings.ToLower(name),
-		"CodeGen":    strings.Join(args, " "),
+func (g *Generator) generateClient(name string, schemes []SecurityScheme) error {
+	return clientTemplate.Execute(g.file(clientFile), map[string]any{
+		"ClientName":      name,
+		"SecuritySchemes": schemes,
+	})
+}
+
+func (g *Generator) generateAPI(client string, paths []Path, subClients []SubClient) error {
+	return apiTemplate.Execute(g.file(clientFile), map[string]any{
+		"Client":     client,
+		"Fake":       !g.SkipFake,
+		"Paths":      paths,
+		"SubClients": subClients,
 	})
 }
 
-func (g *Generator) generateConfig(paths []Path) error {
-	return configTemplate.Execute(&g.buf, map[string]any{
-		"Paths": paths,
+func (g *Generator) generateConfig(
+	client string,
+	defaults MethodDefaults,
+	paths []Path,
+) error {
+	return configTemplate.Execute(g.file(configFile), map[string]any{
+		"EnvPrefix": strings.ToUpper(client),
+		"Defaults":  defaults,
+		"Paths":     paths,
 	})
 }
 
@@ -122,15 +318,21 @@ func (g *Generator) generateComponents(
 		}
 
 		typ := schema.Type[0]
-		if typ != "object" {
+		if typ != "object" || goType(proxy.Value()) != "" {
 			continue
 		}
 
 		properties := collectProperties(ctx, schema, "")
 
-		err := componentsTemplate.Execute(&g.buf, map[string]any{
-			"Name":       proxy.Key(),
+		var checks Checks
+		if !g.SkipValidation {
+			checks = componentChecks(ctx, schema)
+		}
+
+		err := componentsTemplate.Execute(g.file(modelsFile), map[string]any{
+			"Name":       schemaName(proxy.Key(), schema),
 			"Properties": properties,
+			"Checks":     checks,
 		})
 		if err != nil {
 			return fmt.Errorf("could not render %q: %w", proxy.Key(), err)
@@ -140,6 +342,15 @@ func (g *Generator) generateComponents(
 	return nil
 }
 
+func (g *Generator) generateSchemas(
+	ctx context.Context,
+	components *v3high.Components,
+) error {
+	return schemasTemplate.Execute(g.file(modelsFile), map[string]any{
+		"Schemas": collectSchemas(ctx, components),
+	})
+}
+
 func (g *Generator) generateMethods(client string, paths []Path) error {
 	for _, path := range paths {
 		if err := g.generateMethod(client, path); err != nil {
@@ -155,11 +366,65 @@ func (g *Generator) generateMethod(client string, path Path) error {
 	parameters["Client"] = client
 	parameters["Path"] = path
 
-	return requestTemplate.Execute(&g.buf, parameters)
+	return requestTemplate.Execute(g.file(operationFile(path)), parameters)
+}
+
+// generateSubClients renders sub-clients next to their operations.
+func (g *Generator) generateSubClients(subClients []SubClient) error {
+	for _, subClient := range subClients {
+		if err := subClientTemplate.Execute(g.file(subClient.file), subClient); err != nil {
+			return fmt.Errorf("could not generate %q: %w", subClient.Tag, err)
+		}
+	}
+
+	return nil
+}
+
+func (g *Generator) generateFake(client string, paths []Path, subClients []SubClient) error {
+	return fakeTemplate.Execute(g.file(fakeFile), map[string]any{
+		"Client":     client,
+		"Paths":      paths,
+		"SubClients": subClients,
+	})
 }
 
+var (
+	errNotServable     = errors.New("path parameters must be whole path segments")
+	errMockWithoutFake = errors.New("mock requires fake")
+	errNoOperations    = errors.New("no operations to generate")
+)
+
+func (g *Generator) generateServer(client string, paths []Path) error {
+	for _, path := range paths {
+		if path.Route.Pattern == "" {
+			return fmt.Errorf("could not serve %s %q: %w", path.Method, path.URL, errNotServable)
+		}
+	}
+
+	return serverTemplate.Execute(g.file(serverFile), map[string]any{
+		"Client": client,
+		"Paths":  paths,
+	})
+}
+
+func (g *Generator) generateMock(client string, paths []Path) error {
+	return mockTemplate.Execute(g.file(mockFile), map[string]any{
+		"Client": client,
+		"Paths":  paths,
+	})
+}
+
+// Source returns generated code as a single file.
 func (g *Generator) Source() ([]byte, error) {
-	source, err := format.Source(g.buf.Bytes())
+	var buf bytes.Buffer
+
+	_, _ = buf.Write(g.header.Bytes())
+
+	for _, file := range g.files {
+		_, _ = buf.Write(file.body.Bytes())
+	}
+
+	source, err := formatSource(buf.Bytes())
 	if err != nil {
 		return nil, fmt.Errorf("could not format source: %w", err)
 	}
@@ -167,6 +432,50 @@ func (g *Generator) Source() ([]byte, error) {
 	return source, nil
 }
 
+// Files returns generated code split into files: client, config, models,
+// operations grouped by tag, fake and optional server and mock.
+func (g *Generator) Files() ([]File, error) {
+	result := make([]File, 0, len(g.files))
+
+	for _, file := range g.files {
+		var buf bytes.Buffer
+
+		_, _ = buf.Write(g.header.Bytes())
+		_, _ = buf.Write(file.body.Bytes())
+
+		source, err := formatSource(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("could not format %s: %w", file.name, err)
+		}
+
+		result = append(result, File{Name: file.name, Source: source})
+	}
+
+	return result, nil
+}
+
+// operationFile returns name of file with operation: the first tag in lower
+// case, e.g. "useraccounts.go" for "User Accounts" tag. Separators are
+// dropped, so names never end with "_test" or build constraints like
+// "_linux". Tags named after other generated files get "ops" suffix.
+func operationFile(path Path) string {
+	if len(path.Tags) == 0 {
+		return clientFile
+	}
+
+	name := strings.ToLower(canonize(path.Tags[0]))
+	if name == "" {
+		return clientFile
+	}
+
+	switch name + ".go" {
+	case clientFile, configFile, modelsFile, fakeFile, serverFile, mockFile:
+		name += "ops"
+	}
+
+	return name + ".go"
+}
+
 func collectProperties(
 	ctx context.Context,
 	schema *base.Schema,
@@ -177,9 +486,20 @@ func collectProperties(
 
 	for property := range properties {
 		key := property.Key()
-		value := property.Value().Schema()
+		proxy := property.Value()
+		value := proxy.Schema()
 		required := slices.Contains(schema.Required, key)
 
+		tag := key
+		if !required {
+			tag = tag + ",omitempty"
+		}
+
+		if typ := goType(proxy); typ != "" {
+			result = append(result, Property{Name: fieldName(key, proxy), Type: typ, Tag: tag})
+			continue
+		}
+
 		typ := "any"
 
 		switch ttyp := value.Type[0]; ttyp {
@@ -195,14 +515,16 @@ func collectProperties(
 			// NOTE(max): it's a hack and might panic; please do something
 			// about it.
 			// TODO(max): generate struct for inline property.
-			reference := value.ParentProxy.GetReference()
-			if reference == "" {
-				reference = parentName + canonize(key)
+			typ = typeName(proxy)
+			if typ == "" {
+				typ = parentName + canonize(key)
 			}
-
-			splits := strings.Split(reference, "/")
-			typ = splits[len(splits)-1]
 		case "array":
+			if items := goType(value.Items.A); items != "" {
+				typ = "[]" + items
+				break
+			}
+
 			// NOTE(max): it's a hack and might panic; please do something
 			// about it.
 			itemsSchema := value.Items.A.Schema()
@@ -217,23 +539,23 @@ func collectProperties(
 			typ = "[]" + atyp
 
 			if atyp == "object" {
-				reference := itemsSchema.ParentProxy.GetReference()
-				if reference == "" {
-					reference = parentName + canonize(key)
+				name := typeName(value.Items.A)
+				if name == "" {
+					name = parentName + canonize(key)
 				}
 
-				splits := strings.Split(reference, "/")
-				typ = "[]" + splits[len(splits)-1]
+				typ = "[]" + name
 			}
 		}
 
-		tag := key
-		if !required {
-			tag = tag + ",omitempty"
+		// Optional numbers are pointers, so explicit zero is sent and
+		// validated.
+		if !required && (typ == "int64" || typ == "float64") {
+			typ = "*" + typ
 		}
 
 		result = append(result, Property{
-			Name: canonize(key),
+			Name: fieldName(key, proxy),
 			Type: typ,
 			Tag:  tag,
 		})
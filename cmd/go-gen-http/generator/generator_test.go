@@ -0,0 +1,173 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go/parser"
+	"go/token"
+	"slices"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+)
+
+const filesSpec = `
+openapi: 3.0.0
+info: {title: Example Service, version: 1.0.0}
+paths:
+  /messages:
+    get:
+      tags: [Messages]
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Message'}
+  /users:
+    get:
+      tags: [User Accounts, Messages]
+      responses: {}
+  /models:
+    get:
+      tags: [models]
+      responses: {}
+  /health:
+    get:
+      responses: {}
+components:
+  schemas:
+    Message:
+      type: object
+      properties:
+        text: {type: string}
+`
+
+func TestGeneratorFiles(t *testing.T) {
+	t.Parallel()
+
+	doc, err := libopenapi.NewDocument([]byte(filesSpec))
+	if err != nil {
+		t.Fatalf("could not parse spec: %v", err)
+	}
+
+	model, errs := doc.BuildV3Model()
+	if len(errs) > 0 {
+		t.Fatalf("could not build model: %v", errs)
+	}
+
+	g := Generator{Server: true, Package: "api"}
+	if err := g.Generate(context.Background(), model.Model, "ExampleService", []string{"go-gen-http"}); err != nil {
+		t.Fatalf("could not generate: %v", err)
+	}
+
+	files, err := g.Files()
+	if err != nil {
+		t.Fatalf("could not split files: %v", err)
+	}
+
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+
+	want := []string{
+		"client.go", "config.go", "models.go", "messages.go",
+		"useraccounts.go", "modelsops.go", "fake.go", "server.go",
+	}
+	if !slices.Equal(want, names) {
+		t.Fatalf("files mismatch: want %v; got %v", want, names)
+	}
+
+	fset := token.NewFileSet()
+
+	for _, file := range files {
+		if !generatedHeader.Match(bytes.SplitN(file.Source, []byte("\n"), 2)[0]) {
+			t.Fatalf("%s has no generated header", file.Name)
+		}
+
+		parsed, err := parser.ParseFile(fset, file.Name, file.Source, parser.PackageClauseOnly)
+		if err != nil {
+			t.Fatalf("could not parse %s: %v", file.Name, err)
+		}
+
+		if parsed.Name.Name != "api" {
+			t.Fatalf("%s package mismatch: want %q; got %q", file.Name, "api", parsed.Name.Name)
+		}
+	}
+
+	source, err := g.Source()
+	if err != nil {
+		t.Fatalf("could not generate source: %v", err)
+	}
+
+	if !bytes.Contains(source, []byte("\nfunc NewServerHandler(")) {
+		t.Fatal("single file misses server handler")
+	}
+}
+
+func TestGeneratorSkip(t *testing.T) {
+	t.Parallel()
+
+	doc, err := libopenapi.NewDocument([]byte(validationSpec))
+	if err != nil {
+		t.Fatalf("could not parse spec: %v", err)
+	}
+
+	model, errs := doc.BuildV3Model()
+	if len(errs) > 0 {
+		t.Fatalf("could not build model: %v", errs)
+	}
+
+	g := Generator{SkipFake: true, SkipValidation: true}
+	if err := g.Generate(context.Background(), model.Model, "ExampleService", []string{"go-gen-http"}); err != nil {
+		t.Fatalf("could not generate: %v", err)
+	}
+
+	files, err := g.Files()
+	if err != nil {
+		t.Fatalf("could not split files: %v", err)
+	}
+
+	for _, file := range files {
+		if file.Name == fakeFile {
+			t.Fatal("fake is generated")
+		}
+
+		if bytes.Contains(file.Source, []byte("v.Required(")) {
+			t.Fatalf("%s contains validation checks", file.Name)
+		}
+	}
+
+	mock := Generator{Mock: true, SkipFake: true}
+	if err := mock.Generate(context.Background(), model.Model, "ExampleService", nil); !errors.Is(err, errMockWithoutFake) {
+		t.Fatalf("error mismatch: want %v; got %v", errMockWithoutFake, err)
+	}
+}
+
+func TestGeneratorDeterministic(t *testing.T) {
+	t.Parallel()
+
+	model := filterModel(t)
+
+	var want []byte
+
+	for range 10 {
+		g := Generator{Server: true, Mock: true, SubClients: true}
+		if err := g.Generate(context.Background(), *model, "ExampleService", []string{"go-gen-http"}); err != nil {
+			t.Fatalf("could not generate: %v", err)
+		}
+
+		source, err := g.Source()
+		if err != nil {
+			t.Fatalf("could not generate source: %v", err)
+		}
+
+		if want != nil && !bytes.Equal(want, source) {
+			t.Fatalf("output differs between runs:\n%s", unifiedDiff("first", "next", want, source))
+		}
+
+		want = source
+	}
+}
@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"regexp"
+	"strconv"
+)
+
+// headerImport matches import line of header template.
+var headerImport = regexp.MustCompile(`(?m)^\s*"([^"]+)"\s*$`)
+
+//nolint:gochecknoglobals // Parsed once from embedded template.
+var (
+	// headerImports map import paths of header to package names.
+	headerImports = make(map[string]string)
+	// headerNames are package names imported by header.
+	headerNames = make(map[string]bool)
+)
+
+func init() {
+	for _, match := range headerImport.FindAllStringSubmatch(rawHeaderTemplate, -1) {
+		name := path.Base(match[1])
+		headerImports[match[1]] = name
+		headerNames[name] = true
+	}
+}
+
+// formatSource removes unused imports from source and formats it. Every
+// generated file starts with the same header importing all packages used by
+// templates, so files are not required to track their imports.
+func formatSource(source []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "", source, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse source: %w", err)
+	}
+
+	used := make(map[string]bool)
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		selector, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		// Identifiers resolved to local declarations shadow packages.
+		if ident, ok := selector.X.(*ast.Ident); ok && ident.Obj == nil {
+			used[ident.Name] = true
+		}
+
+		return true
+	})
+
+	// Header lists imports one per line, so unused ones are cut by line.
+	unused := make(map[int]bool)
+
+	for _, imp := range file.Imports {
+		name := importName(imp)
+		if name != "_" && name != "." && !used[name] {
+			unused[fset.Position(imp.Pos()).Line] = true
+		}
+	}
+
+	lines := bytes.SplitAfter(source, []byte("\n"))
+
+	var buf bytes.Buffer
+
+	for i, line := range lines {
+		if !unused[i+1] {
+			_, _ = buf.Write(line)
+		}
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+func importName(imp *ast.ImportSpec) string {
+	if imp.Name != nil {
+		return imp.Name.Name
+	}
+
+	value, err := strconv.Unquote(imp.Path.Value)
+	if err != nil {
+		return ""
+	}
+
+	return path.Base(value)
+}
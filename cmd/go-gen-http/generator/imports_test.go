@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"testing"
+)
+
+func TestFormatSource(t *testing.T) {
+	t.Parallel()
+
+	source := `package api
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"github.com/vitaminniy/go-lib-http/retry"
+	_ "embed"
+)
+
+func build(path string) string {
+	url := &url.URL{Path: path}
+	strings := []string{url.String()}
+
+	return strings[0]
+}
+`
+
+	want := `package api
+
+import (
+	"net/url"
+
+	_ "embed"
+)
+
+func build(path string) string {
+	url := &url.URL{Path: path}
+	strings := []string{url.String()}
+
+	return strings[0]
+}
+`
+
+	got, err := formatSource([]byte(source))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(got) != want {
+		t.Fatalf("mismatch:\nwant %s\ngot  %s", want, got)
+	}
+}
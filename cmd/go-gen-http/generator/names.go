@@ -0,0 +1,244 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/token"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3high "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	errInvalidName  = errors.New("invalid Go name")
+	errNameConflict = errors.New("name conflict")
+)
+
+// goName returns x-go-name extension value or fallback if it's not set.
+func goName(extensions *orderedmap.Map[string, *yaml.Node], fallback string) string {
+	if name := extensionValue(extensions, extGoName); name != "" {
+		return name
+	}
+
+	return fallback
+}
+
+// operationName returns Go name of operation: x-go-name, canonized
+// operationId or method and canonized URL.
+func operationName(method, url string, op *v3high.Operation) string {
+	fallback := method + canonize(url)
+	if op.OperationId != "" {
+		fallback = canonize(op.OperationId)
+	}
+
+	return goName(op.Extensions, fallback)
+}
+
+// schemaName returns Go type name of component schema.
+func schemaName(key string, schema *base.Schema) string {
+	if schema == nil {
+		return canonize(key)
+	}
+
+	return goName(schema.Extensions, canonize(key))
+}
+
+// typeName returns Go type name of schema referenced by proxy, either generated
+// or set with x-go-type; empty string is returned if proxy is not a reference.
+func typeName(proxy *base.SchemaProxy) string {
+	reference := proxy.GetReference()
+	if reference == "" {
+		return ""
+	}
+
+	if typ := goType(proxy); typ != "" {
+		return typ
+	}
+
+	splits := strings.Split(reference, "/")
+
+	return schemaName(splits[len(splits)-1], proxy.Schema())
+}
+
+// fieldName returns Go field name of object property. Extensions of
+// referenced schemas name types, so only inline properties are renamed.
+func fieldName(key string, proxy *base.SchemaProxy) string {
+	if proxy.IsReference() || proxy.Schema() == nil {
+		return canonize(key)
+	}
+
+	return goName(proxy.Schema().Extensions, canonize(key))
+}
+
+// paramName returns Go name of parameter without location prefix.
+func paramName(param *v3high.Parameter) string {
+	return goName(param.Extensions, canonize(param.Name))
+}
+
+// nameScope detects invalid and conflicting names declared in one Go scope,
+// e.g. package or struct.
+type nameScope struct {
+	name     string
+	declared map[string]string
+	errs     *[]error
+}
+
+func newNameScope(name string, errs *[]error) *nameScope {
+	return &nameScope{name: name, declared: make(map[string]string), errs: errs}
+}
+
+// declare adds name declared by source, e.g. `schema "Message"`, and reports
+// whether it's valid and unique.
+func (s *nameScope) declare(name, source string) bool {
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		*s.errs = append(*s.errs, fmt.Errorf("%w %q of %s", errInvalidName, name, source))
+		return false
+	}
+
+	if first, ok := s.declared[name]; ok {
+		*s.errs = append(*s.errs, fmt.Errorf("%w: %s %q is declared by %s and %s", errNameConflict, s.name, name, first, source))
+		return false
+	}
+
+	s.declared[name] = source
+
+	return true
+}
+
+// Exported package identifiers declared by templates regardless of the spec.
+//
+//nolint:gochecknoglobals // Read-only list.
+var generatedIdentifiers = []string{
+	"Option", "WithTransport", "WithTimeout", "WithConfigFunc", "WithMiddleware",
+	"WithMetrics", "WithTracer", "WithLogger", "ResponseViolationHandler",
+	"WithResponseViolationHandler", "MethodConfig", "ConfigFunc", "Config",
+	"DefaultConfig", "EnvPrefix", "ResolveConfig", "ErrNotImplemented",
+	"ServerInterface", "ServerOption", "WithServerErrorHandler",
+	"NewServerHandler", "NewMockHandler",
+}
+
+// checkNames reports invalid names and conflicts of generated identifiers:
+// package types and functions, client methods with their Config fields and
+// Fake members, and fields of generated structs.
+func checkNames(
+	ctx context.Context,
+	client string,
+	schemes []SecurityScheme,
+	paths []Path,
+	subClients []SubClient,
+	components *v3high.Components,
+) error {
+	var errs []error
+
+	pkg := newNameScope("package identifier", &errs)
+
+	for _, name := range generatedIdentifiers {
+		pkg.declare(name, "generated code")
+	}
+
+	pkg.declare(client, "client name")
+	pkg.declare("New"+client, "client constructor")
+	pkg.declare(client+"API", "client interface")
+	pkg.declare("Fake"+client, "client fake")
+	pkg.declare("Fake"+client+"Call", "client fake")
+
+	for _, scheme := range schemes {
+		source := fmt.Sprintf("security scheme %q", scheme.Name)
+
+		pkg.declare("With"+scheme.CanonicalName+"Credentials", source)
+
+		if scheme.Kind == securityOAuth2 {
+			pkg.declare("New"+scheme.CanonicalName+"ClientCredentials", source)
+		}
+	}
+
+	if components != nil {
+		for pair := range orderedmap.Iterate(ctx, components.Schemas) {
+			schema := pair.Value().Schema()
+			// Types set with x-go-type are declared elsewhere.
+			if schema == nil || len(schema.Type) == 0 || schema.Type[0] != "object" ||
+				goType(pair.Value()) != "" {
+				continue
+			}
+
+			name := schemaName(pair.Key(), schema)
+			source := fmt.Sprintf("schema %q", pair.Key())
+
+			pkg.declare(name, source)
+
+			fields := newNameScope("field of "+name, &errs)
+			fields.declare("Validate", "generated method")
+
+			for property := range orderedmap.Iterate(ctx, schema.Properties) {
+				fields.declare(
+					fieldName(property.Key(), property.Value()),
+					fmt.Sprintf("property %q of %s", property.Key(), source),
+				)
+			}
+		}
+	}
+
+	// Operation names are shared by client methods, Config fields and Fake
+	// methods, so derived Fake members are declared in the same scope.
+	operations := newNameScope("operation", &errs)
+	operations.declare("Default", "Config field")
+	operations.declare("Calls", "Fake"+client+" method")
+	operations.declare("Reset", "Fake"+client+" method")
+
+	for _, path := range paths {
+		source := fmt.Sprintf("operation %s %s", path.Method, path.URL)
+		if path.OperationID != "" {
+			source += fmt.Sprintf(" (operationId %q)", path.OperationID)
+		}
+
+		// Names derived from invalid or conflicting one are not reported.
+		if !operations.declare(path.CanonicalName, source) {
+			continue
+		}
+
+		operations.declare(path.CanonicalName+"Func", source)
+		operations.declare(path.CanonicalName+"Calls", source)
+
+		pkg.declare(path.Request.Name, source)
+		pkg.declare(path.Response.Name, source)
+
+		fields := newNameScope("field of "+path.Request.Name, &errs)
+		fields.declare("Headers", "generated field")
+		fields.declare("Body", "generated field")
+		fields.declare("Validate", "generated method")
+
+		for _, params := range []struct {
+			prefix string
+			values *Parameters
+		}{
+			{prefix: "Header", values: path.Request.Headers},
+			{prefix: "Query", values: path.Request.QueryParams},
+			{prefix: "Path", values: path.Request.PathParams},
+		} {
+			if params.values == nil {
+				continue
+			}
+
+			for _, param := range params.values.Values {
+				fields.declare(
+					params.prefix+param.Name,
+					fmt.Sprintf("%s parameter %q of %s", strings.ToLower(params.prefix), param.Key, source),
+				)
+			}
+		}
+	}
+
+	// Accessors are methods of client and its fake.
+	for _, subClient := range subClients {
+		source := fmt.Sprintf("tag %q", subClient.Tag)
+
+		pkg.declare(subClient.Type, source)
+		operations.declare(subClient.Name, source)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,224 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+)
+
+const namesSpec = `
+openapi: 3.0.0
+info: {title: Example Service, version: 1.0.0}
+paths:
+  /messages/{message-id}:
+    get:
+      operationId: get-message
+      parameters:
+        - {in: path, name: message-id, x-go-name: ID, schema: {type: string}}
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/message'}
+  /messages:
+    get:
+      x-go-name: ListMessages
+      responses: {}
+    post:
+      responses: {}
+components:
+  schemas:
+    message:
+      type: object
+      x-go-name: Message
+      properties:
+        text: {type: string, x-go-name: Body}
+        author: {$ref: '#/components/schemas/message'}
+`
+
+func TestNames(t *testing.T) {
+	t.Parallel()
+
+	doc, err := libopenapi.NewDocument([]byte(namesSpec))
+	if err != nil {
+		t.Fatalf("could not parse spec: %v", err)
+	}
+
+	model, errs := doc.BuildV3Model()
+	if len(errs) > 0 {
+		t.Fatalf("could not build model: %v", errs)
+	}
+
+	ctx := context.Background()
+
+	paths, err := CollectPaths(ctx, model.Model.Paths, Filter{})
+	if err != nil {
+		t.Fatalf("could not collect paths: %v", err)
+	}
+
+	want := []string{"GetMessage", "ListMessages", "POSTMessages"}
+	for i, path := range paths {
+		if path.CanonicalName != want[i] {
+			t.Fatalf("operation name mismatch: want %q; got %q", want[i], path.CanonicalName)
+		}
+	}
+
+	if got := paths[0].Request.PathParams.Values[0].Name; got != "ID" {
+		t.Fatalf("parameter name mismatch: want %q; got %q", "ID", got)
+	}
+
+	if got := paths[0].Response.Codes[0].Name; got != "Message" {
+		t.Fatalf("response type mismatch: want %q; got %q", "Message", got)
+	}
+
+	properties := collectProperties(ctx, model.Model.Components.Schemas.GetOrZero("message").Schema(), "")
+
+	if got := properties[0].Name; got != "Body" {
+		t.Fatalf("field name mismatch: want %q; got %q", "Body", got)
+	}
+
+	if got := properties[1]; got.Name != "Author" || got.Type != "Message" {
+		t.Fatalf("field mismatch: want Author Message; got %s %s", got.Name, got.Type)
+	}
+
+	if err := checkNames(ctx, "MessageService", nil, paths, nil, model.Model.Components); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCheckNames(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		spec       string
+		subClients bool
+		err        error
+		message    string
+	}{
+		{
+			name: "duplicate operationId",
+			spec: `
+paths:
+  /a: {get: {operationId: getThing, responses: {}}}
+  /b: {get: {operationId: get_thing, responses: {}}}`,
+			err: errNameConflict,
+			message: `operation "GetThing" is declared by operation GET /a (operationId "getThing") ` +
+				`and operation GET /b (operationId "get_thing")`,
+		},
+		{
+			name: "fake member",
+			spec: `
+paths:
+  /a: {get: {operationId: thing, responses: {}}}
+  /b: {get: {operationId: thingCalls, responses: {}}}`,
+			err:     errNameConflict,
+			message: `operation "ThingCalls" is declared by operation GET /a`,
+		},
+		{
+			name: "sub-client accessor",
+			spec: `
+paths:
+  /a: {get: {operationId: messages, tags: [messages], responses: {}}}`,
+			subClients: true,
+			err:        errNameConflict,
+			message:    `operation "Messages" is declared by operation GET /a (operationId "messages") and tag "messages"`,
+		},
+		{
+			name: "config field",
+			spec: `
+paths:
+  /a: {get: {operationId: default, responses: {}}}`,
+			err:     errNameConflict,
+			message: `operation "Default" is declared by Config field and operation GET /a`,
+		},
+		{
+			name: "component and request type",
+			spec: `
+paths:
+  /a: {get: {operationId: thing, responses: {}}}
+components:
+  schemas:
+    ThingRequest: {type: object}`,
+			err:     errNameConflict,
+			message: `package identifier "ThingRequest" is declared by schema "ThingRequest" and operation GET /a`,
+		},
+		{
+			name: "reserved identifier",
+			spec: `
+paths: {}
+components:
+  schemas:
+    Config: {type: object}`,
+			err:     errNameConflict,
+			message: `package identifier "Config" is declared by generated code and schema "Config"`,
+		},
+		{
+			name: "properties",
+			spec: `
+paths: {}
+components:
+  schemas:
+    Thing:
+      type: object
+      properties:
+        user_id: {type: string}
+        user-id: {type: string}`,
+			err:     errNameConflict,
+			message: `field of Thing "UserID" is declared by property "user_id" of schema "Thing" and property "user-id"`,
+		},
+		{
+			name: "invalid x-go-name",
+			spec: `
+paths:
+  /a: {get: {x-go-name: get-a, responses: {}}}`,
+			err:     errInvalidName,
+			message: `invalid Go name "get-a" of operation GET /a`,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			spec := "openapi: 3.0.0\ninfo: {title: Example Service, version: 1.0.0}" + c.spec
+
+			doc, err := libopenapi.NewDocument([]byte(spec))
+			if err != nil {
+				t.Fatalf("could not parse spec: %v", err)
+			}
+
+			model, errs := doc.BuildV3Model()
+			if len(errs) > 0 {
+				t.Fatalf("could not build model: %v", errs)
+			}
+
+			ctx := context.Background()
+
+			paths, err := CollectPaths(ctx, model.Model.Paths, Filter{})
+			if err != nil {
+				t.Fatalf("could not collect paths: %v", err)
+			}
+
+			var subClients []SubClient
+			if c.subClients {
+				subClients = collectSubClients("ExampleService", paths)
+			}
+
+			err = checkNames(ctx, "ExampleService", nil, paths, subClients, model.Model.Components)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("error mismatch: want %v; got %v", c.err, err)
+			}
+
+			if !strings.Contains(err.Error(), c.message) {
+				t.Fatalf("message mismatch: want %q; got %q", c.message, err.Error())
+			}
+		})
+	}
+}
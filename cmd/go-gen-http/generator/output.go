@@ -0,0 +1,216 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// generatedHeader matches the first line of files written by go-gen-http.
+//
+//nolint:gochecknoglobals // Compiled once.
+var generatedHeader = regexp.MustCompile(`^// Code generated by .*go-gen-http.* DO NOT EDIT\.$`)
+
+var errNoFiles = errors.New("no files to write")
+
+// WriteDir writes files to dir creating it if needed. Go files generated
+// earlier and missing from files, e.g. after a tag was renamed, are removed;
+// other files are left intact. Empty files are refused, so generated ones
+// are never wiped at once.
+func WriteDir(dir string, files []File) error {
+	if len(files) == 0 {
+		return errNoFiles
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("could not create directory: %w", err)
+	}
+
+	stale, err := generatedFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		delete(stale, file.Name)
+
+		if err := os.WriteFile(filepath.Join(dir, file.Name), file.Source, 0o644); err != nil {
+			return fmt.Errorf("could not write %s: %w", file.Name, err)
+		}
+	}
+
+	for name := range stale {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return fmt.Errorf("could not remove stale %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// CheckDir compares files with dir as WriteDir would change it and returns
+// unified diff of differences; it's empty if dir is up to date.
+func CheckDir(dir string, files []File) ([]byte, error) {
+	stale, err := generatedFiles(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	var result []byte
+
+	for _, file := range files {
+		delete(stale, file.Name)
+
+		diff, err := CheckFile(filepath.Join(dir, file.Name), file.Source)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, diff...)
+	}
+
+	names := make([]string, 0, len(stale))
+	for name := range stale {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		name = filepath.Join(dir, name)
+
+		raw, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %w", name, err)
+		}
+
+		result = append(result, unifiedDiff(name, os.DevNull, raw, nil)...)
+	}
+
+	return result, nil
+}
+
+// CheckFile compares source with file name and returns unified diff of
+// differences; it's empty if file is up to date.
+func CheckFile(name string, source []byte) ([]byte, error) {
+	label := name
+
+	raw, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		label = os.DevNull
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", name, err)
+	}
+
+	return unifiedDiff(label, name, raw, source), nil
+}
+
+// HeaderArgs returns command line recorded in generated header, so output
+// doesn't depend on where and how generator is run: program name is stripped
+// of directory, e.g. of a "go run" build cache, absolute paths, including
+// ones of -ref URL=PATH and -import FILE=IMPORTPATH mappings, are made
+// relative to dir and -check flag is dropped.
+func HeaderArgs(args []string, dir string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+
+	result := []string{filepath.Base(args[0])}
+
+	// flag is a name of preceding flag which value is the next argument.
+	flag := ""
+
+	for _, arg := range args[1:] {
+		if !strings.HasPrefix(arg, "-") {
+			result = append(result, relativeValue(flag, arg, dir))
+			flag = ""
+
+			continue
+		}
+
+		name, value, ok := strings.Cut(arg, "=")
+		if strings.TrimLeft(name, "-") == "check" {
+			flag = ""
+			continue
+		}
+
+		if !ok {
+			result = append(result, arg)
+			flag = strings.TrimLeft(name, "-")
+
+			continue
+		}
+
+		result = append(result, name+"="+relativeValue(strings.TrimLeft(name, "-"), value, dir))
+		flag = ""
+	}
+
+	return result
+}
+
+// relativeValue returns value of flag with absolute paths relative to dir:
+// the path of -ref URL=PATH, the file of -import FILE=IMPORTPATH or value
+// itself.
+func relativeValue(flag, value, dir string) string {
+	switch flag {
+	case "ref":
+		if remote, name, ok := strings.Cut(value, "="); ok {
+			return remote + "=" + relativePath(name, dir)
+		}
+	case "import":
+		if location, importPath, ok := strings.Cut(value, "="); ok {
+			return relativePath(location, dir) + "=" + importPath
+		}
+	}
+
+	return relativePath(value, dir)
+}
+
+// relativePath returns absolute path relative to dir or its base name if
+// there's no relative one; other values are returned as is.
+func relativePath(value, dir string) string {
+	if !filepath.IsAbs(value) {
+		return value
+	}
+
+	rel, err := filepath.Rel(dir, value)
+	if err != nil {
+		return filepath.Base(value)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// generatedFiles returns set of Go files in dir with generated header.
+func generatedFiles(dir string) (map[string]bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read directory: %w", err)
+	}
+
+	result := make(map[string]bool)
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+
+		raw, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %w", entry.Name(), err)
+		}
+
+		line, _, _ := bytes.Cut(raw, []byte("\n"))
+		if generatedHeader.Match(line) {
+			result[entry.Name()] = true
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,180 @@
+package generator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWriteDir(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "api")
+
+	const header = "// Code generated by go-gen-http -output-dir api spec.yaml. DO NOT EDIT.\npackage api\n"
+
+	files := []File{
+		{Name: "client.go", Source: []byte(header)},
+		{Name: "messages.go", Source: []byte(header)},
+	}
+
+	if err := WriteDir(dir, files); err != nil {
+		t.Fatalf("could not write directory: %v", err)
+	}
+
+	others := map[string]string{
+		"helpers.go":  "package api\n",
+		"stringer.go": "// Code generated by stringer; DO NOT EDIT.\npackage api\n",
+		"notes.txt":   header,
+		"renamed.go":  header,
+		"messages.go": header + "// Stale.\n",
+	}
+
+	for name, content := range others {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("could not write %s: %v", name, err)
+		}
+	}
+
+	if err := WriteDir(dir, files); err != nil {
+		t.Fatalf("could not write directory: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("could not read directory: %v", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	want := []string{"client.go", "helpers.go", "messages.go", "notes.txt", "stringer.go"}
+	if !slices.Equal(want, names) {
+		t.Fatalf("files mismatch: want %v; got %v", want, names)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, "messages.go"))
+	if err != nil {
+		t.Fatalf("could not read messages.go: %v", err)
+	}
+
+	if string(raw) != header {
+		t.Fatalf("messages.go is not overwritten: %q", raw)
+	}
+}
+
+func TestCheckDir(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "api")
+
+	const header = "// Code generated by go-gen-http -output-dir api spec.yaml. DO NOT EDIT.\npackage api\n"
+
+	files := []File{
+		{Name: "client.go", Source: []byte(header)},
+		{Name: "messages.go", Source: []byte(header + "// Messages.\n")},
+	}
+
+	diff, err := CheckDir(dir, files)
+	if err != nil {
+		t.Fatalf("could not check missing directory: %v", err)
+	}
+
+	if !strings.HasPrefix(string(diff), "--- "+os.DevNull+"\n+++ "+filepath.Join(dir, "client.go")+"\n@@ -0,0 +1,2 @@\n") {
+		t.Fatalf("unexpected diff of missing directory:\n%s", diff)
+	}
+
+	if err := WriteDir(dir, files); err != nil {
+		t.Fatalf("could not write directory: %v", err)
+	}
+
+	if diff, err := CheckDir(dir, files); err != nil || len(diff) != 0 {
+		t.Fatalf("written directory differs: %v\n%s", err, diff)
+	}
+
+	files[1].Source = []byte(header + "// Changed.\n")
+	if err := os.WriteFile(filepath.Join(dir, "renamed.go"), []byte(header), 0o644); err != nil {
+		t.Fatalf("could not write renamed.go: %v", err)
+	}
+
+	diff, err = CheckDir(dir, files)
+	if err != nil {
+		t.Fatalf("could not check directory: %v", err)
+	}
+
+	messages := filepath.Join(dir, "messages.go")
+	renamed := filepath.Join(dir, "renamed.go")
+
+	want := "--- " + messages + "\n+++ " + messages + "\n" +
+		"@@ -1,3 +1,3 @@\n" +
+		" // Code generated by go-gen-http -output-dir api spec.yaml. DO NOT EDIT.\n" +
+		" package api\n" +
+		"-// Messages.\n" +
+		"+// Changed.\n" +
+		"--- " + renamed + "\n+++ " + os.DevNull + "\n" +
+		"@@ -1,2 +0,0 @@\n" +
+		"-// Code generated by go-gen-http -output-dir api spec.yaml. DO NOT EDIT.\n" +
+		"-package api\n"
+	if string(diff) != want {
+		t.Fatalf("diff mismatch:\nwant\n%s\ngot\n%s", want, diff)
+	}
+}
+
+func TestHeaderArgs(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.FromSlash("/home/user/project")
+
+	args := []string{
+		filepath.FromSlash("/tmp/go-build123/b001/exe/go-gen-http"),
+		"-check",
+		"-server",
+		"-config", filepath.FromSlash("/home/user/project/api/go-gen-http.yaml"),
+		"-output-dir=" + filepath.FromSlash("/home/user/project/api"),
+		"-check=true",
+		"-ref=https://example.com/schemas/=" + filepath.FromSlash("/home/user/project/third_party/schemas"),
+		"-ref", "https://example.com/money.yaml=" + filepath.FromSlash("/home/user/project/third_party/money.yaml"),
+		"-import=" + filepath.FromSlash("/home/user/project/common.yaml") + "=example.com/common",
+		"--import", filepath.FromSlash("/home/user/project/third_party/money.yaml") + "=example.com/money",
+		"-import", "https://example.com/schemas/user.yaml=example.com/user",
+		"spec.yaml",
+	}
+
+	want := []string{
+		"go-gen-http", "-server", "-config", "api/go-gen-http.yaml", "-output-dir=api",
+		"-ref=https://example.com/schemas/=third_party/schemas",
+		"-ref", "https://example.com/money.yaml=third_party/money.yaml",
+		"-import=common.yaml=example.com/common",
+		"--import", "third_party/money.yaml=example.com/money",
+		"-import", "https://example.com/schemas/user.yaml=example.com/user",
+		"spec.yaml",
+	}
+	if got := HeaderArgs(args, dir); !slices.Equal(want, got) {
+		t.Fatalf("args mismatch: want %v; got %v", want, got)
+	}
+}
+
+func TestWriteDirEmpty(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	const header = "// Code generated by go-gen-http -output-dir api spec.yaml. DO NOT EDIT.\npackage api\n"
+
+	if err := os.WriteFile(filepath.Join(dir, "client.go"), []byte(header), 0o644); err != nil {
+		t.Fatalf("could not write client.go: %v", err)
+	}
+
+	if err := WriteDir(dir, nil); !errors.Is(err, errNoFiles) {
+		t.Fatalf("error mismatch: want %v; got %v", errNoFiles, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "client.go")); err != nil {
+		t.Fatalf("generated file is removed: %v", err)
+	}
+}
@@ -4,8 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
-	"strings"
 
 	v3high "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/pb33f/libopenapi/orderedmap"
@@ -13,23 +13,48 @@ import (
 
 type Path struct {
 	CanonicalName string
+	OperationID   string
 	URL           string
 	Method        string
+	Route         Route
+	Tags          []string
+
+	// Sensitive lists values which must be redacted in logs.
+	Sensitive Sensitive
+
+	// Security lists alternative security requirements; nil if operation
+	// does not declare them.
+	Security []SecurityRequirement
+
+	// Defaults are method settings declared with spec extensions.
+	Defaults MethodDefaults
 
 	Request  Request
 	Response Response
+
+	// operation is the source of path; schemas it references are
+	// generated when operations are filtered.
+	operation *v3high.Operation
 }
 
 type Request struct {
 	Name        string
 	Headers     *Parameters
 	QueryParams *Parameters
+	PathParams  *Parameters
 	Body        *RequestBody
+	// Checks validate parameters and body against schema constraints.
+	Checks Checks
 }
 
 type Response struct {
 	Name  string
 	Codes []ResponseCode
+	// Errors are documented error responses with status >= 400.
+	Errors []ResponseCode
+	// Example is served by generated mock; nil if there is no successful
+	// JSON response.
+	Example *Example
 }
 
 type Parameters struct {
@@ -42,11 +67,17 @@ type Parameter struct {
 	Required bool
 }
 
-func CollectPaths(ctx context.Context, paths *v3high.Paths) ([]Path, error) {
+// CollectPaths returns operations selected by filter in spec order.
+func CollectPaths(ctx context.Context, paths *v3high.Paths, filter Filter) ([]Path, error) {
 	if paths == nil {
 		return nil, nil
 	}
 
+	matcher, err := filter.compile()
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+
 	result := make([]Path, 0, orderedmap.Len(paths.PathItems))
 
 	// TODO(max): maybe we need to handle rest of the HTTP methods.
@@ -54,8 +85,8 @@ func CollectPaths(ctx context.Context, paths *v3high.Paths) ([]Path, error) {
 		url := pair.Key()
 		pathItem := pair.Value()
 
-		if pathItem.Get != nil {
-			path, err := NewPath(ctx, url, http.MethodGet, pathItem.Get)
+		if pathItem.Get != nil && matcher.selects(http.MethodGet, url, pathItem.Get) {
+			path, err := NewPath(ctx, url, http.MethodGet, pathItem, pathItem.Get)
 			if err != nil {
 				return nil, fmt.Errorf("could not create GET path %q: %w", url, err)
 			}
@@ -63,8 +94,8 @@ func CollectPaths(ctx context.Context, paths *v3high.Paths) ([]Path, error) {
 			result = append(result, path)
 		}
 
-		if pathItem.Post != nil {
-			path, err := NewPath(ctx, url, http.MethodPost, pathItem.Post)
+		if pathItem.Post != nil && matcher.selects(http.MethodPost, url, pathItem.Post) {
+			path, err := NewPath(ctx, url, http.MethodPost, pathItem, pathItem.Post)
 			if err != nil {
 				return nil, fmt.Errorf("could not create POST path %q: %w", url, err)
 			}
@@ -76,12 +107,20 @@ func CollectPaths(ctx context.Context, paths *v3high.Paths) ([]Path, error) {
 	return result, nil
 }
 
-func NewPath(ctx context.Context, url, method string, op *v3high.Operation) (Path, error) {
-	canonicalName := method + canonize(url)
+// NewPath describes operation of path item. Parameters declared on the path
+// item apply to the operation unless it overrides them.
+func NewPath(ctx context.Context, url, method string, pathItem *v3high.PathItem, op *v3high.Operation) (Path, error) {
+	if pathItem != nil && len(pathItem.Parameters) > 0 {
+		merged := *op
+		merged.Parameters = mergeParams(pathItem.Parameters, op.Parameters)
+		op = &merged
+	}
+
+	canonicalName := operationName(method, url, op)
 	requestCanonicalName := canonicalName + "Request"
 	responseCanonicalName := canonicalName + "Response"
 
-	headers, queryParams := collectParams(op.Parameters)
+	headers, queryParams, pathParams := collectParams(op.Parameters)
 	requestBody := collectRequestBody(requestCanonicalName, op.RequestBody)
 
 	responseCodes, err := collectResponseCodes(ctx, op.Responses)
@@ -89,30 +128,85 @@ func NewPath(ctx context.Context, url, method string, op *v3high.Operation) (Pat
 		return Path{}, fmt.Errorf("could not collect response codes: %w", err)
 	}
 
+	errorCodes, err := collectErrorCodes(ctx, op.Responses)
+	if err != nil {
+		return Path{}, fmt.Errorf("could not collect error codes: %w", err)
+	}
+
+	example, err := collectExample(ctx, op.Responses)
+	if err != nil {
+		return Path{}, fmt.Errorf("could not collect example: %w", err)
+	}
+
+	defaults, err := collectMethodDefaults(op.Extensions)
+	if err != nil {
+		return Path{}, fmt.Errorf("could not collect method defaults: %w", err)
+	}
+
+	route, err := compileRoute(method, url, pathParams)
+	if err != nil {
+		return Path{}, fmt.Errorf("could not compile route: %w", err)
+	}
+
+	sensitive, err := collectSensitive(ctx, op)
+	if err != nil {
+		return Path{}, fmt.Errorf("could not collect sensitive values: %w", err)
+	}
+
 	return Path{
 		CanonicalName: canonicalName,
+		OperationID:   op.OperationId,
 		URL:           url,
 		Method:        method,
+		Route:         route,
+		Tags:          op.Tags,
+		Defaults:      defaults,
+		Sensitive:     sensitive,
+		Security:      collectSecurity(op.Security),
 		Request: Request{
 			Name:        requestCanonicalName,
 			Headers:     headers,
 			QueryParams: queryParams,
+			PathParams:  pathParams,
 			Body:        requestBody,
+			Checks:      requestChecks(op, requestBody),
 		},
 		Response: Response{
-			Name:  responseCanonicalName,
-			Codes: responseCodes,
+			Name:    responseCanonicalName,
+			Codes:   responseCodes,
+			Errors:  errorCodes,
+			Example: example,
 		},
+		operation: op,
 	}, nil
 }
 
-func collectParams(params []*v3high.Parameter) (headers, queryParams *Parameters) {
+// mergeParams returns path item parameters not overridden by operation ones,
+// i.e. with the same name and location, followed by operation parameters.
+func mergeParams(common, own []*v3high.Parameter) []*v3high.Parameter {
+	result := make([]*v3high.Parameter, 0, len(common)+len(own))
+
+	for _, param := range common {
+		overridden := slices.ContainsFunc(own, func(other *v3high.Parameter) bool {
+			return other.Name == param.Name && other.In == param.In
+		})
+
+		if !overridden {
+			result = append(result, param)
+		}
+	}
+
+	return append(result, own...)
+}
+
+func collectParams(params []*v3high.Parameter) (headers, queryParams, pathParams *Parameters) {
 	hdrs := make([]Parameter, 0, len(params))
 	qrprms := make([]Parameter, 0, len(params))
+	pthprms := make([]Parameter, 0, len(params))
 
 	for _, param := range params {
 		parameter := Parameter{
-			Name:     canonize(param.Name),
+			Name:     paramName(param),
 			Key:      param.Name,
 			Required: resolveptr(param.Required),
 		}
@@ -122,6 +216,10 @@ func collectParams(params []*v3high.Parameter) (headers, queryParams *Parameters
 			hdrs = append(hdrs, parameter)
 		case "query":
 			qrprms = append(qrprms, parameter)
+		case "path":
+			// Path parameters are always required.
+			parameter.Required = true
+			pthprms = append(pthprms, parameter)
 		}
 	}
 
@@ -133,6 +231,10 @@ func collectParams(params []*v3high.Parameter) (headers, queryParams *Parameters
 		queryParams = &Parameters{Values: qrprms}
 	}
 
+	if len(pthprms) > 0 {
+		pathParams = &Parameters{Values: pthprms}
+	}
+
 	return
 }
 
@@ -156,10 +258,8 @@ func collectRequestBody(
 		return result
 	}
 
-	reference := media.Schema.GetReference()
-	splits := strings.Split(reference, "/")
-
-	result.Name = splits[len(splits)-1]
+	result.Name = typeName(media.Schema)
+	result.Reference = media.Schema.IsReference() && goType(media.Schema) == ""
 
 	return result
 }
@@ -192,12 +292,44 @@ func collectResponseCodes(
 			return nil, fmt.Errorf("invalid response schema %q: %w", code.Key(), err)
 		}
 
-		reference := media.Schema.GetReference()
-		splits := strings.Split(reference, "/")
+		result = append(result, ResponseCode{
+			Code: int(httpcode),
+			Name: typeName(media.Schema),
+		})
+	}
+
+	return result, nil
+}
+
+// collectErrorCodes returns documented error responses with JSON body.
+func collectErrorCodes(
+	ctx context.Context,
+	responses *v3high.Responses,
+) ([]ResponseCode, error) {
+	if responses == nil {
+		return nil, nil
+	}
+
+	var result []ResponseCode
+
+	for code := range orderedmap.Iterate(ctx, responses.Codes) {
+		httpcode, err := strconv.ParseInt(code.Key(), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse code %q: %w", code.Key(), err)
+		}
+
+		if httpcode < http.StatusBadRequest || code.Value().Content == nil {
+			continue
+		}
+
+		media := code.Value().Content.GetOrZero("application/json")
+		if media == nil || media.Schema == nil || media.Schema.GetReference() == "" {
+			continue
+		}
 
 		result = append(result, ResponseCode{
 			Code: int(httpcode),
-			Name: splits[len(splits)-1],
+			Name: typeName(media.Schema),
 		})
 	}
 
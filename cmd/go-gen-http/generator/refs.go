@@ -0,0 +1,452 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pb33f/libopenapi/datamodel"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	errUnresolvedRefs = errors.New("unresolved references")
+	errRemoteDisabled = errors.New("remote references are disabled")
+	errInvalidRef     = errors.New("invalid reference")
+	errRefEscapes     = errors.New("reference leaves mapped directory")
+)
+
+// remoteClient fetches remote references as libopenapi does by default.
+//
+//nolint:gochecknoglobals // Shared client.
+var remoteClient = &http.Client{Timeout: 2 * time.Minute}
+
+// refResolver locates documents referenced by $ref: remote URLs mapped by
+// rewrites are read from local files, other ones are fetched only if remote
+// references are allowed. Loaded documents are cached by location.
+type refResolver struct {
+	remote    bool
+	rewrites  map[string]string
+	documents map[string]*refDocument
+}
+
+// refResolver returns resolver of spec references, created on first use.
+func (c *SpecConfig) refResolver() *refResolver {
+	if c.resolver == nil {
+		c.resolver = &refResolver{
+			remote:    c.RemoteRefs == nil || *c.RemoteRefs,
+			rewrites:  c.Refs,
+			documents: make(map[string]*refDocument),
+		}
+	}
+
+	return c.resolver
+}
+
+// basePath returns directory relative references of spec are resolved
+// against.
+func (c *SpecConfig) basePath() (string, error) {
+	dir := c.BasePath
+	if dir == "" {
+		dir = filepath.Dir(c.Spec)
+	}
+
+	result, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve base path: %w", err)
+	}
+
+	return result, nil
+}
+
+// DocumentConfiguration returns libopenapi configuration resolving references
+// as set by RemoteRefs, Refs and BasePath.
+func (c *SpecConfig) DocumentConfiguration() (*datamodel.DocumentConfiguration, error) {
+	base, err := c.basePath()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := datamodel.NewDocumentConfiguration()
+	cfg.BasePath = base
+	cfg.AllowFileReferences = true
+	// Remote file system routes URLs to the resolver, which fetches only
+	// allowed ones.
+	cfg.AllowRemoteReferences = true
+	cfg.RemoteURLHandler = c.refResolver().fetch
+	cfg.ExtractRefsSequentially = true
+	cfg.BundleInlineRefs = true
+
+	return cfg, nil
+}
+
+// rewrite returns local file of remote URL mapped by the longest matching
+// rewrite: either the URL itself or its prefix ending with "/". URLs under
+// a prefix resolving outside of its directory, e.g. with "../", are
+// rejected.
+func (r *refResolver) rewrite(remote string) (string, bool, error) {
+	remote, _, _ = strings.Cut(remote, "#")
+
+	match := ""
+
+	for prefix := range r.rewrites {
+		if len(prefix) <= len(match) {
+			continue
+		}
+
+		if prefix == remote || (strings.HasSuffix(prefix, "/") && strings.HasPrefix(remote, prefix)) {
+			match = prefix
+		}
+	}
+
+	if match == "" {
+		return "", false, nil
+	}
+
+	dir := r.rewrites[match]
+	name := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(remote, match)))
+
+	if rel, err := filepath.Rel(dir, name); err != nil || rel == ".." ||
+		strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", true, fmt.Errorf("%w: %s mapped to %s", errRefEscapes, remote, dir)
+	}
+
+	return name, true, nil
+}
+
+// fetch returns remote document; it's a libopenapi remote URL handler.
+func (r *refResolver) fetch(remote string) (*http.Response, error) {
+	name, ok, err := r.rewrite(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		raw, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s mapped from %s: %w", name, remote, err)
+		}
+
+		return &http.Response{
+			Status:        http.StatusText(http.StatusOK),
+			StatusCode:    http.StatusOK,
+			Header:        make(http.Header),
+			Body:          io.NopCloser(bytes.NewReader(raw)),
+			ContentLength: int64(len(raw)),
+		}, nil
+	}
+
+	if !r.remote {
+		return nil, fmt.Errorf("%w: %s", errRemoteDisabled, remote)
+	}
+
+	return remoteClient.Get(remote) //nolint:noctx // Handler has no context.
+}
+
+// refDocument is a document references are looked up in.
+type refDocument struct {
+	// location is an absolute file name or URL of document.
+	location string
+	// label names document in errors.
+	label string
+	// base is a directory or URL relative references are resolved against.
+	base string
+	root *yaml.Node
+}
+
+// specDocument returns spec document; its relative references are resolved
+// against base path.
+func (c *SpecConfig) specDocument(raw []byte) (*refDocument, error) {
+	base, err := c.basePath()
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := filepath.Abs(c.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve spec path: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(raw, &root); err != nil {
+		return nil, fmt.Errorf("could not decode spec: %w", err)
+	}
+
+	if len(root.Content) == 0 {
+		return nil, errEmptySpec
+	}
+
+	return &refDocument{location: location, label: c.Spec, base: base, root: &root}, nil
+}
+
+// CheckRefs reports every reference of spec and of documents it references
+// that can't be resolved: missing files, remote URLs when remote references
+// are disabled and missing JSON pointers.
+func (c *SpecConfig) CheckRefs(raw []byte) error {
+	spec, err := c.specDocument(raw)
+	if err != nil {
+		return err
+	}
+
+	resolver := c.refResolver()
+
+	documents := map[string]*refDocument{spec.location: spec}
+	queue := []*refDocument{spec}
+	unresolved := make(map[string]bool)
+
+	for len(queue) > 0 {
+		doc := queue[0]
+		queue = queue[1:]
+
+		for _, ref := range collectRefs(doc.root) {
+			location, pointer, _ := strings.Cut(ref, "#")
+
+			target := doc
+			if location != "" {
+				key := resolveLocation(doc.base, location)
+
+				target = documents[key]
+				if target == nil {
+					target, err = resolver.load(key)
+					if err != nil {
+						unresolved[fmt.Sprintf("%q in %s: %v", ref, doc.label, err)] = true
+						continue
+					}
+
+					documents[key] = target
+					queue = append(queue, target)
+				}
+			}
+
+			if _, err := resolvePointer(target.root, pointer); err != nil {
+				unresolved[fmt.Sprintf("%q in %s: %v", ref, doc.label, err)] = true
+			}
+		}
+	}
+
+	if len(unresolved) == 0 {
+		return nil
+	}
+
+	list := make([]string, 0, len(unresolved))
+	for item := range unresolved {
+		list = append(list, item)
+	}
+
+	slices.Sort(list)
+
+	return fmt.Errorf("%w: %s", errUnresolvedRefs, strings.Join(list, ", "))
+}
+
+// load reads document at location: a local file or remote URL. Documents are
+// read once; callers must not modify them.
+func (r *refResolver) load(location string) (*refDocument, error) {
+	if doc, ok := r.documents[location]; ok {
+		return doc, nil
+	}
+
+	name, raw, err := r.read(location)
+	if err != nil {
+		return nil, err
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(raw, &root); err != nil {
+		return nil, fmt.Errorf("could not decode %s: %w", name, err)
+	}
+
+	base := filepath.Dir(name)
+	if isRemote(location) {
+		// Relative references of remote documents are relative to URL.
+		base = location
+	}
+
+	doc := &refDocument{location: location, label: name, base: base, root: &root}
+	r.documents[location] = doc
+
+	return doc, nil
+}
+
+// read returns name and content of document at location: a local file,
+// a local file remote URL is mapped to or a fetched remote document.
+func (r *refResolver) read(location string) (string, []byte, error) {
+	if !isRemote(location) {
+		raw, err := os.ReadFile(location)
+		if err != nil {
+			return "", nil, fmt.Errorf("could not read %s: %w", location, err)
+		}
+
+		return location, raw, nil
+	}
+
+	resp, err := r.fetch(location)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", nil, fmt.Errorf("could not read %s: %w", location, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("could not fetch %s: status %d", location, resp.StatusCode)
+	}
+
+	name := location
+	if mapped, ok, _ := r.rewrite(location); ok {
+		name = mapped
+	}
+
+	return name, raw, nil
+}
+
+func isRemote(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// resolveLocation returns URL or absolute file name of location relative to
+// base directory or URL.
+func resolveLocation(base, location string) string {
+	if isRemote(location) {
+		return location
+	}
+
+	if isRemote(base) {
+		baseURL, err := url.Parse(base)
+		if err != nil {
+			return location
+		}
+
+		ref, err := url.Parse(location)
+		if err != nil {
+			return location
+		}
+
+		return baseURL.ResolveReference(ref).String()
+	}
+
+	if filepath.IsAbs(location) {
+		return location
+	}
+
+	return filepath.Join(base, filepath.FromSlash(location))
+}
+
+// namedKeys are keys of mappings whose keys are names, e.g. schema
+// properties or response headers, rather than keywords.
+var namedKeys = map[string]bool{
+	"properties":        true,
+	"patternProperties": true,
+	"definitions":       true,
+	"$defs":             true,
+	"schemas":           true,
+	"headers":           true,
+}
+
+// isDataKeyword reports whether keyword value may contain arbitrary data,
+// i.e. it's an example or an extension.
+func isDataKeyword(key string) bool {
+	return key == "example" || strings.HasPrefix(key, "x-")
+}
+
+// collectRefs returns $ref values of node and its descendants except
+// examples and extensions which may contain arbitrary data.
+func collectRefs(node *yaml.Node) []string {
+	var result []string
+
+	// named reports whether node keys are names rather than keywords.
+	var visit func(node *yaml.Node, named bool)
+
+	visit = func(node *yaml.Node, named bool) {
+		switch node.Kind {
+		case yaml.DocumentNode, yaml.SequenceNode:
+			for _, child := range node.Content {
+				visit(child, false)
+			}
+		case yaml.MappingNode:
+			for _, pair := range mappingPairs(node) {
+				switch {
+				case named:
+					visit(pair.value, false)
+				case pair.key == "$ref" && pair.value.Kind == yaml.ScalarNode:
+					result = append(result, pair.value.Value)
+				case isDataKeyword(pair.key):
+				default:
+					visit(pair.value, namedKeys[pair.key])
+				}
+			}
+		}
+	}
+
+	visit(node, false)
+
+	return result
+}
+
+// resolvePointer returns value of document JSON pointer points to, e.g.
+// "/components/schemas/Message".
+func resolvePointer(root *yaml.Node, pointer string) (*yaml.Node, error) {
+	node := root
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	if pointer == "" || pointer == "/" {
+		return node, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: pointer must start with /", errInvalidRef)
+	}
+
+	for _, token := range pointerTokens(pointer) {
+		var next *yaml.Node
+
+		switch node.Kind {
+		case yaml.MappingNode:
+			next = mappingValue(node, token)
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(token); err == nil && i >= 0 && i < len(node.Content) {
+				next = node.Content[i]
+			}
+		}
+
+		if next == nil {
+			return nil, fmt.Errorf("%w: no %q", errInvalidRef, pointer)
+		}
+
+		node = next
+	}
+
+	return node, nil
+}
+
+// pointerTokens returns unescaped reference tokens of JSON pointer.
+func pointerTokens(pointer string) []string {
+	if pointer == "" || pointer == "/" {
+		return nil
+	}
+
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+
+	for i, token := range tokens {
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+
+	return tokens
+}
@@ -0,0 +1,266 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+)
+
+const refsSpec = `
+openapi: 3.0.0
+info: {title: Example Service, version: 1.0.0}
+paths:
+  /messages:
+    get:
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: 'https://example.com/schemas/common.yaml#/components/schemas/Message'}
+        404:
+          description: Not found
+          content:
+            application/json:
+              schema: {$ref: 'errors.yaml#/components/schemas/Error'}
+`
+
+const commonSpec = `
+components:
+  schemas:
+    Message:
+      type: object
+      properties:
+        text: {type: string}
+        author: {$ref: 'users.yaml#/components/schemas/User'}
+`
+
+const usersSpec = `
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        name: {type: string}
+`
+
+const errorsSpec = `
+components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        message: {type: string}
+`
+
+// refsDir returns directory with spec, its local reference and vendored
+// copy of remote ones.
+func refsDir(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"api.yaml":                refsSpec,
+		"errors.yaml":             errorsSpec,
+		"vendor/common.yaml":      commonSpec,
+		"vendor/users.yaml":       usersSpec,
+		"vendor/notes/readme.txt": "",
+	} {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatalf("could not create directory: %v", err)
+		}
+
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatalf("could not write %s: %v", name, err)
+		}
+	}
+
+	return dir
+}
+
+func TestCheckRefs(t *testing.T) {
+	t.Parallel()
+
+	dir := refsDir(t)
+	vendor := filepath.Join(dir, "vendor")
+
+	srv := httptest.NewServer(http.StripPrefix("/schemas/", http.FileServer(http.Dir(vendor))))
+	t.Cleanup(srv.Close)
+
+	cases := []struct {
+		name    string
+		spec    SpecConfig
+		raw     string
+		wantErr []string
+	}{
+		{
+			name: "remote allowed",
+			spec: SpecConfig{},
+			raw:  strings.ReplaceAll(refsSpec, "https://example.com", srv.URL),
+		},
+		{
+			name: "prefix rewrite",
+			spec: SpecConfig{
+				RemoteRefs: ptr(false),
+				Refs:       map[string]string{"https://example.com/schemas/": vendor},
+			},
+		},
+		{
+			name: "file rewrite",
+			spec: SpecConfig{
+				RemoteRefs: ptr(false),
+				Refs: map[string]string{
+					"https://example.com/schemas/common.yaml": filepath.Join(vendor, "common.yaml"),
+					"https://example.com/":                    filepath.Join(dir, "missing"),
+				},
+			},
+			wantErr: []string{
+				`"users.yaml#/components/schemas/User" in ` + filepath.Join(vendor, "common.yaml") + ": could not read " +
+					filepath.Join(dir, "missing", "schemas", "users.yaml"),
+			},
+		},
+		{
+			name: "rewrite traversal",
+			spec: SpecConfig{
+				RemoteRefs: ptr(false),
+				Refs:       map[string]string{"https://example.com/schemas/": vendor},
+			},
+			raw: refsSpec + `
+components:
+  schemas:
+    Outside: {$ref: 'https://example.com/schemas/../api.yaml#/info'}
+`,
+			wantErr: []string{
+				`"https://example.com/schemas/../api.yaml#/info" in api.yaml: reference leaves mapped directory: ` +
+					"https://example.com/schemas/../api.yaml mapped to " + vendor,
+			},
+		},
+		{
+			name: "remote disabled",
+			spec: SpecConfig{RemoteRefs: ptr(false)},
+			raw: refsSpec + `
+components:
+  schemas:
+    Local: {$ref: '#/components/schemas/Missing'}
+`,
+			wantErr: []string{
+				`unresolved references: "#/components/schemas/Missing" in api.yaml: invalid reference: no "/components/schemas/Missing", `,
+				`"https://example.com/schemas/common.yaml#/components/schemas/Message" in api.yaml: remote references are disabled`,
+			},
+		},
+		{
+			name: "keyword names",
+			spec: SpecConfig{RemoteRefs: ptr(false)},
+			raw: strings.ReplaceAll(refsSpec, "https://example.com/schemas/", "vendor/") + `
+components:
+  schemas:
+    Note:
+      type: object
+      example: {$ref: 'missing.yaml#/Example'}
+      properties:
+        example: {$ref: 'missing.yaml#/Property'}
+        x-note: {$ref: 'missing.yaml#/Extension'}
+`,
+			wantErr: []string{
+				`"missing.yaml#/Property" in api.yaml: could not read`,
+				`"missing.yaml#/Extension" in api.yaml: could not read`,
+			},
+		},
+		{
+			name: "base path",
+			spec: SpecConfig{BasePath: vendor},
+			wantErr: []string{
+				`"errors.yaml#/components/schemas/Error" in api.yaml: could not read ` + filepath.Join(vendor, "errors.yaml"),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			raw := c.raw
+			if raw == "" {
+				raw = refsSpec
+			}
+
+			spec := c.spec
+			spec.Spec = "api.yaml"
+
+			if spec.BasePath == "" {
+				spec.BasePath = dir
+			}
+
+			err := spec.CheckRefs([]byte(raw))
+			if len(c.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, errUnresolvedRefs) {
+				t.Fatalf("error mismatch: want %v; got %v", errUnresolvedRefs, err)
+			}
+
+			for _, want := range c.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("message mismatch: want %q; got %q", want, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestDocumentConfigurationRewrite(t *testing.T) {
+	t.Parallel()
+
+	dir := refsDir(t)
+
+	spec := SpecConfig{
+		Spec:       filepath.Join(dir, "api.yaml"),
+		RemoteRefs: ptr(false),
+		Refs:       map[string]string{"https://example.com/schemas/": filepath.Join(dir, "vendor")},
+	}
+
+	cfg, err := spec.DocumentConfiguration()
+	if err != nil {
+		t.Fatalf("could not configure document: %v", err)
+	}
+
+	doc, err := libopenapi.NewDocumentWithConfiguration([]byte(refsSpec), cfg)
+	if err != nil {
+		t.Fatalf("could not parse spec: %v", err)
+	}
+
+	model, errs := doc.BuildV3Model()
+	if len(errs) > 0 {
+		t.Fatalf("could not build model: %v", errs)
+	}
+
+	paths, err := CollectPaths(context.Background(), model.Model.Paths, Filter{})
+	if err != nil {
+		t.Fatalf("could not collect paths: %v", err)
+	}
+
+	if got := paths[0].Response.Codes[0].Name; got != "Message" {
+		t.Fatalf("response type mismatch: want %q; got %q", "Message", got)
+	}
+
+	if _, err := spec.refResolver().fetch("https://example.org/api.yaml"); !errors.Is(err, errRemoteDisabled) {
+		t.Fatalf("error mismatch: want %v; got %v", errRemoteDisabled, err)
+	}
+}
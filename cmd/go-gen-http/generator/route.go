@@ -0,0 +1,101 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var errUndeclaredPathParam = errors.New("path parameter is not declared")
+
+// Route is a path template compiled for client and server.
+type Route struct {
+	// URL is a Go expression of escaped request path, e.g.
+	// `"/messages/" + pathParam(request.PathID)`.
+	URL string
+	// Pattern is a http.ServeMux pattern, e.g. "GET /messages/{ID}"; empty if
+	// template can't be served with ServeMux, i.e. some parameter is not a
+	// whole path segment.
+	Pattern string
+}
+
+// compileRoute compiles path template, e.g. "/messages/{id}". ServeMux
+// wildcards are named after canonical parameter names as spec names may be
+// invalid Go identifiers.
+func compileRoute(method, template string, params *Parameters) (Route, error) {
+	var (
+		expr     []string
+		pattern  strings.Builder
+		rest     = template
+		servable = true
+	)
+
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			break
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return Route{}, fmt.Errorf("unclosed path parameter in %q", template)
+		}
+
+		end += start
+		key := rest[start+1 : end]
+
+		param, ok := findParameter(params, key)
+		if !ok {
+			return Route{}, fmt.Errorf("%w: %q", errUndeclaredPathParam, key)
+		}
+
+		if rest[:start] != "" {
+			expr = append(expr, strconv.Quote(rest[:start]))
+		}
+
+		if !strings.HasSuffix(pattern.String()+rest[:start], "/") ||
+			(end+1 < len(rest) && rest[end+1] != '/') {
+			servable = false
+		}
+
+		expr = append(expr, "pathParam(request.Path"+param.Name+")")
+
+		pattern.WriteString(rest[:start])
+		pattern.WriteString("{" + param.Name + "}")
+
+		rest = rest[end+1:]
+	}
+
+	if rest != "" || len(expr) == 0 {
+		expr = append(expr, strconv.Quote(rest))
+	}
+
+	pattern.WriteString(rest)
+
+	// Patterns ending with slash match all paths with that prefix.
+	if strings.HasSuffix(template, "/") {
+		pattern.WriteString("{$}")
+	}
+
+	route := Route{URL: strings.Join(expr, " + ")}
+	if servable {
+		route.Pattern = method + " " + pattern.String()
+	}
+
+	return route, nil
+}
+
+func findParameter(params *Parameters, key string) (Parameter, bool) {
+	if params == nil {
+		return Parameter{}, false
+	}
+
+	for _, param := range params.Values {
+		if param.Key == key {
+			return param, true
+		}
+	}
+
+	return Parameter{}, false
+}
@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+)
+
+func TestCompileRoute(t *testing.T) {
+	t.Parallel()
+
+	params := &Parameters{Values: []Parameter{
+		{Name: "MessageId", Key: "message-id", Required: true},
+		{Name: "Format", Key: "format", Required: true},
+	}}
+
+	cases := []struct {
+		name     string
+		template string
+		want     Route
+		err      error
+	}{
+		{
+			name:     "static",
+			template: "/api/v1/messages",
+			want: Route{
+				URL:     `"/api/v1/messages"`,
+				Pattern: "GET /api/v1/messages",
+			},
+		},
+		{
+			name:     "trailing slash",
+			template: "/api/v1/messages/",
+			want: Route{
+				URL:     `"/api/v1/messages/"`,
+				Pattern: "GET /api/v1/messages/{$}",
+			},
+		},
+		{
+			name:     "params",
+			template: "/api/v1/messages/{message-id}/{format}",
+			want: Route{
+				URL:     `"/api/v1/messages/" + pathParam(request.PathMessageId) + "/" + pathParam(request.PathFormat)`,
+				Pattern: "GET /api/v1/messages/{MessageId}/{Format}",
+			},
+		},
+		{
+			name:     "partial segment",
+			template: "/api/v1/messages/{message-id}.{format}",
+			want: Route{
+				URL:     `"/api/v1/messages/" + pathParam(request.PathMessageId) + "." + pathParam(request.PathFormat)`,
+				Pattern: "",
+			},
+		},
+		{
+			name:     "undeclared",
+			template: "/api/v1/messages/{id}",
+			err:      errUndeclaredPathParam,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := compileRoute(http.MethodGet, c.template, params)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("error mismatch: want %v; got %v", c.err, err)
+			}
+
+			if got != c.want {
+				t.Fatalf("mismatch: want %+v; got %+v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestCollectPathsPathItemParams(t *testing.T) {
+	t.Parallel()
+
+	const spec = `
+openapi: 3.0.0
+info: {title: Example Service, version: 1.0.0}
+paths:
+  /messages/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+      - {name: trace, in: header, schema: {type: string}}
+    get:
+      parameters:
+        - {name: trace, in: header, required: true, schema: {type: string}}
+        - {name: lang, in: query, schema: {type: string}}
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Message'}
+components:
+  schemas:
+    Message:
+      type: object
+      properties:
+        text: {type: string}
+`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	if err != nil {
+		t.Fatalf("could not parse spec: %v", err)
+	}
+
+	model, errs := doc.BuildV3Model()
+	if len(errs) > 0 {
+		t.Fatalf("could not build model: %v", errs)
+	}
+
+	paths, err := CollectPaths(context.Background(), model.Model.Paths, Filter{})
+	if err != nil {
+		t.Fatalf("could not collect paths: %v", err)
+	}
+
+	request := paths[0].Request
+
+	if want := `"/messages/" + pathParam(request.PathID)`; paths[0].Route.URL != want {
+		t.Fatalf("route mismatch: want %s; got %s", want, paths[0].Route.URL)
+	}
+
+	// Operation overrides header declared on path item.
+	if request.Headers == nil || len(request.Headers.Values) != 1 || !request.Headers.Values[0].Required {
+		t.Fatalf("headers mismatch: %+v", request.Headers)
+	}
+
+	if request.QueryParams == nil || request.QueryParams.Values[0].Key != "lang" {
+		t.Fatalf("query params mismatch: %+v", request.QueryParams)
+	}
+}
@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"context"
+	"slices"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3high "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/vitaminniy/go-lib-http/validate"
+)
+
+// SchemaDescriptor is rendered as validate.Schema for strict response
+// validation.
+type SchemaDescriptor struct {
+	Ref        string
+	Type       string
+	Nullable   bool
+	Format     string
+	Enum       []string
+	Properties []PropertyDescriptor
+	Required   []string
+	Closed     bool
+	Items      *SchemaDescriptor
+}
+
+type PropertyDescriptor struct {
+	Key    string
+	Schema *SchemaDescriptor
+}
+
+type NamedSchema struct {
+	Name   string
+	Schema *SchemaDescriptor
+}
+
+// collectSchemas returns descriptors of component schemas.
+func collectSchemas(ctx context.Context, components *v3high.Components) []NamedSchema {
+	if components == nil || components.Schemas == nil {
+		return nil
+	}
+
+	result := make([]NamedSchema, 0, orderedmap.Len(components.Schemas))
+
+	for pair := range orderedmap.Iterate(ctx, components.Schemas) {
+		if goType(pair.Value()) != "" {
+			continue
+		}
+
+		result = append(result, NamedSchema{
+			Name:   schemaName(pair.Key(), pair.Value().Schema()),
+			Schema: describeSchema(ctx, pair.Value().Schema()),
+		})
+	}
+
+	return result
+}
+
+// describeSchema returns descriptor of schema; referenced schemas are
+// described by name, so recursive schemas are supported. Compositions are
+// not supported and match any value.
+func describeSchema(ctx context.Context, schema *base.Schema) *SchemaDescriptor {
+	descriptor := &SchemaDescriptor{}
+
+	if schema == nil || len(schema.AllOf) > 0 || len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 {
+		return descriptor
+	}
+
+	descriptor.Nullable = resolveptr(schema.Nullable) || slices.Contains(schema.Type, "null")
+
+	for _, typ := range schema.Type {
+		if typ != "null" {
+			descriptor.Type = typ
+			break
+		}
+	}
+
+	if descriptor.Type == "" && schema.Properties != nil {
+		descriptor.Type = validate.TypeObject
+	}
+
+	switch descriptor.Type {
+	case validate.TypeString:
+		if slices.Contains(validate.Formats, schema.Format) {
+			descriptor.Format = schema.Format
+		}
+
+		descriptor.Enum = stringEnum(schema)
+	case validate.TypeObject:
+		for property := range orderedmap.Iterate(ctx, schema.Properties) {
+			descriptor.Properties = append(descriptor.Properties, PropertyDescriptor{
+				Key:    property.Key(),
+				Schema: describeProxy(ctx, property.Value()),
+			})
+		}
+
+		descriptor.Required = schema.Required
+		descriptor.Closed = schema.AdditionalProperties != nil &&
+			schema.AdditionalProperties.IsB() && !schema.AdditionalProperties.B
+	case validate.TypeArray:
+		if schema.Items != nil && schema.Items.IsA() {
+			descriptor.Items = describeProxy(ctx, schema.Items.A)
+		}
+	}
+
+	return descriptor
+}
+
+// describeProxy returns descriptor of schema defined or referenced by proxy;
+// schemas with x-go-type match any value.
+func describeProxy(ctx context.Context, proxy *base.SchemaProxy) *SchemaDescriptor {
+	if goType(proxy) != "" {
+		return &SchemaDescriptor{}
+	}
+
+	if name := typeName(proxy); name != "" {
+		return &SchemaDescriptor{Ref: name}
+	}
+
+	return describeSchema(ctx, proxy.Schema())
+}
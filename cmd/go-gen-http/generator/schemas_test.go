@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+)
+
+const schemasSpec = `
+openapi: 3.0.0
+info: {title: Example Service, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    Node:
+      type: object
+      required: [kind]
+      additionalProperties: false
+      properties:
+        kind: {type: string, enum: [leaf, branch]}
+        created: {type: string, format: date-time, nullable: true}
+        secret: {type: string, format: password}
+        children:
+          type: array
+          items: {$ref: '#/components/schemas/Node'}
+        meta:
+          type: object
+          properties:
+            size: {type: integer}
+        any:
+          oneOf: [{type: string}, {type: integer}]
+`
+
+func TestCollectSchemas(t *testing.T) {
+	t.Parallel()
+
+	doc, err := libopenapi.NewDocument([]byte(schemasSpec))
+	if err != nil {
+		t.Fatalf("could not parse spec: %v", err)
+	}
+
+	model, errs := doc.BuildV3Model()
+	if len(errs) > 0 {
+		t.Fatalf("could not build model: %v", errs)
+	}
+
+	want := []NamedSchema{{
+		Name: "Node",
+		Schema: &SchemaDescriptor{
+			Type: "object",
+			Properties: []PropertyDescriptor{
+				{Key: "kind", Schema: &SchemaDescriptor{Type: "string", Enum: []string{"leaf", "branch"}}},
+				{Key: "created", Schema: &SchemaDescriptor{Type: "string", Format: "date-time", Nullable: true}},
+				{Key: "secret", Schema: &SchemaDescriptor{Type: "string"}},
+				{Key: "children", Schema: &SchemaDescriptor{Type: "array", Items: &SchemaDescriptor{Ref: "Node"}}},
+				{Key: "meta", Schema: &SchemaDescriptor{
+					Type: "object",
+					Properties: []PropertyDescriptor{
+						{Key: "size", Schema: &SchemaDescriptor{Type: "integer"}},
+					},
+				}},
+				{Key: "any", Schema: &SchemaDescriptor{}},
+			},
+			Required: []string{"kind"},
+			Closed:   true,
+		},
+	}}
+
+	got := collectSchemas(context.Background(), model.Model.Components)
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("schemas mismatch:\nwant %+v\ngot  %+v", want, got)
+	}
+}
@@ -0,0 +1,159 @@
+package generator
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3high "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// Kinds of supported security schemes.
+const (
+	securityAPIKey = "apiKey"
+	securityBearer = "bearer"
+	securityBasic  = "basic"
+	securityOAuth2 = "oauth2"
+)
+
+// SecurityScheme is a supported security scheme of components.
+type SecurityScheme struct {
+	// Name is a scheme name as declared in the spec.
+	Name          string
+	CanonicalName string
+	Kind          string
+
+	// In and Key are API key location and parameter name.
+	In  string
+	Key string
+
+	// TokenURL and Scopes describe OAuth2 client credentials flow; Scopes are
+	// all scopes required by operations.
+	TokenURL string
+	Scopes   []string
+}
+
+// collectSecuritySchemes returns supported security schemes. Unsupported ones
+// are skipped, so operations requiring only them can't be authorized.
+func collectSecuritySchemes(
+	ctx context.Context,
+	components *v3high.Components,
+) []SecurityScheme {
+	if components == nil || components.SecuritySchemes == nil {
+		return nil
+	}
+
+	result := make([]SecurityScheme, 0, orderedmap.Len(components.SecuritySchemes))
+
+	for pair := range orderedmap.Iterate(ctx, components.SecuritySchemes) {
+		name, spec := pair.Key(), pair.Value()
+
+		scheme := SecurityScheme{
+			Name:          name,
+			CanonicalName: canonize(name),
+		}
+
+		switch {
+		case spec.Type == "apiKey":
+			scheme.Kind = securityAPIKey
+			scheme.In = spec.In
+			scheme.Key = spec.Name
+		case spec.Type == "http" && strings.EqualFold(spec.Scheme, "bearer"):
+			scheme.Kind = securityBearer
+		case spec.Type == "http" && strings.EqualFold(spec.Scheme, "basic"):
+			scheme.Kind = securityBasic
+		case spec.Type == "oauth2" && spec.Flows != nil && spec.Flows.ClientCredentials != nil:
+			scheme.Kind = securityOAuth2
+			scheme.TokenURL = spec.Flows.ClientCredentials.TokenUrl
+		default:
+			log.Printf("unsupported security scheme %q", name)
+			continue
+		}
+
+		result = append(result, scheme)
+	}
+
+	return result
+}
+
+// SecurityRequirement lists security schemes which must be applied together;
+// empty requirement means authorization is optional.
+type SecurityRequirement struct {
+	Schemes []string
+	// Scopes are OAuth2 scopes required per scheme.
+	Scopes map[string][]string
+}
+
+// collectSecurity returns security requirements; nil is returned if they are
+// not declared, and empty slice if security is explicitly disabled.
+func collectSecurity(requirements []*base.SecurityRequirement) []SecurityRequirement {
+	if requirements == nil {
+		return nil
+	}
+
+	result := make([]SecurityRequirement, 0, len(requirements))
+
+	for _, requirement := range requirements {
+		collected := SecurityRequirement{
+			Schemes: make([]string, 0, orderedmap.Len(requirement.Requirements)),
+			Scopes:  make(map[string][]string),
+		}
+
+		for pair := orderedmap.First(requirement.Requirements); pair != nil; pair = pair.Next() {
+			collected.Schemes = append(collected.Schemes, pair.Key())
+			collected.Scopes[pair.Key()] = pair.Value()
+		}
+
+		result = append(result, collected)
+	}
+
+	return result
+}
+
+// applySecurity sets document security to paths without own requirements,
+// collects OAuth2 scopes required by operations and marks API key headers
+// sensitive.
+func applySecurity(
+	doc v3high.Document,
+	schemes []SecurityScheme,
+	paths []Path,
+) {
+	defaults := collectSecurity(doc.Security)
+
+	for i := range paths {
+		path := &paths[i]
+
+		if path.Security == nil {
+			path.Security = defaults
+		}
+
+		for _, requirement := range path.Security {
+			for _, name := range requirement.Schemes {
+				idx := slices.IndexFunc(schemes, func(scheme SecurityScheme) bool {
+					return scheme.Name == name
+				})
+				if idx < 0 {
+					continue
+				}
+
+				scheme := &schemes[idx]
+
+				if scheme.Kind == securityAPIKey && scheme.In == "header" &&
+					!slices.Contains(path.Sensitive.Headers, scheme.Key) {
+					path.Sensitive.Headers = append(path.Sensitive.Headers, scheme.Key)
+				}
+
+				if scheme.Kind == securityOAuth2 {
+					for _, scope := range requirement.Scopes[name] {
+						if !slices.Contains(scheme.Scopes, scope) {
+							scheme.Scopes = append(scheme.Scopes, scope)
+						}
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+)
+
+const securitySpec = `
+openapi: 3.0.0
+info: {title: Example Service, version: 1.0.0}
+security:
+  - BearerAuth: []
+paths:
+  /login:
+    post:
+      security:
+        - ApiKey: []
+          OAuth: [read]
+        - {}
+      responses: {}
+  /public:
+    get:
+      security: []
+      responses: {}
+  /private:
+    get:
+      security:
+        - OAuth: [write, read]
+      responses: {}
+  /default:
+    get:
+      responses: {}
+components:
+  securitySchemes:
+    ApiKey: {type: apiKey, in: header, name: X-Api-Key}
+    BearerAuth: {type: http, scheme: bearer}
+    OAuth:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: https://auth.example.com/token
+          scopes: {read: Read, write: Write}
+    OIDC: {type: openIdConnect, openIdConnectUrl: https://auth.example.com}
+`
+
+func TestApplySecurity(t *testing.T) {
+	t.Parallel()
+
+	doc, err := libopenapi.NewDocument([]byte(securitySpec))
+	if err != nil {
+		t.Fatalf("could not parse spec: %v", err)
+	}
+
+	model, errs := doc.BuildV3Model()
+	if len(errs) > 0 {
+		t.Fatalf("could not build model: %v", errs)
+	}
+
+	ctx := context.Background()
+
+	paths, err := CollectPaths(ctx, model.Model.Paths, Filter{})
+	if err != nil {
+		t.Fatalf("could not collect paths: %v", err)
+	}
+
+	schemes := collectSecuritySchemes(ctx, model.Model.Components)
+	applySecurity(model.Model, schemes, paths)
+
+	wantSchemes := []SecurityScheme{
+		{Name: "ApiKey", CanonicalName: "APIKey", Kind: securityAPIKey, In: "header", Key: "X-Api-Key"},
+		{Name: "BearerAuth", CanonicalName: "BearerAuth", Kind: securityBearer},
+		{
+			Name:          "OAuth",
+			CanonicalName: "OAuth",
+			Kind:          securityOAuth2,
+			TokenURL:      "https://auth.example.com/token",
+			Scopes:        []string{"read", "write"},
+		},
+	}
+
+	if !reflect.DeepEqual(wantSchemes, schemes) {
+		t.Fatalf("schemes mismatch: want %+v; got %+v", wantSchemes, schemes)
+	}
+
+	want := map[string][][]string{
+		"POSTLogin":  {{"ApiKey", "OAuth"}, {}},
+		"GETPublic":  {},
+		"GETPrivate": {{"OAuth"}},
+		"GETDefault": {{"BearerAuth"}},
+	}
+
+	for _, path := range paths {
+		got := make([][]string, 0, len(path.Security))
+		for _, requirement := range path.Security {
+			got = append(got, requirement.Schemes)
+		}
+
+		if !reflect.DeepEqual(want[path.CanonicalName], got) {
+			t.Fatalf("%s security mismatch: want %v; got %v", path.CanonicalName, want[path.CanonicalName], got)
+		}
+	}
+
+	if got := paths[0].Sensitive.Headers; !reflect.DeepEqual([]string{"X-Api-Key"}, got) {
+		t.Fatalf("sensitive headers mismatch: want %v; got %v", []string{"X-Api-Key"}, got)
+	}
+}
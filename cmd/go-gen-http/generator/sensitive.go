@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3high "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// Sensitive lists operation values which must be redacted in logs.
+type Sensitive struct {
+	Headers []string
+	Fields  []string
+}
+
+func collectSensitive(ctx context.Context, op *v3high.Operation) (Sensitive, error) {
+	var sensitive Sensitive
+
+	for _, param := range op.Parameters {
+		if param.In != "header" {
+			continue
+		}
+
+		format := ""
+		if param.Schema != nil && param.Schema.Schema() != nil {
+			format = param.Schema.Schema().Format
+		}
+
+		ok, err := isSensitive(param.Extensions, format)
+		if err != nil {
+			return sensitive, fmt.Errorf("invalid header %q: %w", param.Name, err)
+		}
+
+		if ok {
+			sensitive.Headers = append(sensitive.Headers, param.Name)
+		}
+	}
+
+	collector := fieldCollector{visited: make(map[string]bool)}
+
+	if op.RequestBody != nil && op.RequestBody.Content != nil {
+		for media := range orderedmap.Iterate(ctx, op.RequestBody.Content) {
+			if err := collector.collect(ctx, media.Value().Schema); err != nil {
+				return sensitive, fmt.Errorf("invalid request body: %w", err)
+			}
+		}
+	}
+
+	if op.Responses != nil {
+		for code := range orderedmap.Iterate(ctx, op.Responses.Codes) {
+			if code.Value().Content == nil {
+				continue
+			}
+
+			for media := range orderedmap.Iterate(ctx, code.Value().Content) {
+				if err := collector.collect(ctx, media.Value().Schema); err != nil {
+					return sensitive, fmt.Errorf("invalid response %q: %w", code.Key(), err)
+				}
+			}
+		}
+	}
+
+	sensitive.Fields = collector.fields
+
+	return sensitive, nil
+}
+
+// schemaID identifies schema to detect recursion. libopenapi builds new schema
+// for every reference, so referenced schemas are identified by reference.
+func schemaID(proxy *base.SchemaProxy) string {
+	if reference := proxy.GetReference(); reference != "" {
+		return reference
+	}
+
+	return fmt.Sprintf("%p", proxy.Schema())
+}
+
+type fieldCollector struct {
+	visited map[string]bool
+	fields  []string
+}
+
+// collect walks schema properties and array items collecting names of
+// sensitive properties; recursive schemas are visited once.
+func (c *fieldCollector) collect(ctx context.Context, proxy *base.SchemaProxy) error {
+	if proxy == nil {
+		return nil
+	}
+
+	schema := proxy.Schema()
+	if schema == nil || c.visited[schemaID(proxy)] {
+		return nil
+	}
+
+	c.visited[schemaID(proxy)] = true
+
+	for property := range orderedmap.Iterate(ctx, schema.Properties) {
+		value := property.Value().Schema()
+		if value == nil {
+			continue
+		}
+
+		ok, err := isSensitive(value.Extensions, value.Format)
+		if err != nil {
+			return fmt.Errorf("invalid property %q: %w", property.Key(), err)
+		}
+
+		if ok && !slices.Contains(c.fields, property.Key()) {
+			c.fields = append(c.fields, property.Key())
+		}
+
+		if err := c.collect(ctx, property.Value()); err != nil {
+			return err
+		}
+	}
+
+	if schema.Items != nil && schema.Items.IsA() {
+		return c.collect(ctx, schema.Items.A)
+	}
+
+	return nil
+}
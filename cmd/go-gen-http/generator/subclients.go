@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SubClient groups operations by their first tag, e.g. Messages() returning
+// MessageServiceMessages with List and Get methods.
+type SubClient struct {
+	// Client is a canonical client name.
+	Client string
+	// Tag is an original tag.
+	Tag string
+	// Name is a name of the accessor, i.e. canonical tag.
+	Name string
+	// Type is a name of the sub-client type: client name followed by Name.
+	Type string
+	// Methods are operations in order of declaration.
+	Methods []SubClientMethod
+
+	file string
+}
+
+// SubClientMethod is a sub-client operation.
+type SubClientMethod struct {
+	// Name is an operation name without tag, e.g. "List" for "ListMessages"
+	// in "messages" tag.
+	Name string
+	Path Path
+}
+
+// collectSubClients groups tagged operations by their first tag. Untagged
+// operations are available via flat API only.
+func collectSubClients(client string, paths []Path) []SubClient {
+	result := make([]SubClient, 0)
+	index := make(map[string]int)
+
+	for _, path := range paths {
+		if len(path.Tags) == 0 {
+			continue
+		}
+
+		name := canonize(path.Tags[0])
+		if name == "" {
+			continue
+		}
+
+		i, ok := index[name]
+		if !ok {
+			i = len(result)
+			index[name] = i
+
+			result = append(result, SubClient{
+				Client: client,
+				Tag:    path.Tags[0],
+				Name:   name,
+				Type:   client + name,
+				file:   operationFile(path),
+			})
+		}
+
+		result[i].Methods = append(result[i].Methods, SubClientMethod{
+			Name: trimTag(path.CanonicalName, name),
+			Path: path,
+		})
+	}
+
+	for i := range result {
+		dedupMethods(result[i].Methods)
+	}
+
+	return result
+}
+
+// trimTag removes tag or its singular form from either end of operation
+// name, e.g. "ListMessages" and "GetMessage" become "List" and "Get" for
+// "Messages" tag. Name is kept as is if nothing's left.
+func trimTag(name, tag string) string {
+	candidates := []string{tag}
+	if singular := strings.TrimSuffix(tag, "s"); singular != tag && singular != "" {
+		candidates = append(candidates, singular)
+	}
+
+	for _, candidate := range candidates {
+		if trimmed, ok := strings.CutSuffix(name, candidate); ok && trimmed != "" {
+			return trimmed
+		}
+
+		trimmed, ok := strings.CutPrefix(name, candidate)
+		if ok && trimmed != "" && unicode.IsUpper([]rune(trimmed)[0]) {
+			return trimmed
+		}
+	}
+
+	return name
+}
+
+// dedupMethods falls back to canonical operation names for methods whose
+// trimmed names collide. Canonical names are unique, so it terminates.
+func dedupMethods(methods []SubClientMethod) {
+	for {
+		seen := make(map[string]int, len(methods))
+		for _, method := range methods {
+			seen[method.Name]++
+		}
+
+		changed := false
+
+		for i := range methods {
+			if seen[methods[i].Name] > 1 && methods[i].Name != methods[i].Path.CanonicalName {
+				methods[i].Name = methods[i].Path.CanonicalName
+				changed = true
+			}
+		}
+
+		if !changed {
+			return
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestTrimTag(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		tag  string
+		want string
+	}{
+		{name: "ListMessages", tag: "Messages", want: "List"},
+		{name: "GetMessage", tag: "Messages", want: "Get"},
+		{name: "MessagesSearch", tag: "Messages", want: "Search"},
+		{name: "Messages", tag: "Messages", want: "Messages"},
+		{name: "Messenger", tag: "Messages", want: "Messenger"},
+		{name: "GETAPIV1MessagesMessageID", tag: "Messages", want: "GETAPIV1MessagesMessageID"},
+		{name: "GetStatus", tag: "Status", want: "Get"},
+	}
+
+	for _, c := range cases {
+		if got := trimTag(c.name, c.tag); got != c.want {
+			t.Fatalf("%q in %q mismatch: want %q; got %q", c.name, c.tag, c.want, got)
+		}
+	}
+}
+
+func TestSubClients(t *testing.T) {
+	t.Parallel()
+
+	model := filterModel(t)
+
+	paths, err := CollectPaths(context.Background(), model.Paths, Filter{})
+	if err != nil {
+		t.Fatalf("could not collect paths: %v", err)
+	}
+
+	subClients := collectSubClients("ExampleService", paths)
+
+	want := map[string][]string{
+		"ExampleServiceMessages": {"List", "Create"},
+		"ExampleServiceUsers":    {"AdminGet"},
+	}
+	if len(subClients) != len(want) {
+		t.Fatalf("sub-clients mismatch: want %d; got %+v", len(want), subClients)
+	}
+
+	for _, subClient := range subClients {
+		methods := want[subClient.Type]
+		if len(methods) != len(subClient.Methods) {
+			t.Fatalf("%s methods mismatch: want %v; got %+v", subClient.Type, methods, subClient.Methods)
+		}
+
+		for i, method := range subClient.Methods {
+			if method.Name != methods[i] {
+				t.Fatalf("%s method mismatch: want %q; got %q", subClient.Type, methods[i], method.Name)
+			}
+		}
+	}
+
+	g := Generator{SubClients: true}
+	if err := g.Generate(context.Background(), *model, "ExampleService", []string{"go-gen-http"}); err != nil {
+		t.Fatalf("could not generate: %v", err)
+	}
+
+	source, err := g.Source()
+	if err != nil {
+		t.Fatalf("could not generate source: %v", err)
+	}
+
+	for _, want := range []string{
+		"func (cl *ExampleService) Messages() *ExampleServiceMessages",
+		"func (f *FakeExampleService) Messages() *ExampleServiceMessages",
+		"func (c *ExampleServiceMessages) List(",
+		"return c.api.ListMessages(ctx, request)",
+		"Users() *ExampleServiceUsers\n",
+		"func (cl *ExampleService) GETHealth(",
+	} {
+		if !bytes.Contains(source, []byte(want)) {
+			t.Fatalf("source misses %s", want)
+		}
+	}
+}
+
+func TestSubClientsDedup(t *testing.T) {
+	t.Parallel()
+
+	methods := []SubClientMethod{
+		{Name: "List", Path: Path{CanonicalName: "ListMessages"}},
+		{Name: "List", Path: Path{CanonicalName: "ListMessage"}},
+		{Name: "ListMessages", Path: Path{CanonicalName: "ListAllMessages"}},
+		{Name: "Get", Path: Path{CanonicalName: "GetMessage"}},
+	}
+
+	dedupMethods(methods)
+
+	want := []string{"ListMessages", "ListMessage", "ListAllMessages", "Get"}
+	for i, method := range methods {
+		if method.Name != want[i] {
+			t.Fatalf("method #%d mismatch: want %q; got %q", i, want[i], method.Name)
+		}
+	}
+}
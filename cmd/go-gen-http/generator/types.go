@@ -0,0 +1,188 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3high "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// goType returns Go type set with x-go-type extension of schema defined or
+// referenced by proxy, qualified with package name used by generated code,
+// e.g. "uuid.UUID"; empty string is returned if it's not set.
+func goType(proxy *base.SchemaProxy) string {
+	if proxy == nil || proxy.Schema() == nil {
+		return ""
+	}
+
+	_, typ := splitGoType(extensionValue(proxy.Schema().Extensions, extGoType))
+
+	return typ
+}
+
+// splitGoType splits x-go-type value into import path and type qualified
+// with package name: "github.com/jackc/pgx/v5/pgtype#UUID" into
+// "github.com/jackc/pgx/v5/pgtype" and "pgtype.UUID". Package name may be
+// set explicitly, e.g. "github.com/jackc/pgx/v5/pgtype#pg.UUID". Import path
+// may be separated with the last dot instead, e.g. "gopkg.in/yaml.v3.Node".
+// Types without import path, e.g. "time.Time", are returned as is.
+func splitGoType(value string) (string, string) {
+	pkgPath, name, ok := cutLast(value, "#")
+	if ok && strings.Contains(name, ".") && pkgPath != "" {
+		return pkgPath, name
+	}
+
+	if !ok {
+		slash := strings.LastIndex(value, "/")
+		if slash < 0 {
+			return "", value
+		}
+
+		if pkgPath, name, ok = cutLast(value, "."); !ok || len(pkgPath) < slash {
+			return "", value
+		}
+	}
+
+	if pkgPath == "" || name == "" {
+		return "", value
+	}
+
+	return pkgPath, importAlias(pkgPath) + "." + name
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+
+	return s, "", false
+}
+
+var errImportConflict = errors.New("x-go-type packages are imported with the same name")
+
+//nolint:gochecknoglobals // Major version suffixes of import paths.
+var (
+	majorVersion       = regexp.MustCompile(`^v[0-9]+$`)
+	majorVersionSuffix = regexp.MustCompile(`\.v[0-9]+$`)
+)
+
+// importAlias returns name of package imported for x-go-type: name used by
+// the header if it imports the package, or a name derived from import path
+// otherwise, e.g. "pgtype" for "github.com/jackc/pgx/v5/pgtype", "pgx" for
+// "github.com/jackc/pgx/v5" and "yaml" for "gopkg.in/yaml.v3". Derived names
+// may differ from package names, so the package is imported with it.
+func importAlias(pkgPath string) string {
+	if name, ok := headerImports[pkgPath]; ok {
+		return name
+	}
+
+	elems := strings.Split(pkgPath, "/")
+
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && majorVersion.MatchString(name) {
+		name = elems[len(elems)-2]
+	}
+
+	name = majorVersionSuffix.ReplaceAllString(name, "")
+	name = strings.TrimPrefix(strings.TrimSuffix(name, "-go"), "go-")
+
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, name)
+
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "pkg" + name
+	}
+
+	// Names of header imports are taken.
+	if headerNames[name] {
+		name += "pkg"
+	}
+
+	return name
+}
+
+// TypeImport is a package imported for types set with x-go-type.
+type TypeImport struct {
+	Name string
+	Path string
+}
+
+// collectTypeImports returns packages of types set with x-go-type on
+// component schemas and their inline properties and items, except ones
+// imported by header. Packages imported with the same name are reported.
+func collectTypeImports(ctx context.Context, components *v3high.Components) ([]TypeImport, error) {
+	if components == nil {
+		return nil, nil
+	}
+
+	var (
+		paths = make(map[string]string)
+		errs  []error
+		visit func(schema *base.Schema)
+	)
+
+	visit = func(schema *base.Schema) {
+		if schema == nil {
+			return
+		}
+
+		pkg, typ := splitGoType(extensionValue(schema.Extensions, extGoType))
+		name, _, _ := strings.Cut(typ, ".")
+
+		switch other, ok := paths[name]; {
+		case pkg == "" || headerImports[pkg] == name || other == pkg:
+		case ok || headerNames[name]:
+			if !ok {
+				other = "header"
+			}
+
+			errs = append(errs, fmt.Errorf("%w: %q of %s and %s", errImportConflict, name, pkg, other))
+		default:
+			paths[name] = pkg
+		}
+
+		// Referenced schemas are components, so they are visited on their
+		// own.
+		for property := range orderedmap.Iterate(ctx, schema.Properties) {
+			if !property.Value().IsReference() {
+				visit(property.Value().Schema())
+			}
+		}
+
+		if schema.Items != nil && schema.Items.IsA() && !schema.Items.A.IsReference() {
+			visit(schema.Items.A.Schema())
+		}
+	}
+
+	for pair := range orderedmap.Iterate(ctx, components.Schemas) {
+		visit(pair.Value().Schema())
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	result := make([]TypeImport, 0, len(paths))
+	for name, pkg := range paths {
+		result = append(result, TypeImport{Name: name, Path: pkg})
+	}
+
+	slices.SortFunc(result, func(a, b TypeImport) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return result, nil
+}
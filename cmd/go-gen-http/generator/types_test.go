@@ -0,0 +1,176 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+)
+
+func TestSplitGoType(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		value string
+		pkg   string
+		typ   string
+	}{
+		{value: "string", typ: "string"},
+		{value: "time.Time", typ: "time.Time"},
+		{value: "github.com/google/uuid.UUID", pkg: "github.com/google/uuid", typ: "uuid.UUID"},
+		{value: "example.com/types", typ: "example.com/types"},
+		{value: "github.com/jackc/pgx/v5/pgtype.UUID", pkg: "github.com/jackc/pgx/v5/pgtype", typ: "pgtype.UUID"},
+		{value: "github.com/jackc/pgx/v5#Conn", pkg: "github.com/jackc/pgx/v5", typ: "pgx.Conn"},
+		{value: "gopkg.in/yaml.v3.Node", pkg: "gopkg.in/yaml.v3", typ: "yaml.Node"},
+		{value: "gopkg.in/yaml.v3#Node", pkg: "gopkg.in/yaml.v3", typ: "yaml.Node"},
+		{value: "github.com/mattn/go-sqlite3#Error", pkg: "github.com/mattn/go-sqlite3", typ: "sqlite3.Error"},
+		{value: "github.com/google/uuid#id.UUID", pkg: "github.com/google/uuid", typ: "id.UUID"},
+		{value: "net/http#Header", pkg: "net/http", typ: "http.Header"},
+		{value: "example.com/config#Value", pkg: "example.com/config", typ: "configpkg.Value"},
+	}
+
+	for _, c := range cases {
+		pkg, typ := splitGoType(c.value)
+		if pkg != c.pkg || typ != c.typ {
+			t.Fatalf("%q mismatch: want %q %q; got %q %q", c.value, c.pkg, c.typ, pkg, typ)
+		}
+	}
+}
+
+const typesSpec = `
+openapi: 3.0.0
+info: {title: Example Service, version: 1.0.0}
+paths:
+  /messages:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Message'}
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Message'}
+components:
+  schemas:
+    Message:
+      type: object
+      required: [id, created]
+      properties:
+        id: {type: string, format: uuid, x-go-type: github.com/google/uuid.UUID}
+        key: {type: string, x-go-type: 'github.com/jackc/pgx/v5/pgtype#UUID'}
+        node: {type: object, x-go-type: gopkg.in/yaml.v3.Node}
+        created: {$ref: '#/components/schemas/Timestamp'}
+        updates:
+          type: array
+          items: {$ref: '#/components/schemas/Timestamp'}
+        meta: {$ref: '#/components/schemas/Meta'}
+    Timestamp: {type: string, format: date-time, x-go-type: time.Time}
+    Meta: {type: object, x-go-type: json.RawMessage}
+`
+
+func TestGoType(t *testing.T) {
+	t.Parallel()
+
+	doc, err := libopenapi.NewDocument([]byte(typesSpec))
+	if err != nil {
+		t.Fatalf("could not parse spec: %v", err)
+	}
+
+	model, errs := doc.BuildV3Model()
+	if len(errs) > 0 {
+		t.Fatalf("could not build model: %v", errs)
+	}
+
+	ctx := context.Background()
+	components := model.Model.Components
+
+	wantImports := []TypeImport{
+		{Name: "uuid", Path: "github.com/google/uuid"},
+		{Name: "pgtype", Path: "github.com/jackc/pgx/v5/pgtype"},
+		{Name: "yaml", Path: "gopkg.in/yaml.v3"},
+	}
+	if got, err := collectTypeImports(ctx, components); err != nil || !reflect.DeepEqual(got, wantImports) {
+		t.Fatalf("imports mismatch: want %v; got %v %v", wantImports, got, err)
+	}
+
+	message := components.Schemas.GetOrZero("Message").Schema()
+
+	wantProperties := []Property{
+		{Name: "ID", Type: "uuid.UUID", Tag: "id"},
+		{Name: "Key", Type: "pgtype.UUID", Tag: "key,omitempty"},
+		{Name: "Node", Type: "yaml.Node", Tag: "node,omitempty"},
+		{Name: "Created", Type: "time.Time", Tag: "created"},
+		{Name: "Updates", Type: "[]time.Time", Tag: "updates,omitempty"},
+		{Name: "Meta", Type: "json.RawMessage", Tag: "meta,omitempty"},
+	}
+	if got := collectProperties(ctx, message, ""); !reflect.DeepEqual(wantProperties, got) {
+		t.Fatalf("properties mismatch: want %+v; got %+v", wantProperties, got)
+	}
+
+	if got := componentChecks(ctx, message); len(got) != 0 {
+		t.Fatalf("unexpected checks: %v", got)
+	}
+
+	g := Generator{}
+	if err := g.Generate(ctx, model.Model, "ExampleService", []string{"go-gen-http"}); err != nil {
+		t.Fatalf("could not generate: %v", err)
+	}
+
+	source, err := g.Source()
+	if err != nil {
+		t.Fatalf("could not generate source: %v", err)
+	}
+
+	for _, want := range []string{
+		`uuid "github.com/google/uuid"`,
+		`pgtype "github.com/jackc/pgx/v5/pgtype"`,
+		`yaml "gopkg.in/yaml.v3"`,
+		"type Message struct",
+		"Body200 *Message",
+	} {
+		if !bytes.Contains(source, []byte(want)) {
+			t.Fatalf("source misses %s", want)
+		}
+	}
+
+	for _, unwanted := range []string{"type Meta struct", "type Timestamp struct", `"Meta": {`} {
+		if bytes.Contains(source, []byte(unwanted)) {
+			t.Fatalf("source contains %s", unwanted)
+		}
+	}
+}
+
+func TestCollectTypeImportsConflict(t *testing.T) {
+	t.Parallel()
+
+	const spec = `
+openapi: 3.0.0
+info: {title: Example Service, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    A: {type: string, x-go-type: 'github.com/google/uuid#UUID'}
+    B: {type: string, x-go-type: 'github.com/gofrs/uuid#UUID'}
+    C: {type: string, x-go-type: 'github.com/gofrs/uuid#gofrs.UUID'}
+`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	if err != nil {
+		t.Fatalf("could not parse spec: %v", err)
+	}
+
+	model, errs := doc.BuildV3Model()
+	if len(errs) > 0 {
+		t.Fatalf("could not build model: %v", errs)
+	}
+
+	if _, err := collectTypeImports(context.Background(), model.Model.Components); !errors.Is(err, errImportConflict) {
+		t.Fatalf("error mismatch: want %v; got %v", errImportConflict, err)
+	}
+}
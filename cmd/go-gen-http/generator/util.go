@@ -1,31 +1,174 @@
 package generator
 
 import (
+	"fmt"
+	"go/token"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
+	"unicode/utf8"
 )
 
-func canonize(path string) string {
+// commonInitialisms are upper-cased in Go identifiers, see
+// https://go.dev/wiki/CodeReviewComments#initialisms.
+//
+//nolint:gochecknoglobals // Read-only set.
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true,
+	"DNS": true, "EOF": true, "GUID": true, "HTML": true, "HTTP": true,
+	"HTTPS": true, "ID": true, "IP": true, "JSON": true, "JWT": true,
+	"LHS": true, "QPS": true, "RAM": true, "RHS": true, "RPC": true,
+	"SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true,
+	"UUID": true, "URI": true, "URL": true, "UTF8": true, "VM": true,
+	"XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// canonize converts arbitrary string, e.g. URL path, operationId or property
+// key, to exported Go identifier: words are split on characters other than
+// letters and digits and on case changes, capitalized and joined, common
+// initialisms are upper-cased, e.g. "/api/v1/users/{user_id}" becomes
+// "APIV1UsersUserID". Identifiers that can't start with upper-case letter,
+// e.g. "123abc", get "X" prefix; Go keywords are never produced since they
+// are lower-case.
+func canonize(s string) string {
 	sb := &strings.Builder{}
-	sb.Grow(len(path))
+	sb.Grow(len(s))
+
+	for _, word := range splitWords(s) {
+		_, _ = sb.WriteString(capitalize(word))
+	}
 
-	nextUpper := true
+	result := sb.String()
+	if result != "" && !token.IsExported(result) {
+		result = "X" + result
+	}
 
-	for _, r := range path {
-		if nextUpper {
-			r = unicode.ToUpper(r)
-			nextUpper = false
-		}
+	return result
+}
+
+// splitWords splits s on characters other than letters and digits and on
+// case changes: "getHTTPServer2Info" becomes "get", "HTTP", "Server2",
+// "Info".
+func splitWords(s string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+
+	runes := []rune(s)
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = word[:0]
+			}
 
-		if r == '-' || r == '/' || r == '_' {
-			nextUpper = true
 			continue
 		}
 
-		_, _ = sb.WriteRune(r)
+		if len(word) > 0 && unicode.IsUpper(r) {
+			prev := word[len(word)-1]
+			// Either "fooBar" or "v1Bar" or "HTTPServer", where "S" starts
+			// new word.
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, string(word))
+				word = word[:0]
+			}
+		}
+
+		word = append(word, r)
+	}
+
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+
+	return words
+}
+
+// capitalize upper-cases the first letter of word or the whole word if it's
+// common initialism, optionally in plural form, e.g. "ids" becomes "IDs".
+func capitalize(word string) string {
+	upper := strings.ToUpper(word)
+	if commonInitialisms[upper] {
+		return upper
+	}
+
+	if stem := upper[:len(upper)-1]; strings.HasSuffix(word, "s") && commonInitialisms[stem] {
+		return stem + "s"
+	}
+
+	r, size := utf8.DecodeRuneInString(word)
+
+	return string(unicode.ToUpper(r)) + word[size:]
+}
+
+// packageName returns Go package name for client name; Go keywords get "api"
+// suffix, e.g. client "Type" is generated in package "typeapi".
+func packageName(client string) string {
+	name := strings.ToLower(client)
+	if token.IsKeyword(name) {
+		name += "api"
+	}
+
+	return name
+}
+
+// durationLiteral returns Go expression for duration, e.g. "250 * time.Millisecond".
+func durationLiteral(duration time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{unit: time.Hour, name: "time.Hour"},
+		{unit: time.Minute, name: "time.Minute"},
+		{unit: time.Second, name: "time.Second"},
+		{unit: time.Millisecond, name: "time.Millisecond"},
+		{unit: time.Microsecond, name: "time.Microsecond"},
+	}
+
+	for _, unit := range units {
+		if duration%unit.unit == 0 {
+			return fmt.Sprintf("%d * %s", duration/unit.unit, unit.name)
+		}
+	}
+
+	return fmt.Sprintf("%d * time.Nanosecond", duration)
+}
+
+// stringSliceLiteral returns Go expression for values, e.g. `[]string{"a", "b"}`.
+func stringSliceLiteral(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, strconv.Quote(value))
+	}
+
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+// numberSliceLiteral returns Go expression for values, e.g. `[]float64{1, 2.5}`.
+func numberSliceLiteral(values []float64) string {
+	literals := make([]string, 0, len(values))
+	for _, value := range values {
+		literals = append(literals, strconv.FormatFloat(value, 'g', -1, 64))
+	}
+
+	return "[]float64{" + strings.Join(literals, ", ") + "}"
+}
+
+// stringLiteral returns Go string literal for value; raw string literal is
+// preferred for readability.
+func stringLiteral(value string) string {
+	if !strconv.CanBackquote(value) {
+		return strconv.Quote(value)
 	}
 
-	return sb.String()
+	return "`" + value + "`"
 }
 
 func must[T any](value T, err error) T {
@@ -3,6 +3,7 @@ package generator
 import (
 	"errors"
 	"testing"
+	"time"
 )
 
 func TestCanonize(t *testing.T) {
@@ -26,7 +27,97 @@ func TestCanonize(t *testing.T) {
 		{
 			name: "slash replace",
 			path: "/api/v1/path",
-			want: "ApiV1Path",
+			want: "APIV1Path",
+		},
+		{
+			name: "path params",
+			path: "/api/v1/messages/{message-id}",
+			want: "APIV1MessagesMessageID",
+		},
+		{
+			name: "underscore",
+			path: "sender_id",
+			want: "SenderID",
+		},
+		{
+			name: "camel case",
+			path: "senderId",
+			want: "SenderID",
+		},
+		{
+			name: "pascal case",
+			path: "SenderId",
+			want: "SenderID",
+		},
+		{
+			name: "initialism prefix",
+			path: "HTTPServer",
+			want: "HTTPServer",
+		},
+		{
+			name: "initialism inside",
+			path: "getHttpUrlInfo",
+			want: "GetHTTPURLInfo",
+		},
+		{
+			name: "plural initialism",
+			path: "user_ids",
+			want: "UserIDs",
+		},
+		{
+			name: "initialism prefix of word",
+			path: "idle",
+			want: "Idle",
+		},
+		{
+			name: "digits inside",
+			path: "v2Messages",
+			want: "V2Messages",
+		},
+		{
+			name: "digits first",
+			path: "123abc",
+			want: "X123abc",
+		},
+		{
+			name: "dots",
+			path: "foo.bar",
+			want: "FooBar",
+		},
+		{
+			name: "spaces",
+			path: "  list all messages ",
+			want: "ListAllMessages",
+		},
+		{
+			name: "braces only",
+			path: "{id}",
+			want: "ID",
+		},
+		{
+			name: "keyword",
+			path: "type",
+			want: "Type",
+		},
+		{
+			name: "non-ascii letters",
+			path: "über_straße",
+			want: "ÜberStraße",
+		},
+		{
+			name: "uncased letters",
+			path: "名前",
+			want: "X名前",
+		},
+		{
+			name: "symbols",
+			path: "price (€)",
+			want: "Price",
+		},
+		{
+			name: "root path",
+			path: "/",
+			want: "",
 		},
 	}
 
@@ -44,6 +135,71 @@ func TestCanonize(t *testing.T) {
 	}
 }
 
+func TestDurationLiteral(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{duration: 0, want: "0 * time.Hour"},
+		{duration: time.Hour * 2, want: "2 * time.Hour"},
+		{duration: time.Second * 90, want: "90 * time.Second"},
+		{duration: time.Millisecond * 250, want: "250 * time.Millisecond"},
+		{duration: time.Microsecond * 1500, want: "1500 * time.Microsecond"},
+		{duration: 7, want: "7 * time.Nanosecond"},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.duration.String(), func(t *testing.T) {
+			t.Parallel()
+
+			got := durationLiteral(c.duration)
+			if c.want != got {
+				t.Fatalf("mismatch: want %q; got %q", c.want, got)
+			}
+		})
+	}
+}
+
+func TestStringSliceLiteral(t *testing.T) {
+	t.Parallel()
+
+	if got, want := stringSliceLiteral(nil), "[]string{}"; got != want {
+		t.Fatalf("mismatch: want %q; got %q", want, got)
+	}
+
+	if got, want := stringSliceLiteral([]string{"a", `"b"`}), `[]string{"a", "\"b\""}`; got != want {
+		t.Fatalf("mismatch: want %q; got %q", want, got)
+	}
+}
+
+func TestStringLiteral(t *testing.T) {
+	t.Parallel()
+
+	if got, want := stringLiteral(`{"a":1}`), "`{\"a\":1}`"; got != want {
+		t.Fatalf("mismatch: want %q; got %q", want, got)
+	}
+
+	if got, want := stringLiteral("a`b"), `"a`+"`"+`b"`; got != want {
+		t.Fatalf("mismatch: want %q; got %q", want, got)
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	t.Parallel()
+
+	if got, want := packageName("MessageService"), "messageservice"; got != want {
+		t.Fatalf("mismatch: want %q; got %q", want, got)
+	}
+
+	if got, want := packageName("Type"), "typeapi"; got != want {
+		t.Fatalf("mismatch: want %q; got %q", want, got)
+	}
+}
+
 func TestMust(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,323 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3high "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/vitaminniy/go-lib-http/validate"
+)
+
+// Checks are Go statements of generated validate methods. They report
+// violations to validator v; paths are JSON pointers.
+type Checks []string
+
+// componentChecks returns checks of object schema properties. Statements use
+// receiver m and JSON pointer of the object in variable path.
+func componentChecks(ctx context.Context, schema *base.Schema) Checks {
+	var checks Checks
+
+	for property := range orderedmap.Iterate(ctx, schema.Properties) {
+		key := property.Key()
+		proxy := property.Value()
+
+		value := proxy.Schema()
+		if value == nil || len(value.Type) == 0 || goType(proxy) != "" {
+			continue
+		}
+
+		field := "m." + fieldName(key, proxy)
+		path := "path+" + strconv.Quote(validate.Field("", key))
+		required := slices.Contains(schema.Required, key)
+
+		switch value.Type[0] {
+		case "string":
+			checks = append(checks, stringChecks(value, field, path, required)...)
+		case "integer", "number":
+			checks = append(checks, numberChecks(value, field, path, required)...)
+		case "object":
+			checks = append(checks, objectChecks(proxy, field, path, required)...)
+		case "array":
+			checks = append(checks, arrayChecks(value, field, path, required)...)
+		case "boolean":
+		default:
+			if required {
+				checks = append(checks, fmt.Sprintf("v.Required(%s, %s != nil)", path, field))
+			}
+		}
+	}
+
+	return checks
+}
+
+// requestChecks returns checks of operation parameters and body. Statements
+// use receiver r.
+func requestChecks(op *v3high.Operation, body *RequestBody) Checks {
+	var checks Checks
+
+	for _, param := range op.Parameters {
+		if param.Schema == nil || param.Schema.Schema() == nil {
+			continue
+		}
+
+		schema := param.Schema.Schema()
+		path := strconv.Quote(validate.Field("/"+param.In, param.Name))
+
+		switch param.In {
+		case "header":
+			checks = append(checks, paramChecks(schema, "r.Header"+paramName(param), path, resolveptr(param.Required), false)...)
+		case "path":
+			checks = append(checks, paramChecks(schema, "r.Path"+paramName(param), path, true, false)...)
+		case "query":
+			required := resolveptr(param.Required)
+			checks = append(checks, paramChecks(schema, "r.Query"+paramName(param), path, required, !required)...)
+		}
+	}
+
+	if body != nil {
+		if body.Required {
+			checks = append(checks, `v.Required("/body", r.Body != nil)`)
+		}
+
+		if body.Reference {
+			checks = append(checks, guard("r.Body != nil", Checks{`r.Body.validate(v, "/body")`}))
+		}
+	}
+
+	return checks
+}
+
+// paramChecks returns checks of parameter field. Parameters are strings, so
+// empty ones are treated as missing unless field is a pointer, e.g. optional
+// query parameter. Numeric parameters are parsed for range and enum checks;
+// booleans are not checked.
+func paramChecks(schema *base.Schema, field, path string, required, pointer bool) Checks {
+	value, present := field, field+` != ""`
+	if pointer {
+		value, present = "*"+field, field+" != nil"
+	}
+
+	var checks Checks
+
+	if required {
+		checks = append(checks, fmt.Sprintf("v.Required(%s, %s)", path, present))
+	}
+
+	var constraints Checks
+
+	switch {
+	case len(schema.Type) == 0 || schema.Type[0] == "string":
+		constraints = constraintChecks(schema, value, path)
+	case schema.Type[0] == "integer" || schema.Type[0] == "number":
+		parse := fmt.Sprintf("v.Number(%s, %s, %t)", path, value, schema.Type[0] == "integer")
+
+		constraints = Checks{parse}
+		if numbers := constraintChecks(schema, "n", path); len(numbers) > 0 {
+			constraints = Checks{guard("n, ok := "+parse+"; ok", numbers)}
+		}
+	}
+
+	if len(constraints) > 0 {
+		checks = append(checks, guard(present, constraints))
+	}
+
+	return checks
+}
+
+// stringChecks returns checks of string property. Required properties may be
+// empty, so they are checked against constraints only, e.g. minLength; empty
+// optional strings are treated as missing and not checked.
+func stringChecks(schema *base.Schema, field, path string, required bool) Checks {
+	constraints := constraintChecks(schema, field, path)
+	if required || len(constraints) == 0 {
+		return constraints
+	}
+
+	return Checks{guard(field+` != ""`, constraints)}
+}
+
+// numberChecks returns checks of numeric property; optional numbers are
+// pointers, so explicit zero is checked as well.
+func numberChecks(schema *base.Schema, field, path string, required bool) Checks {
+	if required {
+		return constraintChecks(schema, "float64("+field+")", path)
+	}
+
+	constraints := constraintChecks(schema, "float64(*"+field+")", path)
+	if len(constraints) == 0 {
+		return nil
+	}
+
+	return Checks{guard(field+" != nil", constraints)}
+}
+
+// objectChecks validates referenced object field; unset optional objects are
+// not checked. Inline objects and x-go-type have no generated type, so they
+// are skipped.
+func objectChecks(proxy *base.SchemaProxy, field, path string, required bool) Checks {
+	if proxy.GetReference() == "" || goType(proxy) != "" {
+		return nil
+	}
+
+	check := fmt.Sprintf("%s.validate(v, %s)", field, path)
+	if required {
+		return Checks{check}
+	}
+
+	return Checks{guard("!validate.IsZero("+field+")", Checks{check})}
+}
+
+// arrayChecks returns checks of slice field and its items; nil slices are
+// treated as missing.
+func arrayChecks(schema *base.Schema, field, path string, required bool) Checks {
+	var checks Checks
+
+	if required {
+		checks = append(checks, fmt.Sprintf("v.Required(%s, %s != nil)", path, field))
+	}
+
+	constraints := constraintChecks(schema, "len("+field+")", path)
+
+	if schema.Items != nil && schema.Items.IsA() && schema.Items.A.Schema() != nil {
+		items := schema.Items.A.Schema()
+		item := "validate.Index(" + path + ", i)"
+
+		switch {
+		case goType(schema.Items.A) != "":
+		case slices.Contains(items.Type, "object") && schema.Items.A.GetReference() != "":
+			constraints = append(constraints, loop("i := range "+field, Checks{
+				fmt.Sprintf("%s[i].validate(v, %s)", field, item),
+			}))
+		case slices.Contains(items.Type, "string"):
+			if itemChecks := constraintChecks(items, "item", item); len(itemChecks) > 0 {
+				constraints = append(constraints, loop("i, item := range "+field, itemChecks))
+			}
+		}
+	}
+
+	if len(constraints) > 0 {
+		checks = append(checks, guard(field+" != nil", constraints))
+	}
+
+	return checks
+}
+
+// constraintChecks returns checks of value expression against schema
+// constraints declared for its type.
+func constraintChecks(schema *base.Schema, value, path string) Checks {
+	var checks Checks
+
+	if schema.MinLength != nil {
+		checks = append(checks, fmt.Sprintf("v.MinLength(%s, %s, %d)", path, value, *schema.MinLength))
+	}
+
+	if schema.MaxLength != nil {
+		checks = append(checks, fmt.Sprintf("v.MaxLength(%s, %s, %d)", path, value, *schema.MaxLength))
+	}
+
+	if schema.Pattern != "" {
+		if _, err := regexp.Compile(schema.Pattern); err != nil {
+			log.Printf("unsupported pattern %q is not checked: %v", schema.Pattern, err)
+		} else {
+			checks = append(checks, fmt.Sprintf("v.Pattern(%s, %s, %s)", path, value, stringLiteral(schema.Pattern)))
+		}
+	}
+
+	if slices.Contains(schema.Type, "string") && slices.Contains(validate.Formats, schema.Format) {
+		checks = append(checks, fmt.Sprintf("v.Format(%s, %s, %q)", path, value, schema.Format))
+	}
+
+	if enum := stringEnum(schema); len(enum) > 0 {
+		checks = append(checks, fmt.Sprintf("v.Enum(%s, %s, %s...)", path, value, stringSliceLiteral(enum)))
+	}
+
+	if enum := numberEnum(schema); len(enum) > 0 {
+		checks = append(checks, fmt.Sprintf("v.NumberEnum(%s, %s, %s...)", path, value, numberSliceLiteral(enum)))
+	}
+
+	if schema.Minimum != nil || (schema.ExclusiveMinimum != nil && schema.ExclusiveMinimum.IsB()) {
+		limit, exclusive := bound(schema.Minimum, schema.ExclusiveMinimum)
+		checks = append(checks, fmt.Sprintf("v.Minimum(%s, %s, %s, %t)", path, value, limit, exclusive))
+	}
+
+	if schema.Maximum != nil || (schema.ExclusiveMaximum != nil && schema.ExclusiveMaximum.IsB()) {
+		limit, exclusive := bound(schema.Maximum, schema.ExclusiveMaximum)
+		checks = append(checks, fmt.Sprintf("v.Maximum(%s, %s, %s, %t)", path, value, limit, exclusive))
+	}
+
+	if schema.MinItems != nil {
+		checks = append(checks, fmt.Sprintf("v.MinItems(%s, %s, %d)", path, value, *schema.MinItems))
+	}
+
+	if schema.MaxItems != nil {
+		checks = append(checks, fmt.Sprintf("v.MaxItems(%s, %s, %d)", path, value, *schema.MaxItems))
+	}
+
+	return checks
+}
+
+// bound returns limit literal and exclusiveness. OpenAPI 3.0 declares
+// exclusiveness as boolean next to the limit; 3.1 declares exclusive limit
+// instead of it.
+func bound(limit *float64, exclusive *base.DynamicValue[bool, float64]) (string, bool) {
+	if exclusive != nil && exclusive.IsB() {
+		return strconv.FormatFloat(exclusive.B, 'g', -1, 64), true
+	}
+
+	return strconv.FormatFloat(*limit, 'g', -1, 64), exclusive != nil && exclusive.A
+}
+
+// stringEnum returns enum values of string schema.
+func stringEnum(schema *base.Schema) []string {
+	if !slices.Contains(schema.Type, "string") || len(schema.Enum) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(schema.Enum))
+
+	for _, node := range schema.Enum {
+		var value string
+		if err := node.Decode(&value); err != nil {
+			continue
+		}
+
+		values = append(values, value)
+	}
+
+	return values
+}
+
+// numberEnum returns enum values of numeric schema.
+func numberEnum(schema *base.Schema) []float64 {
+	if !slices.Contains(schema.Type, "integer") && !slices.Contains(schema.Type, "number") {
+		return nil
+	}
+
+	values := make([]float64, 0, len(schema.Enum))
+
+	for _, node := range schema.Enum {
+		var value float64
+		if err := node.Decode(&value); err != nil {
+			continue
+		}
+
+		values = append(values, value)
+	}
+
+	return values
+}
+
+func guard(condition string, checks Checks) string {
+	return "if " + condition + " {\n" + strings.Join(checks, "\n") + "\n}"
+}
+
+func loop(clause string, checks Checks) string {
+	return "for " + clause + " {\n" + strings.Join(checks, "\n") + "\n}"
+}
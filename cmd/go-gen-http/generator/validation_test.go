@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+)
+
+const validationSpec = `
+openapi: 3.0.0
+info: {title: Example Service, version: 1.0.0}
+paths:
+  /items/{item-id}:
+    post:
+      parameters:
+        - {in: path, name: item-id, schema: {type: string, format: uuid}}
+        - {in: query, name: limit, schema: {type: integer, maximum: 10}}
+        - {in: query, name: page, required: true, schema: {type: integer, minimum: 1}}
+        - {in: query, name: ratio, required: true, schema: {type: number}}
+        - {in: query, name: sort, schema: {type: string, enum: [asc, desc]}}
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Item'}
+      responses: {}
+components:
+  schemas:
+    Item:
+      type: object
+      required: [name, title, tags]
+      properties:
+        name: {type: string, minLength: 1, pattern: '^[a-z]+$'}
+        title: {type: string}
+        a/b: {type: integer, minimum: 0, exclusiveMinimum: true}
+        priority: {type: integer, enum: [1, 2, 3]}
+        tags:
+          type: array
+          minItems: 1
+          items: {type: string, maxLength: 8}
+        parent: {$ref: '#/components/schemas/Item'}
+        flag: {type: boolean}
+`
+
+func TestChecks(t *testing.T) {
+	t.Parallel()
+
+	doc, err := libopenapi.NewDocument([]byte(validationSpec))
+	if err != nil {
+		t.Fatalf("could not parse spec: %v", err)
+	}
+
+	model, errs := doc.BuildV3Model()
+	if len(errs) > 0 {
+		t.Fatalf("could not build model: %v", errs)
+	}
+
+	ctx := context.Background()
+
+	item := model.Model.Components.Schemas.GetOrZero("Item").Schema()
+
+	want := Checks{
+		// Required strings may be empty unless constrained.
+		`v.MinLength(path+"/name", m.Name, 1)`,
+		"v.Pattern(path+\"/name\", m.Name, `^[a-z]+$`)",
+		// Optional numbers are pointers, so explicit zero is checked.
+		"if m.AB != nil {\nv.Minimum(path+\"/a~1b\", float64(*m.AB), 0, true)\n}",
+		"if m.Priority != nil {\nv.NumberEnum(path+\"/priority\", float64(*m.Priority), []float64{1, 2, 3}...)\n}",
+		`v.Required(path+"/tags", m.Tags != nil)`,
+		"if m.Tags != nil {\nv.MinItems(path+\"/tags\", len(m.Tags), 1)\n" +
+			"for i, item := range m.Tags {\nv.MaxLength(validate.Index(path+\"/tags\", i), item, 8)\n}\n}",
+		"if !validate.IsZero(m.Parent) {\nm.Parent.validate(v, path+\"/parent\")\n}",
+	}
+
+	if got := componentChecks(ctx, item); !reflect.DeepEqual(want, got) {
+		t.Fatalf("component checks mismatch:\nwant %q\ngot  %q", want, got)
+	}
+
+	paths, err := CollectPaths(ctx, model.Model.Paths, Filter{})
+	if err != nil {
+		t.Fatalf("could not collect paths: %v", err)
+	}
+
+	want = Checks{
+		`v.Required("/path/item-id", r.PathItemID != "")`,
+		"if r.PathItemID != \"\" {\nv.Format(\"/path/item-id\", r.PathItemID, \"uuid\")\n}",
+		"if r.QueryLimit != nil {\nif n, ok := v.Number(\"/query/limit\", *r.QueryLimit, true); ok {\n" +
+			"v.Maximum(\"/query/limit\", n, 10, false)\n}\n}",
+		`v.Required("/query/page", r.QueryPage != "")`,
+		"if r.QueryPage != \"\" {\nif n, ok := v.Number(\"/query/page\", r.QueryPage, true); ok {\n" +
+			"v.Minimum(\"/query/page\", n, 1, false)\n}\n}",
+		`v.Required("/query/ratio", r.QueryRatio != "")`,
+		"if r.QueryRatio != \"\" {\nv.Number(\"/query/ratio\", r.QueryRatio, false)\n}",
+		"if r.QuerySort != nil {\nv.Enum(\"/query/sort\", *r.QuerySort, []string{\"asc\", \"desc\"}...)\n}",
+		`v.Required("/body", r.Body != nil)`,
+		"if r.Body != nil {\nr.Body.validate(v, \"/body\")\n}",
+	}
+
+	if got := paths[0].Request.Checks; !reflect.DeepEqual(want, got) {
+		t.Fatalf("request checks mismatch:\nwant %q\ngot  %q", want, got)
+	}
+}
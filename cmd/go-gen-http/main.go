@@ -8,22 +8,66 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/pb33f/libopenapi"
-	"github.com/pb33f/libopenapi/datamodel"
 	"github.com/vitaminniy/go-lib-http/cmd/go-gen-http/generator"
 )
 
 var (
+	configFile = flag.String("config", "", "config file declaring generated clients; "+generator.ConfigFileName+" is used if input file is not set")
 	clientName = flag.String("client-name", "", "name of the generated client; name will be canonized; must be set")
 	output     = flag.String("output", "", "output file name; if not set, stdout will be used")
+	outputDir  = flag.String("output-dir", "", "output directory; code is split into files and stale generated files are removed")
+	pkg        = flag.String("package", "", "name of the generated package; lower-cased client name is used if not set")
+	withServer = flag.Bool("server", false, "generate server interface and http handler as well")
+	withMock   = flag.Bool("mock", false, "generate http handler serving spec examples; implies -server")
+	subClients = flag.Bool("sub-clients", false, "generate per-tag sub-clients in addition to flat API")
+	check      = flag.Bool("check", false, "compare generated code with output files instead of writing them; differences are printed as unified diff")
+	noRemote   = flag.Bool("no-remote-refs", false, "fail on remote references not mapped with -ref instead of fetching them")
+	basePath   = flag.String("base-path", "", "directory relative references are resolved against; spec directory is used if not set")
+	refs       = make(map[string]string)
+	imports    = make(map[string]string)
+)
+
+func init() {
+	flag.Func("ref", "map remote URL or URL prefix ending with / to local file or directory: URL=PATH; may be repeated", func(value string) error {
+		remote, name, ok := strings.Cut(value, "=")
+		if !ok || (!strings.HasPrefix(remote, "http://") && !strings.HasPrefix(remote, "https://")) {
+			return errInvalidRef
+		}
+
+		refs[remote] = name
+
+		return nil
+	})
+
+	flag.Func("import", "use schemas of referenced file or URL from existing Go package: FILE=IMPORTPATH; may be repeated", func(value string) error {
+		location, importPath, ok := strings.Cut(value, "=")
+		if !ok || location == "" || importPath == "" {
+			return errInvalidImport
+		}
+
+		imports[location] = importPath
+
+		return nil
+	})
+}
+
+var (
+	errOutdated      = errors.New("generated code is out of date")
+	errCheckStdout   = errors.New("-check requires output file or directory")
+	errInvalidRef    = errors.New("must be URL=PATH with http or https URL")
+	errInvalidImport = errors.New("must be FILE=IMPORTPATH")
 )
 
 func usage() {
 	fmt.Fprintf(os.Stderr, "Usage: go-gen-http [options] <input-file>\n")
 	fmt.Fprintf(os.Stderr, "\tgo-gen-http -client-name ExampleService spec.yaml\n")
+	fmt.Fprintf(os.Stderr, "\tgo-gen-http -client-name ExampleService -output-dir exampleservice spec.yaml\n")
+	fmt.Fprintf(os.Stderr, "\tgo-gen-http -config %s\n", generator.ConfigFileName)
+	fmt.Fprintf(os.Stderr, "\tgo-gen-http -check\n")
 	fmt.Fprintf(os.Stderr, "\nFlags:\n")
 
 	flag.PrintDefaults()
@@ -38,65 +82,189 @@ func main() {
 	flag.Parse()
 
 	args := flag.Args()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		log.Fatalf("could not get working directory: %v", err)
+	}
+
+	header := generator.HeaderArgs(os.Args, wd)
+
 	if len(args) == 0 {
-		flag.Usage()
-		os.Exit(1)
+		name := *configFile
+		if name == "" {
+			name = generator.ConfigFileName
+		}
+
+		file, err := generator.LoadConfigFile(name)
+		if err != nil {
+			log.Fatalf("could not load %q: %v", name, err)
+		}
+
+		outdated := false
+
+		for _, spec := range file.Specs {
+			err := generate(spec, header)
+			if errors.Is(err, errOutdated) {
+				log.Printf("%s from %q: %v", spec.Client, spec.Spec, err)
+				outdated = true
+
+				continue
+			}
+
+			if err != nil {
+				log.Fatalf("could not generate %s from %q: %v", spec.Client, spec.Spec, err)
+			}
+		}
+
+		if outdated {
+			os.Exit(1)
+		}
+
+		return
 	}
 
-	if *clientName == "" {
+	if *configFile != "" || *clientName == "" || (*output != "" && *outputDir != "") {
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	fname, err := filepath.Abs(args[0])
+	remote := !*noRemote
+
+	spec := generator.SpecConfig{
+		Spec:       args[0],
+		Client:     *clientName,
+		Package:    *pkg,
+		Output:     *output,
+		OutputDir:  *outputDir,
+		Server:     *withServer,
+		Mock:       *withMock,
+		SubClients: *subClients,
+		RemoteRefs: &remote,
+		Refs:       refs,
+		BasePath:   *basePath,
+		Imports:    imports,
+	}
+
+	if err := generate(spec, header); err != nil {
+		log.Fatalf("could not generate client: %v", err)
+	}
+}
+
+func generate(spec generator.SpecConfig, header []string) error {
+	if *check && spec.Output == "" && spec.OutputDir == "" {
+		return errCheckStdout
+	}
+
+	fname, err := filepath.Abs(spec.Spec)
 	if err != nil {
-		log.Fatalf("could not resolve file name: %v", err)
+		return fmt.Errorf("could not resolve file name: %w", err)
 	}
 
 	raw, err := os.ReadFile(fname)
 	if err != nil {
-		log.Fatalf("could not read file %q: %v", args[0], err)
+		return fmt.Errorf("could not read file %q: %w", spec.Spec, err)
+	}
+
+	// Spec caches referenced documents, so bundling reuses ones read by the
+	// check instead of fetching them again.
+	if err = spec.CheckRefs(raw); err != nil {
+		return err
+	}
+
+	raw, err = spec.BundleRefs(raw)
+	if err != nil {
+		return fmt.Errorf("could not bundle references: %w", err)
+	}
+
+	raw, err = spec.ApplyOverrides(raw)
+	if err != nil {
+		return fmt.Errorf("could not apply overrides: %w", err)
 	}
 
-	cfg := datamodel.NewDocumentConfiguration()
-	cfg.BasePath = path.Dir(fname)
-	cfg.AllowFileReferences = true
-	cfg.AllowRemoteReferences = true
-	cfg.ExtractRefsSequentially = true
-	cfg.BundleInlineRefs = true
+	cfg, err := spec.DocumentConfiguration()
+	if err != nil {
+		return err
+	}
 
 	doc, err := libopenapi.NewDocumentWithConfiguration(raw, cfg)
 	if err != nil {
-		log.Fatalf("could not parse open api document: %v", err)
+		return fmt.Errorf("could not parse open api document: %w", err)
 	}
 
 	model, errs := doc.BuildV3Model()
 	if len(errs) > 0 {
-		log.Fatalf("could prepare open api model: %v", errors.Join(errs...))
+		return fmt.Errorf("could prepare open api model: %w", errors.Join(errs...))
 	}
 
 	if model.Model.Paths == nil {
-		log.Fatalf("document %q doesn't contain paths", args[0])
+		return fmt.Errorf("document %q doesn't contain paths", spec.Spec)
 	}
 
 	ctx := context.Background()
-	g := generator.Generator{}
+	g := spec.Generator()
 
-	if err = g.Generate(ctx, model.Model, *clientName, os.Args); err != nil {
-		log.Fatalf("could not generate client: %v", err)
+	if err = g.Generate(ctx, model.Model, spec.Client, header); err != nil {
+		return err
+	}
+
+	if spec.OutputDir != "" {
+		files, err := g.Files()
+		if err != nil {
+			return err
+		}
+
+		if *check {
+			diff, err := generator.CheckDir(spec.OutputDir, files)
+			if err != nil {
+				return fmt.Errorf("could not check directory %q: %w", spec.OutputDir, err)
+			}
+
+			return reportDiff(diff)
+		}
+
+		if err = generator.WriteDir(spec.OutputDir, files); err != nil {
+			return fmt.Errorf("could not write directory %q: %w", spec.OutputDir, err)
+		}
+
+		return nil
 	}
 
 	source, err := g.Source()
 	if err != nil {
-		log.Fatalf("could not generate client: %v", err)
+		return err
 	}
 
-	if *output == "" {
+	if *check {
+		diff, err := generator.CheckFile(spec.Output, source)
+		if err != nil {
+			return fmt.Errorf("could not check file %q: %w", spec.Output, err)
+		}
+
+		return reportDiff(diff)
+	}
+
+	if spec.Output == "" {
 		fmt.Println(string(source))
-		return
+		return nil
 	}
 
-	if err = os.WriteFile(*output, source, 0644); err != nil {
-		log.Fatalf("could not wirte file %v", err)
+	if err = os.WriteFile(spec.Output, source, 0644); err != nil {
+		return fmt.Errorf("could not wirte file %w", err)
 	}
+
+	return nil
+}
+
+// reportDiff prints diff of generated code and fails if there's one.
+func reportDiff(diff []byte) error {
+	if len(diff) == 0 {
+		return nil
+	}
+
+	if _, err := os.Stdout.Write(diff); err != nil {
+		return fmt.Errorf("could not print diff: %w", err)
+	}
+
+	return errOutdated
 }
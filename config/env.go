@@ -0,0 +1,140 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// EnvLayer reads config layer from environment variables. See FromEnv for
+// variables naming.
+func EnvLayer[T any](prefix string) (Layer[T], error) {
+	cfg, err := FromEnv[T](prefix, os.LookupEnv)
+	if err != nil {
+		return Layer[T]{}, err
+	}
+
+	return Layer[T]{Name: LayerEnv, Config: cfg}, nil
+}
+
+// FromEnv reads config from variables returned by lookup.
+//
+// Variable name is built from prefix and field names joined with underscore
+// and uppercased, e.g. MESSAGESERVICE_GETAPIV1MESSAGES_TIMEOUT for
+// Config.GETAPIV1Messages.Timeout with prefix "MessageService". Durations are
+// parsed with time.ParseDuration. Missing variables leave fields unset, set
+// ones are stored to pointer fields even if zero.
+func FromEnv[T any](prefix string, lookup func(key string) (string, bool)) (T, error) {
+	var (
+		cfg  T
+		errs []error
+	)
+
+	fromEnv(reflect.ValueOf(&cfg).Elem(), strings.ToUpper(prefix), lookup, &errs)
+
+	if len(errs) > 0 {
+		return cfg, fmt.Errorf("config: invalid environment: %w", errors.Join(errs...))
+	}
+
+	return cfg, nil
+}
+
+func fromEnv(
+	value reflect.Value,
+	key string,
+	lookup func(key string) (string, bool),
+	errs *[]error,
+) {
+	if value.Kind() == reflect.Struct {
+		typ := value.Type()
+
+		for i := range typ.NumField() {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			fieldKey := joinPath(key, strings.ToUpper(field.Name), "_")
+			fromEnv(value.Field(i), fieldKey, lookup, errs)
+		}
+
+		return
+	}
+
+	raw, ok := lookup(key)
+	if !ok {
+		return
+	}
+
+	if err := parseValue(value, raw); err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %w", key, err))
+	}
+}
+
+func parseValue(value reflect.Value, raw string) error {
+	if value.Kind() == reflect.Pointer {
+		elem := reflect.New(value.Type().Elem())
+		if err := parseValue(elem.Elem(), raw); err != nil {
+			return err
+		}
+
+		value.Set(elem)
+
+		return nil
+	}
+
+	if value.Type() == durationType {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return err //nolint:wrapcheck // Key is added by caller.
+		}
+
+		value.SetInt(int64(duration))
+
+		return nil
+	}
+
+	//nolint:exhaustive // Unsupported kinds are handled by default.
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err //nolint:wrapcheck // Key is added by caller.
+		}
+
+		value.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err //nolint:wrapcheck // Key is added by caller.
+		}
+
+		value.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err //nolint:wrapcheck // Key is added by caller.
+		}
+
+		value.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return err //nolint:wrapcheck // Key is added by caller.
+		}
+
+		value.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+
+	return nil
+}
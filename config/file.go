@@ -0,0 +1,179 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPollInterval is a default interval between config file checks.
+const DefaultPollInterval = time.Second * 5
+
+// DecodeFile decodes YAML or JSON config into cfg. JSON is decoded as YAML
+// since it's a subset of it.
+//
+// Keys are taken from `yaml` struct tags; durations are expected as strings
+// parsable by time.ParseDuration, e.g. "250ms". Unknown keys are rejected to
+// catch typos.
+func DecodeFile[T any](raw []byte, cfg *T) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(raw))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("could not decode config: %w", err)
+	}
+
+	return nil
+}
+
+// FileLayer reads config layer from YAML or JSON file. See DecodeFile for
+// file format.
+func FileLayer[T any](path string) (Layer[T], error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Layer[T]{}, fmt.Errorf("could not read config file %q: %w", path, err)
+	}
+
+	var cfg T
+	if err := DecodeFile(raw, &cfg); err != nil {
+		return Layer[T]{}, fmt.Errorf("config file %q: %w", path, err)
+	}
+
+	return Layer[T]{Name: LayerFile, Config: cfg}, nil
+}
+
+// FileSourceOption overrides FileSource creation.
+type FileSourceOption[T any] func(*FileSource[T])
+
+// WithPollInterval overrides the default file poll interval.
+func WithPollInterval[T any](interval time.Duration) FileSourceOption[T] {
+	return func(src *FileSource[T]) {
+		src.interval = interval
+	}
+}
+
+// WithErrorHandler sets function which is called on every reload error.
+func WithErrorHandler[T any](handler func(error)) FileSourceOption[T] {
+	return func(src *FileSource[T]) {
+		src.onError = handler
+	}
+}
+
+// WithDefaults sets config which file values are decoded on top of. Keys
+// missing in file keep their default values.
+func WithDefaults[T any](defaults T) FileSourceOption[T] {
+	return func(src *FileSource[T]) {
+		src.defaults = defaults
+	}
+}
+
+// NewFileSource creates new file-backed config source which pushes file
+// contents into snapshot.
+func NewFileSource[T any](
+	path string,
+	snapshot *Snapshot[T],
+	opts ...FileSourceOption[T],
+) *FileSource[T] {
+	src := &FileSource[T]{
+		path:     path,
+		snapshot: snapshot,
+		interval: DefaultPollInterval,
+		onError:  func(error) {},
+	}
+
+	for _, opt := range opts {
+		opt(src)
+	}
+
+	return src
+}
+
+// FileSource loads config from YAML or JSON file into Snapshot and reloads it
+// when file changes.
+//
+// Changes are detected by polling file modification time and size; file
+// contents are hashed so touching file without changing it does not trigger
+// an update. Invalid configs are rejected and the last good config is kept.
+type FileSource[T any] struct {
+	path     string
+	snapshot *Snapshot[T]
+	interval time.Duration
+	onError  func(error)
+	defaults T
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	hash    [sha256.Size]byte
+	loaded  bool
+}
+
+// Load reads file and updates snapshot if file contents changed since the
+// last load.
+func (src *FileSource[T]) Load() error {
+	src.mu.Lock()
+	defer src.mu.Unlock()
+
+	info, err := os.Stat(src.path)
+	if err != nil {
+		return fmt.Errorf("could not stat config file %q: %w", src.path, err)
+	}
+
+	if src.loaded && info.ModTime().Equal(src.modTime) && info.Size() == src.size {
+		return nil
+	}
+
+	raw, err := os.ReadFile(src.path)
+	if err != nil {
+		return fmt.Errorf("could not read config file %q: %w", src.path, err)
+	}
+
+	hash := sha256.Sum256(raw)
+	unchanged := src.loaded && hash == src.hash
+
+	// Remember contents even if they turn out to be invalid, so the same
+	// error is not reported on every poll.
+	src.modTime, src.size, src.hash, src.loaded = info.ModTime(), info.Size(), hash, true
+
+	if unchanged {
+		return nil
+	}
+
+	cfg := src.defaults
+	if err := DecodeFile(raw, &cfg); err != nil {
+		return fmt.Errorf("config file %q: %w", src.path, err)
+	}
+
+	if err := src.snapshot.Update(cfg); err != nil {
+		return fmt.Errorf("config file %q: %w", src.path, err)
+	}
+
+	return nil
+}
+
+// Watch loads file immediately and then polls it for changes until ctx is
+// done. Reload errors are reported to error handler.
+func (src *FileSource[T]) Watch(ctx context.Context) {
+	ticker := time.NewTicker(src.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := src.Load(); err != nil {
+			src.onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
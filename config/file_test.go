@@ -0,0 +1,235 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testMethodConfig struct {
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type testConfig struct {
+	GETApiV1Messages testMethodConfig `yaml:"GETApiV1Messages"`
+	POSTApiV1Message testMethodConfig `yaml:"POSTApiV1Message"`
+}
+
+func TestDecodeFile(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		raw     string
+		want    testConfig
+		wantErr bool
+	}{
+		{
+			name: "yaml",
+			raw:  "GETApiV1Messages:\n  timeout: 250ms\n",
+			want: testConfig{
+				GETApiV1Messages: testMethodConfig{Timeout: time.Millisecond * 250},
+			},
+		},
+		{
+			name: "json",
+			raw:  `{"POSTApiV1Message": {"timeout": "1s"}}`,
+			want: testConfig{
+				POSTApiV1Message: testMethodConfig{Timeout: time.Second},
+			},
+		},
+		{
+			name: "empty",
+			raw:  "",
+			want: testConfig{},
+		},
+		{
+			name:    "unknown key",
+			raw:     "GETApiV1Message:\n  timeout: 250ms\n",
+			wantErr: true,
+		},
+		{
+			name:    "duration without unit",
+			raw:     "GETApiV1Messages:\n  timeout: 250\n",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got testConfig
+
+			err := DecodeFile([]byte(c.raw), &got)
+			if c.wantErr != (err != nil) {
+				t.Fatalf("error mismatch: want error %t; got %v", c.wantErr, err)
+			}
+
+			if !c.wantErr && got != c.want {
+				t.Fatalf("mismatch: want %+v; got %+v", c.want, got)
+			}
+		})
+	}
+}
+
+// writeConfig writes config file and moves its modification time forward so
+// the change is noticed regardless of file system time resolution.
+func writeConfig(t *testing.T, path, raw string) {
+	t.Helper()
+
+	var modTime time.Time
+
+	if info, err := os.Stat(path); err == nil {
+		modTime = info.ModTime()
+	}
+
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatalf("could not write config: %v", err)
+	}
+
+	modTime = modTime.Add(time.Second)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("could not change config modification time: %v", err)
+	}
+}
+
+func TestFileSourceLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "GETApiV1Messages:\n  timeout: 250ms\n")
+
+	defaults := testConfig{POSTApiV1Message: testMethodConfig{Timeout: time.Second}}
+	snapshot := NewSnapshot(testConfig{})
+	src := NewFileSource(path, snapshot, WithDefaults(defaults))
+
+	if err := src.Load(); err != nil {
+		t.Fatalf("could not load config: %v", err)
+	}
+
+	want := testConfig{
+		GETApiV1Messages: testMethodConfig{Timeout: time.Millisecond * 250},
+		POSTApiV1Message: testMethodConfig{Timeout: time.Second},
+	}
+
+	got, version := snapshot.Get()
+	if got != want || version != 2 {
+		t.Fatalf("mismatch: want %+v@2; got %+v@%d", want, got, version)
+	}
+
+	// Same contents must not produce an update.
+	writeConfig(t, path, "GETApiV1Messages:\n  timeout: 250ms\n")
+
+	if err := src.Load(); err != nil {
+		t.Fatalf("could not reload config: %v", err)
+	}
+
+	if _, version := snapshot.Get(); version != 2 {
+		t.Fatalf("unexpected update: version %d", version)
+	}
+
+	writeConfig(t, path, "GETApiV1Messages:\n  timeout: 500ms\n")
+
+	if err := src.Load(); err != nil {
+		t.Fatalf("could not reload config: %v", err)
+	}
+
+	want.GETApiV1Messages.Timeout = time.Millisecond * 500
+
+	got, version = snapshot.Get()
+	if got != want || version != 3 {
+		t.Fatalf("mismatch: want %+v@3; got %+v@%d", want, got, version)
+	}
+}
+
+func TestFileSourceKeepsLastGoodConfig(t *testing.T) {
+	t.Parallel()
+
+	errNoTimeout := errors.New("timeout must be set")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "GETApiV1Messages:\n  timeout: 250ms\n")
+
+	snapshot := NewSnapshot(testConfig{})
+	snapshot.AddValidator(func(cfg testConfig) error {
+		if cfg.GETApiV1Messages.Timeout == 0 {
+			return errNoTimeout
+		}
+
+		return nil
+	})
+
+	src := NewFileSource(path, snapshot)
+	if err := src.Load(); err != nil {
+		t.Fatalf("could not load config: %v", err)
+	}
+
+	want, _ := snapshot.Get()
+
+	writeConfig(t, path, "GETApiV1Messages: [")
+
+	if err := src.Load(); err == nil {
+		t.Fatal("expected parse error but got nil")
+	}
+
+	writeConfig(t, path, "GETApiV1Messages:\n  timeout: 0s\n")
+
+	err := src.Load()
+	compareErrors(t, errNoTimeout, err)
+
+	if got, version := snapshot.Get(); got != want || version != 2 {
+		t.Fatalf("last good config lost: want %+v@2; got %+v@%d", want, got, version)
+	}
+}
+
+func TestFileSourceWatch(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "GETApiV1Messages: [")
+
+	errs := make(chan error, 1)
+
+	snapshot := NewSnapshot(testConfig{})
+	src := NewFileSource(
+		path,
+		snapshot,
+		WithPollInterval[testConfig](time.Millisecond*10),
+		WithErrorHandler[testConfig](func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		}),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := snapshot.Changes(ctx)
+
+	go src.Watch(ctx)
+
+	select {
+	case <-errs:
+	case <-time.After(time.Second):
+		t.Fatal("reload error was not reported")
+	}
+
+	writeConfig(t, path, "GETApiV1Messages:\n  timeout: 250ms\n")
+
+	select {
+	case got := <-changes:
+		if got.GETApiV1Messages.Timeout != time.Millisecond*250 {
+			t.Fatalf("mismatch: want %v; got %v", time.Millisecond*250, got.GETApiV1Messages.Timeout)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("config was not reloaded")
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Well-known layer names in order of increasing priority.
+const (
+	LayerSpec    = "spec"
+	LayerFile    = "file"
+	LayerEnv     = "env"
+	LayerRuntime = "runtime"
+)
+
+// Layer is a named partial config. Zero-valued fields are treated as unset and
+// do not override values from lower layers; use pointer fields for values
+// which may be overridden with zero, e.g. false.
+type Layer[T any] struct {
+	Name   string
+	Config T
+}
+
+// Origin describes which layer set a config value.
+type Origin struct {
+	Layer string
+	Value any
+}
+
+// Origins maps config field path, e.g. "GETAPIV1Messages.Timeout", to its
+// origin.
+type Origins map[string]Origin
+
+// Explain returns human-readable description of which layer set each value,
+// one value per line sorted by field path.
+func (o Origins) Explain() string {
+	paths := make([]string, 0, len(o))
+	for path := range o {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	sb := &strings.Builder{}
+
+	for _, path := range paths {
+		origin := o[path]
+		fmt.Fprintf(sb, "%s = %v (%s)\n", path, origin.Value, origin.Layer)
+	}
+
+	return sb.String()
+}
+
+// Resolve merges layers in order of increasing priority: non-zero fields of
+// later layers override fields of earlier ones. Struct fields are merged
+// field by field; other values are replaced as a whole.
+func Resolve[T any](layers ...Layer[T]) (T, Origins) {
+	var result T
+
+	origins := make(Origins)
+
+	for _, layer := range layers {
+		merge(
+			reflect.ValueOf(&result).Elem(),
+			reflect.ValueOf(layer.Config),
+			"",
+			layer.Name,
+			origins,
+		)
+	}
+
+	return result, origins
+}
+
+func merge(dst, src reflect.Value, path, layer string, origins Origins) {
+	if src.Kind() == reflect.Struct {
+		typ := src.Type()
+
+		for i := range typ.NumField() {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			merge(dst.Field(i), src.Field(i), joinPath(path, field.Name, "."), layer, origins)
+		}
+
+		return
+	}
+
+	if src.IsZero() {
+		return
+	}
+
+	dst.Set(src)
+
+	value := src
+	if value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+
+	origins[path] = Origin{
+		Layer: layer,
+		Value: value.Interface(),
+	}
+}
+
+func joinPath(path, name, sep string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + sep + name
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testRetry struct {
+	Attempts uint
+	Backoff  time.Duration
+}
+
+type testLayeredMethodConfig struct {
+	Timeout time.Duration `yaml:"timeout"`
+	Retry   testRetry     `yaml:"retry"`
+}
+
+type testLayeredConfig struct {
+	Default          testLayeredMethodConfig `yaml:"Default"`
+	GETApiV1Messages testLayeredMethodConfig `yaml:"GETApiV1Messages"`
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	spec := Layer[testLayeredConfig]{
+		Name: LayerSpec,
+		Config: testLayeredConfig{
+			Default: testLayeredMethodConfig{Timeout: time.Second},
+			GETApiV1Messages: testLayeredMethodConfig{
+				Timeout: time.Millisecond * 250,
+				Retry:   testRetry{Attempts: 3, Backoff: time.Millisecond * 10},
+			},
+		},
+	}
+
+	env := Layer[testLayeredConfig]{
+		Name: LayerEnv,
+		Config: testLayeredConfig{
+			GETApiV1Messages: testLayeredMethodConfig{
+				Retry: testRetry{Attempts: 5},
+			},
+		},
+	}
+
+	got, origins := Resolve(spec, env)
+
+	want := testLayeredConfig{
+		Default: testLayeredMethodConfig{Timeout: time.Second},
+		GETApiV1Messages: testLayeredMethodConfig{
+			Timeout: time.Millisecond * 250,
+			Retry:   testRetry{Attempts: 5, Backoff: time.Millisecond * 10},
+		},
+	}
+
+	if got != want {
+		t.Fatalf("mismatch: want %+v; got %+v", want, got)
+	}
+
+	wantExplain := "" +
+		"Default.Timeout = 1s (spec)\n" +
+		"GETApiV1Messages.Retry.Attempts = 5 (env)\n" +
+		"GETApiV1Messages.Retry.Backoff = 10ms (spec)\n" +
+		"GETApiV1Messages.Timeout = 250ms (spec)\n"
+
+	if explain := origins.Explain(); explain != wantExplain {
+		t.Fatalf("explain mismatch:\nwant:\n%s\ngot:\n%s", wantExplain, explain)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		env     map[string]string
+		want    testLayeredConfig
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			env:  map[string]string{},
+			want: testLayeredConfig{},
+		},
+		{
+			name: "nested fields",
+			env: map[string]string{
+				"MESSAGESERVICE_DEFAULT_TIMEOUT":                 "1s",
+				"MESSAGESERVICE_GETAPIV1MESSAGES_TIMEOUT":        "250ms",
+				"MESSAGESERVICE_GETAPIV1MESSAGES_RETRY_ATTEMPTS": "3",
+			},
+			want: testLayeredConfig{
+				Default: testLayeredMethodConfig{Timeout: time.Second},
+				GETApiV1Messages: testLayeredMethodConfig{
+					Timeout: time.Millisecond * 250,
+					Retry:   testRetry{Attempts: 3},
+				},
+			},
+		},
+		{
+			name: "invalid duration",
+			env: map[string]string{
+				"MESSAGESERVICE_GETAPIV1MESSAGES_TIMEOUT": "250",
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative attempts",
+			env: map[string]string{
+				"MESSAGESERVICE_GETAPIV1MESSAGES_RETRY_ATTEMPTS": "-1",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := func(key string) (string, bool) {
+				value, ok := c.env[key]
+				return value, ok
+			}
+
+			got, err := FromEnv[testLayeredConfig]("MessageService", lookup)
+			if c.wantErr != (err != nil) {
+				t.Fatalf("error mismatch: want error %t; got %v", c.wantErr, err)
+			}
+
+			if !c.wantErr && got != c.want {
+				t.Fatalf("mismatch: want %+v; got %+v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestResolveExplicitZero(t *testing.T) {
+	t.Parallel()
+
+	file := Layer[QOS]{
+		Name: LayerFile,
+		Config: QOS{
+			Timeout:    Ptr(time.Second),
+			Validation: Validation{StrictResponse: Ptr(true)},
+		},
+	}
+
+	lookup := func(key string) (string, bool) {
+		value, ok := map[string]string{
+			"MESSAGESERVICE_TIMEOUT":                   "0s",
+			"MESSAGESERVICE_VALIDATION_STRICTRESPONSE": "false",
+		}[key]
+
+		return value, ok
+	}
+
+	cfg, err := FromEnv[QOS]("MessageService", lookup)
+	if err != nil {
+		t.Fatalf("could not read env: %v", err)
+	}
+
+	got, origins := Resolve(file, Layer[QOS]{Name: LayerEnv, Config: cfg})
+
+	want := QOS{
+		Timeout:    Ptr(time.Duration(0)),
+		Validation: Validation{StrictResponse: Ptr(false)},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("mismatch: want %+v; got %+v", want, got)
+	}
+
+	wantExplain := "" +
+		"Timeout = 0s (env)\n" +
+		"Validation.StrictResponse = false (env)\n"
+
+	if explain := origins.Explain(); explain != wantExplain {
+		t.Fatalf("explain mismatch:\nwant:\n%s\ngot:\n%s", wantExplain, explain)
+	}
+}
+
+func TestFileLayer(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	raw := "GETApiV1Messages:\n  timeout: 250ms\n  retry:\n    attempts: 2\n"
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatalf("could not write config: %v", err)
+	}
+
+	layer, err := FileLayer[testLayeredConfig](path)
+	if err != nil {
+		t.Fatalf("could not read file layer: %v", err)
+	}
+
+	want := testLayeredConfig{
+		GETApiV1Messages: testLayeredMethodConfig{
+			Timeout: time.Millisecond * 250,
+			Retry:   testRetry{Attempts: 2},
+		},
+	}
+
+	if layer.Name != LayerFile || layer.Config != want {
+		t.Fatalf("mismatch: want %+v; got %s %+v", want, layer.Name, layer.Config)
+	}
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"context"
+	"time"
+
+	"github.com/vitaminniy/go-lib-http/breaker"
+	"github.com/vitaminniy/go-lib-http/deadline"
+	"github.com/vitaminniy/go-lib-http/hedge"
+	"github.com/vitaminniy/go-lib-http/limiter"
+	"github.com/vitaminniy/go-lib-http/retry"
+	"github.com/vitaminniy/go-lib-http/validate"
+)
+
+// QOS is a per-call policy.
+//
+// Nil fields are treated as unset, so partial policies can be merged on top
+// of each other with Merge, and explicit zero, e.g. false or 0s, overrides a
+// value set below. Use Ptr to set fields in code. Groups are resolved to
+// configs of policy packages with their Config methods; unset fields are
+// zero.
+type QOS struct {
+	// Timeout limits the whole call including retries.
+	Timeout *time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Retry   Retry          `json:"retry" yaml:"retry,omitempty"`
+	Hedging Hedging        `json:"hedging" yaml:"hedging,omitempty"`
+	Breaker Breaker        `json:"breaker" yaml:"breaker,omitempty"`
+	Limiter Limiter        `json:"limiter" yaml:"limiter,omitempty"`
+	// Deadline controls propagation of remaining budget to upstream.
+	Deadline Deadline `json:"deadline" yaml:"deadline,omitempty"`
+	// Validation controls checks of requests and responses against schema.
+	Validation Validation `json:"validation" yaml:"validation,omitempty"`
+}
+
+// Retry is a partial retry.Config.
+type Retry struct {
+	Attempts *uint          `json:"attempts,omitempty" yaml:"attempts,omitempty"`
+	Backoff  *time.Duration `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+	Jitter   *time.Duration `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+}
+
+// Config returns retry config with unset fields zeroed.
+func (r Retry) Config() retry.Config {
+	return retry.Config{
+		Attempts: valueOf(r.Attempts),
+		Backoff:  valueOf(r.Backoff),
+		Jitter:   valueOf(r.Jitter),
+	}
+}
+
+// Hedging is a partial hedge.Config.
+type Hedging struct {
+	Delay    *time.Duration `json:"delay,omitempty" yaml:"delay,omitempty"`
+	Attempts *uint          `json:"attempts,omitempty" yaml:"attempts,omitempty"`
+}
+
+// Config returns hedging config with unset fields zeroed.
+func (h Hedging) Config() hedge.Config {
+	return hedge.Config{
+		Delay:    valueOf(h.Delay),
+		Attempts: valueOf(h.Attempts),
+	}
+}
+
+// Breaker is a partial breaker.Config.
+type Breaker struct {
+	Failures *uint          `json:"failures,omitempty" yaml:"failures,omitempty"`
+	Cooldown *time.Duration `json:"cooldown,omitempty" yaml:"cooldown,omitempty"`
+}
+
+// Config returns circuit breaker config with unset fields zeroed.
+func (b Breaker) Config() breaker.Config {
+	return breaker.Config{
+		Failures: valueOf(b.Failures),
+		Cooldown: valueOf(b.Cooldown),
+	}
+}
+
+// Limiter is a partial limiter.Config.
+type Limiter struct {
+	Rate  *float64 `json:"rate,omitempty" yaml:"rate,omitempty"`
+	Burst *uint    `json:"burst,omitempty" yaml:"burst,omitempty"`
+}
+
+// Config returns rate limiter config with unset fields zeroed.
+func (l Limiter) Config() limiter.Config {
+	return limiter.Config{
+		Rate:  valueOf(l.Rate),
+		Burst: valueOf(l.Burst),
+	}
+}
+
+// Deadline is a partial deadline.Config.
+type Deadline struct {
+	Header *string          `json:"header,omitempty" yaml:"header,omitempty"`
+	Format *deadline.Format `json:"format,omitempty" yaml:"format,omitempty"`
+	Margin *time.Duration   `json:"margin,omitempty" yaml:"margin,omitempty"`
+}
+
+// Config returns deadline propagation config with unset fields zeroed.
+func (d Deadline) Config() deadline.Config {
+	return deadline.Config{
+		Header: valueOf(d.Header),
+		Format: valueOf(d.Format),
+		Margin: valueOf(d.Margin),
+	}
+}
+
+// Validation is a partial validate.Config.
+type Validation struct {
+	SkipRequest    *bool `json:"skipRequest,omitempty" yaml:"skipRequest,omitempty"`
+	StrictResponse *bool `json:"strictResponse,omitempty" yaml:"strictResponse,omitempty"`
+}
+
+// Config returns validation config with unset fields zeroed.
+func (v Validation) Config() validate.Config {
+	return validate.Config{
+		SkipRequest:    valueOf(v.SkipRequest),
+		StrictResponse: valueOf(v.StrictResponse),
+	}
+}
+
+// Merge returns q with fields set in override replaced, so a partial override
+// inherits the rest of settings from q.
+//
+//nolint:cyclop // Flat list of fields.
+func (q QOS) Merge(override QOS) QOS {
+	mergeValue(&q.Timeout, override.Timeout)
+
+	mergeValue(&q.Retry.Attempts, override.Retry.Attempts)
+	mergeValue(&q.Retry.Backoff, override.Retry.Backoff)
+	mergeValue(&q.Retry.Jitter, override.Retry.Jitter)
+
+	mergeValue(&q.Hedging.Delay, override.Hedging.Delay)
+	mergeValue(&q.Hedging.Attempts, override.Hedging.Attempts)
+
+	mergeValue(&q.Breaker.Failures, override.Breaker.Failures)
+	mergeValue(&q.Breaker.Cooldown, override.Breaker.Cooldown)
+
+	mergeValue(&q.Limiter.Rate, override.Limiter.Rate)
+	mergeValue(&q.Limiter.Burst, override.Limiter.Burst)
+
+	mergeValue(&q.Deadline.Header, override.Deadline.Header)
+	mergeValue(&q.Deadline.Format, override.Deadline.Format)
+	mergeValue(&q.Deadline.Margin, override.Deadline.Margin)
+
+	mergeValue(&q.Validation.SkipRequest, override.Validation.SkipRequest)
+	mergeValue(&q.Validation.StrictResponse, override.Validation.StrictResponse)
+
+	return q
+}
+
+// Context returns ctx with applied timeout.
+func (q *QOS) Context(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := valueOf(q.Timeout)
+	if timeout == 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Ptr returns pointer to value, e.g. to set QOS fields in code.
+func Ptr[T any](value T) *T {
+	return &value
+}
+
+func mergeValue[T any](dst **T, override *T) {
+	if override != nil {
+		*dst = override
+	}
+}
+
+// valueOf returns value of set field or zero.
+func valueOf[T any](field *T) T {
+	if field == nil {
+		var zero T
+		return zero
+	}
+
+	return *field
+}
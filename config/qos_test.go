@@ -0,0 +1,146 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vitaminniy/go-lib-http/breaker"
+	"github.com/vitaminniy/go-lib-http/deadline"
+	"github.com/vitaminniy/go-lib-http/retry"
+)
+
+func TestQOSMerge(t *testing.T) {
+	t.Parallel()
+
+	base := QOS{
+		Timeout: Ptr(time.Second),
+		Retry:   Retry{Attempts: Ptr[uint](3), Backoff: Ptr(time.Millisecond * 10)},
+		Breaker: Breaker{Failures: Ptr[uint](5), Cooldown: Ptr(time.Second)},
+	}
+
+	override := QOS{
+		Timeout: Ptr(time.Millisecond * 250),
+		Retry:   Retry{Attempts: Ptr[uint](1)},
+	}
+
+	want := QOS{
+		Timeout: Ptr(time.Millisecond * 250),
+		Retry:   Retry{Attempts: Ptr[uint](1), Backoff: Ptr(time.Millisecond * 10)},
+		Breaker: Breaker{Failures: Ptr[uint](5), Cooldown: Ptr(time.Second)},
+	}
+
+	if got := base.Merge(override); !reflect.DeepEqual(got, want) {
+		t.Fatalf("mismatch: want %+v; got %+v", want, got)
+	}
+
+	if got := base.Merge(QOS{}); !reflect.DeepEqual(got, base) {
+		t.Fatalf("empty override changed policy: want %+v; got %+v", base, got)
+	}
+}
+
+func TestQOSMergeZero(t *testing.T) {
+	t.Parallel()
+
+	base := QOS{
+		Timeout:    Ptr(time.Second),
+		Breaker:    Breaker{Failures: Ptr[uint](5)},
+		Validation: Validation{StrictResponse: Ptr(true)},
+	}
+
+	override := QOS{
+		Timeout:    Ptr(time.Duration(0)),
+		Breaker:    Breaker{Failures: Ptr[uint](0)},
+		Validation: Validation{StrictResponse: Ptr(false)},
+	}
+
+	got := base.Merge(override)
+	if !reflect.DeepEqual(got, override) {
+		t.Fatalf("mismatch: want %+v; got %+v", override, got)
+	}
+
+	if cfg := got.Breaker.Config(); cfg.Enabled() || got.Validation.Config().StrictResponse {
+		t.Fatalf("explicit zero is ignored: %+v", got)
+	}
+
+	ctx, cancel := got.Context(context.Background())
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); ok {
+		t.Fatal("unexpected deadline for zero timeout")
+	}
+}
+
+// fill sets every leaf field of value to zero value behind non-nil pointer.
+func fill(value reflect.Value) {
+	if value.Kind() == reflect.Struct {
+		for i := range value.NumField() {
+			fill(value.Field(i))
+		}
+
+		return
+	}
+
+	if value.Kind() != reflect.Pointer {
+		panic("unsupported kind " + value.Kind().String())
+	}
+
+	value.Set(reflect.New(value.Type().Elem()))
+}
+
+func TestQOSMergeAllFields(t *testing.T) {
+	t.Parallel()
+
+	var override QOS
+
+	fill(reflect.ValueOf(&override).Elem())
+
+	if got := (QOS{}).Merge(override); !reflect.DeepEqual(got, override) {
+		t.Fatalf("some fields are not merged: want %+v; got %+v", override, got)
+	}
+}
+
+func TestQOSContext(t *testing.T) {
+	t.Parallel()
+
+	qos := QOS{}
+
+	ctx, cancel := qos.Context(context.Background())
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); ok {
+		t.Fatal("unexpected deadline for zero timeout")
+	}
+
+	qos.Timeout = Ptr(time.Second)
+
+	ctx, cancel = qos.Context(context.Background())
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); !ok {
+		t.Fatal("expected deadline")
+	}
+}
+
+func TestQOSConfig(t *testing.T) {
+	t.Parallel()
+
+	qos := QOS{
+		Retry:    Retry{Attempts: Ptr[uint](3), Backoff: Ptr(time.Millisecond * 10)},
+		Deadline: Deadline{Header: Ptr(deadline.HeaderGRPCTimeout), Format: Ptr(deadline.FormatGRPC)},
+	}
+
+	if want := (retry.Config{Attempts: 3, Backoff: time.Millisecond * 10}); qos.Retry.Config() != want {
+		t.Fatalf("retry mismatch: want %+v; got %+v", want, qos.Retry.Config())
+	}
+
+	want := deadline.Config{Header: deadline.HeaderGRPCTimeout, Format: deadline.FormatGRPC}
+	if qos.Deadline.Config() != want {
+		t.Fatalf("deadline mismatch: want %+v; got %+v", want, qos.Deadline.Config())
+	}
+
+	if qos.Breaker.Config() != (breaker.Config{}) {
+		t.Fatalf("unset breaker is not zero: %+v", qos.Breaker.Config())
+	}
+}
@@ -2,45 +2,201 @@ package config
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
-	"time"
+	"sync/atomic"
 )
 
-// NewSnapshot creates new config snapshot.
+// ErrVersionMismatch is returned by CompareAndSwap when config was updated
+// concurrently.
+var ErrVersionMismatch = errors.New("config: version mismatch")
+
+// Validator checks config before it's applied.
+type Validator[T any] func(cfg T) error
+
+// NewSnapshot creates new config snapshot. Initial config has version 1.
 func NewSnapshot[T any](cfg T) *Snapshot[T] {
-	return &Snapshot[T]{
-		cfg: cfg,
-	}
+	snapshot := &Snapshot[T]{}
+	snapshot.cfg.Store(&versioned[T]{cfg: cfg, version: 1})
+
+	return snapshot
 }
 
 // Snapshot provides thread-safe access to service configuration.
+//
+// Reads are lock-free so Get is cheap enough to be called on every request.
+// Updates are serialized and subscribers are notified in the order updates
+// were applied.
+//
+// Every applied update increments config version, so concurrent updaters can
+// use CompareAndSwap to avoid overwriting each other's changes.
+//
+// Zero value is ready to use and holds zero config of version 0.
 type Snapshot[T any] struct {
-	mu  sync.RWMutex
-	cfg T
+	cfg atomic.Pointer[versioned[T]]
+
+	// updateMu serializes updates, subscriber notifications and guards
+	// validators.
+	updateMu   sync.Mutex
+	validators []Validator[T]
+
+	subscribersMu sync.Mutex
+	subscribers   []subscriber[T]
+	nextID        uint64
+}
+
+type versioned[T any] struct {
+	cfg     T
+	version uint64
+}
+
+type subscriber[T any] struct {
+	id uint64
+	fn func(old, new T)
+}
+
+// AddValidator registers validator which is checked on every update.
+// Current config is not validated.
+func (s *Snapshot[T]) AddValidator(validator Validator[T]) {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+
+	s.validators = append(s.validators, validator)
+}
+
+// Update validates and replaces current config and notifies subscribers.
+// Invalid config is rejected and current config is kept.
+//
+// Subscribers are called synchronously, so they must not call Update.
+func (s *Snapshot[T]) Update(cfg T) error {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+
+	return s.update(s.load(), cfg)
+}
+
+// CompareAndSwap updates config only if current version equals to version.
+// ErrVersionMismatch is returned otherwise.
+func (s *Snapshot[T]) CompareAndSwap(version uint64, cfg T) error {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+
+	current := s.load()
+	if current.version != version {
+		return fmt.Errorf("%w: want %d; got %d", ErrVersionMismatch, version, current.version)
+	}
+
+	return s.update(current, cfg)
+}
+
+// Get returns current config and its version.
+func (s *Snapshot[T]) Get() (cfg T, version uint64) {
+	current := s.load()
+
+	return current.cfg, current.version
 }
 
-func (s *Snapshot[T]) Update(cfg T) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// load returns current config; it's zero config of version 0 until the first
+// update of zero Snapshot.
+func (s *Snapshot[T]) load() *versioned[T] {
+	if current := s.cfg.Load(); current != nil {
+		return current
+	}
 
-	s.cfg = cfg
+	return &versioned[T]{}
 }
 
-func (s *Snapshot[T]) Get() T {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// update must be called with updateMu held.
+func (s *Snapshot[T]) update(current *versioned[T], cfg T) error {
+	if err := s.validate(cfg); err != nil {
+		return err
+	}
+
+	s.cfg.Store(&versioned[T]{cfg: cfg, version: current.version + 1})
 
-	return s.cfg
+	for _, sub := range s.subscribersList() {
+		sub.fn(current.cfg, cfg)
+	}
+
+	return nil
 }
 
-type QOS struct {
-	Timeout time.Duration
+func (s *Snapshot[T]) validate(cfg T) error {
+	errs := make([]error, 0, len(s.validators))
+
+	for _, validator := range s.validators {
+		if err := validator(cfg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("config: invalid config: %w", errors.Join(errs...))
 }
 
-func (q *QOS) Context(ctx context.Context) (context.Context, context.CancelFunc) {
-	if q.Timeout == 0 {
-		return ctx, func() {}
+// Subscribe registers fn to be called on every config update with previous
+// and new config values. Returned function removes the subscription; it's
+// safe to call it more than once.
+func (s *Snapshot[T]) Subscribe(fn func(old, new T)) (unsubscribe func()) {
+	s.subscribersMu.Lock()
+	defer s.subscribersMu.Unlock()
+
+	s.nextID++
+	id := s.nextID
+
+	s.subscribers = append(s.subscribers, subscriber[T]{id: id, fn: fn})
+
+	return func() {
+		s.subscribersMu.Lock()
+		defer s.subscribersMu.Unlock()
+
+		for i, sub := range s.subscribers {
+			if sub.id == id {
+				s.subscribers = append(s.subscribers[:i:i], s.subscribers[i+1:]...)
+				return
+			}
+		}
 	}
+}
+
+// Changes returns a channel which receives new config values on every update
+// until ctx is done; the channel is closed afterwards.
+//
+// Slow readers do not block updates: if previous value was not received yet
+// it's replaced with the latest one.
+func (s *Snapshot[T]) Changes(ctx context.Context) <-chan T {
+	changes := make(chan T, 1)
+
+	unsubscribe := s.Subscribe(func(_, cfg T) {
+		select {
+		case <-changes:
+		default:
+		}
+
+		changes <- cfg
+	})
+
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+
+		// Wait for in-flight notification to finish before closing.
+		s.updateMu.Lock()
+		defer s.updateMu.Unlock()
+
+		close(changes)
+	}()
+
+	return changes
+}
+
+func (s *Snapshot[T]) subscribersList() []subscriber[T] {
+	s.subscribersMu.Lock()
+	defer s.subscribersMu.Unlock()
 
-	return context.WithTimeout(ctx, q.Timeout)
+	return s.subscribers[:len(s.subscribers):len(s.subscribers)]
 }
@@ -0,0 +1,305 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnapshotGetUpdate(t *testing.T) {
+	t.Parallel()
+
+	snapshot := NewSnapshot(1)
+	if got, version := snapshot.Get(); got != 1 || version != 1 {
+		t.Fatalf("mismatch: want %d@%d; got %d@%d", 1, 1, got, version)
+	}
+
+	if err := snapshot.Update(2); err != nil {
+		t.Fatalf("could not update: %v", err)
+	}
+
+	if got, version := snapshot.Get(); got != 2 || version != 2 {
+		t.Fatalf("mismatch: want %d@%d; got %d@%d", 2, 2, got, version)
+	}
+}
+
+func TestSnapshotZero(t *testing.T) {
+	t.Parallel()
+
+	var snapshot Snapshot[int]
+
+	if got, version := snapshot.Get(); got != 0 || version != 0 {
+		t.Fatalf("mismatch: want %d@%d; got %d@%d", 0, 0, got, version)
+	}
+
+	if err := snapshot.CompareAndSwap(0, 1); err != nil {
+		t.Fatalf("could not update: %v", err)
+	}
+
+	if got, version := snapshot.Get(); got != 1 || version != 1 {
+		t.Fatalf("mismatch: want %d@%d; got %d@%d", 1, 1, got, version)
+	}
+}
+
+func TestSnapshotValidators(t *testing.T) {
+	t.Parallel()
+
+	errNegative := errors.New("negative value")
+	errOdd := errors.New("odd value")
+
+	snapshot := NewSnapshot(0)
+	snapshot.AddValidator(func(cfg int) error {
+		if cfg < 0 {
+			return errNegative
+		}
+
+		return nil
+	})
+	snapshot.AddValidator(func(cfg int) error {
+		if cfg%2 != 0 {
+			return errOdd
+		}
+
+		return nil
+	})
+
+	var notified bool
+
+	snapshot.Subscribe(func(int, int) {
+		notified = true
+	})
+
+	err := snapshot.Update(-1)
+	compareErrors(t, errNegative, err)
+	compareErrors(t, errOdd, err)
+
+	if notified {
+		t.Fatal("subscribers must not be notified about rejected update")
+	}
+
+	if got, version := snapshot.Get(); got != 0 || version != 1 {
+		t.Fatalf("rejected update applied: got %d@%d", got, version)
+	}
+
+	if err := snapshot.Update(2); err != nil {
+		t.Fatalf("could not update: %v", err)
+	}
+
+	if got, version := snapshot.Get(); got != 2 || version != 2 {
+		t.Fatalf("mismatch: want %d@%d; got %d@%d", 2, 2, got, version)
+	}
+}
+
+func TestSnapshotCompareAndSwap(t *testing.T) {
+	t.Parallel()
+
+	snapshot := NewSnapshot(0)
+	_, version := snapshot.Get()
+
+	if err := snapshot.CompareAndSwap(version, 1); err != nil {
+		t.Fatalf("could not swap: %v", err)
+	}
+
+	err := snapshot.CompareAndSwap(version, 2)
+	compareErrors(t, ErrVersionMismatch, err)
+
+	if got, version := snapshot.Get(); got != 1 || version != 2 {
+		t.Fatalf("mismatch: want %d@%d; got %d@%d", 1, 2, got, version)
+	}
+}
+
+func TestSnapshotCompareAndSwapConcurrent(t *testing.T) {
+	t.Parallel()
+
+	const updaters = 16
+
+	snapshot := NewSnapshot(0)
+
+	var wg sync.WaitGroup
+
+	for range updaters {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				cfg, version := snapshot.Get()
+
+				err := snapshot.CompareAndSwap(version, cfg+1)
+				if err == nil {
+					return
+				}
+
+				if !errors.Is(err, ErrVersionMismatch) {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if got, _ := snapshot.Get(); got != updaters {
+		t.Fatalf("lost updates: want %d; got %d", updaters, got)
+	}
+}
+
+func TestSnapshotSubscribe(t *testing.T) {
+	t.Parallel()
+
+	type change struct {
+		old, new int
+	}
+
+	snapshot := NewSnapshot(0)
+
+	var changes []change
+
+	unsubscribe := snapshot.Subscribe(func(old, new int) {
+		changes = append(changes, change{old: old, new: new})
+	})
+
+	_ = snapshot.Update(1)
+	_ = snapshot.Update(2)
+
+	unsubscribe()
+	unsubscribe()
+
+	_ = snapshot.Update(3)
+
+	want := []change{{old: 0, new: 1}, {old: 1, new: 2}}
+	if len(changes) != len(want) {
+		t.Fatalf("changes mismatch: want %v; got %v", want, changes)
+	}
+
+	for i := range want {
+		if want[i] != changes[i] {
+			t.Fatalf("change %d mismatch: want %v; got %v", i, want[i], changes[i])
+		}
+	}
+}
+
+func TestSnapshotUnsubscribeInsideCallback(t *testing.T) {
+	t.Parallel()
+
+	snapshot := NewSnapshot(0)
+
+	var (
+		calls       int
+		unsubscribe func()
+	)
+
+	unsubscribe = snapshot.Subscribe(func(int, int) {
+		calls++
+		unsubscribe()
+	})
+
+	_ = snapshot.Update(1)
+	_ = snapshot.Update(2)
+
+	if calls != 1 {
+		t.Fatalf("calls mismatch: want %d; got %d", 1, calls)
+	}
+}
+
+func TestSnapshotChanges(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	snapshot := NewSnapshot(0)
+	changes := snapshot.Changes(ctx)
+
+	_ = snapshot.Update(1)
+	_ = snapshot.Update(2)
+
+	select {
+	case got := <-changes:
+		if got != 2 {
+			t.Fatalf("mismatch: want latest value %d; got %d", 2, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no change received")
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-changes:
+		if ok {
+			t.Fatal("expected channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed")
+	}
+
+	// Updates after cancellation must not panic on closed channel.
+	_ = snapshot.Update(3)
+}
+
+func TestSnapshotConcurrentAccess(t *testing.T) {
+	t.Parallel()
+
+	const writers = 8
+
+	snapshot := NewSnapshot(0)
+
+	var (
+		wg   sync.WaitGroup
+		last int
+	)
+
+	snapshot.Subscribe(func(old, new int) {
+		if old != last {
+			t.Errorf("notifications out of order: want old %d; got %d", last, old)
+		}
+
+		last = new
+	})
+
+	for i := 1; i <= writers; i++ {
+		wg.Add(2)
+
+		go func(value int) {
+			defer wg.Done()
+
+			_ = snapshot.Update(value)
+		}(i)
+
+		go func() {
+			defer wg.Done()
+
+			_, _ = snapshot.Get()
+		}()
+	}
+
+	wg.Wait()
+
+	if got, _ := snapshot.Get(); got != last {
+		t.Fatalf("mismatch: want %d; got %d", last, got)
+	}
+}
+
+func compareErrors(t *testing.T, want, got error) {
+	t.Helper()
+
+	if want == nil && got == nil {
+		return
+	}
+
+	if want == nil && got != nil {
+		t.Fatalf("unexpected error: %v", got)
+	}
+
+	if want != nil && got == nil {
+		t.Fatalf("expected %v but got nil", want)
+	}
+
+	if !errors.Is(got, want) {
+		t.Fatalf("error mismatch: want %v; got %v", want, got)
+	}
+}
@@ -0,0 +1,197 @@
+// Package deadline propagates call deadlines to upstream services via request
+// headers.
+package deadline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Well-known deadline headers.
+const (
+	// HeaderRequestTimeout carries remaining budget in milliseconds.
+	HeaderRequestTimeout = "X-Request-Timeout"
+	// HeaderGRPCTimeout carries remaining budget in gRPC timeout encoding.
+	HeaderGRPCTimeout = "Grpc-Timeout"
+)
+
+// Format is a header value encoding.
+type Format string
+
+const (
+	// FormatMilliseconds encodes budget as integer number of milliseconds,
+	// e.g. "250".
+	FormatMilliseconds Format = "milliseconds"
+	// FormatGRPC encodes budget as gRPC timeout, e.g. "250m" or "5S".
+	FormatGRPC Format = "grpc"
+)
+
+// ErrExpired is returned when there is no budget left to propagate.
+var ErrExpired = fmt.Errorf("deadline: budget expired: %w", context.DeadlineExceeded)
+
+// ErrInvalidValue is returned when header value could not be parsed.
+var ErrInvalidValue = errors.New("deadline: invalid header value")
+
+// Config controls deadline propagation.
+type Config struct {
+	// Header is a name of header carrying remaining budget. Propagation is
+	// disabled when empty.
+	Header string `json:"header,omitempty" yaml:"header,omitempty"`
+	// Format is a header value encoding. Defaults to FormatMilliseconds.
+	Format Format `json:"format,omitempty" yaml:"format,omitempty"`
+	// Margin is subtracted from remaining budget to leave time for network
+	// round trip and response handling.
+	Margin time.Duration `json:"margin,omitempty" yaml:"margin,omitempty"`
+}
+
+// Enabled reports whether propagation is enabled.
+func (cfg *Config) Enabled() bool {
+	return cfg.Header != ""
+}
+
+// Inject sets header to the remaining budget of ctx minus margin. Nothing is
+// set if propagation is disabled or ctx has no deadline. ErrExpired is
+// returned if there is no budget left.
+func Inject(ctx context.Context, header http.Header, cfg Config) error {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+
+	budget := time.Until(deadline) - cfg.Margin
+	if budget <= 0 {
+		return ErrExpired
+	}
+
+	value, err := Encode(budget, cfg.Format)
+	if err != nil {
+		return err
+	}
+
+	header.Set(cfg.Header, value)
+
+	return nil
+}
+
+// Middleware returns server middleware which reads budget from the header and
+// applies it to request context. Requests with missing or invalid header are
+// served as is.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !cfg.Enabled() {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			value := r.Header.Get(cfg.Header)
+			if value == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			budget, err := Parse(value, cfg.Format)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), budget)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// maxGRPCValue is a maximum value of gRPC timeout: at most 8 digits.
+const maxGRPCValue = 99_999_999
+
+//nolint:gochecknoglobals // Ordered list of constant units.
+var grpcUnits = []struct {
+	unit   time.Duration
+	suffix byte
+}{
+	{unit: time.Nanosecond, suffix: 'n'},
+	{unit: time.Microsecond, suffix: 'u'},
+	{unit: time.Millisecond, suffix: 'm'},
+	{unit: time.Second, suffix: 'S'},
+	{unit: time.Minute, suffix: 'M'},
+	{unit: time.Hour, suffix: 'H'},
+}
+
+// Encode encodes budget in given format. Budget is rounded down, but a
+// positive budget under a millisecond is encoded as 1 millisecond, since
+// upstream would treat 0 as expired.
+func Encode(budget time.Duration, format Format) (string, error) {
+	switch format {
+	case "", FormatMilliseconds:
+		millis := budget.Milliseconds()
+		if millis == 0 && budget > 0 {
+			millis = 1
+		}
+
+		return strconv.FormatInt(millis, 10), nil
+	case FormatGRPC:
+		// Pick the most precise unit which fits into 8 digits.
+		for _, unit := range grpcUnits {
+			if value := budget / unit.unit; value <= maxGRPCValue {
+				return strconv.FormatInt(int64(value), 10) + string(unit.suffix), nil
+			}
+		}
+
+		return strconv.Itoa(maxGRPCValue) + "H", nil
+	default:
+		return "", fmt.Errorf("deadline: unknown format %q", format)
+	}
+}
+
+// Parse parses budget encoded in given format.
+func Parse(value string, format Format) (time.Duration, error) {
+	switch format {
+	case "", FormatMilliseconds:
+		millis, err := strconv.ParseUint(value, 10, 63)
+		if err != nil {
+			return 0, fmt.Errorf("%w %q: %w", ErrInvalidValue, value, err)
+		}
+
+		return multiply(value, millis, time.Millisecond)
+	case FormatGRPC:
+		if len(value) < 2 || len(value) > 9 {
+			return 0, fmt.Errorf("%w %q", ErrInvalidValue, value)
+		}
+
+		amount, err := strconv.ParseUint(value[:len(value)-1], 10, 63)
+		if err != nil {
+			return 0, fmt.Errorf("%w %q: %w", ErrInvalidValue, value, err)
+		}
+
+		suffix := value[len(value)-1]
+		for _, unit := range grpcUnits {
+			if unit.suffix == suffix {
+				return multiply(value, amount, unit.unit)
+			}
+		}
+
+		return 0, fmt.Errorf("%w %q: unknown unit", ErrInvalidValue, value)
+	default:
+		return 0, fmt.Errorf("deadline: unknown format %q", format)
+	}
+}
+
+// multiply returns amount of units failing on overflow.
+func multiply(value string, amount uint64, unit time.Duration) (time.Duration, error) {
+	if amount > uint64(math.MaxInt64/unit) {
+		return 0, fmt.Errorf("%w %q: overflow", ErrInvalidValue, value)
+	}
+
+	return time.Duration(amount) * unit, nil
+}
@@ -0,0 +1,244 @@
+package deadline
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestEncodeParse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		budget  time.Duration
+		format  Format
+		encoded string
+		parsed  time.Duration
+	}{
+		{
+			name:    "milliseconds",
+			budget:  time.Millisecond*250 + time.Microsecond*900,
+			format:  FormatMilliseconds,
+			encoded: "250",
+			parsed:  time.Millisecond * 250,
+		},
+		{
+			name:    "milliseconds under one",
+			budget:  time.Microsecond * 900,
+			format:  FormatMilliseconds,
+			encoded: "1",
+			parsed:  time.Millisecond,
+		},
+		{
+			name:    "default format is milliseconds",
+			budget:  time.Second,
+			format:  "",
+			encoded: "1000",
+			parsed:  time.Second,
+		},
+		{
+			name:    "grpc nanoseconds",
+			budget:  time.Millisecond * 50,
+			format:  FormatGRPC,
+			encoded: "50000000n",
+			parsed:  time.Millisecond * 50,
+		},
+		{
+			name:    "grpc microseconds",
+			budget:  time.Second*2 + time.Nanosecond,
+			format:  FormatGRPC,
+			encoded: "2000000u",
+			parsed:  time.Second * 2,
+		},
+		{
+			name:    "grpc seconds",
+			budget:  time.Hour * 48,
+			format:  FormatGRPC,
+			encoded: "172800S",
+			parsed:  time.Hour * 48,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := Encode(c.budget, c.format)
+			if err != nil {
+				t.Fatalf("could not encode: %v", err)
+			}
+
+			if encoded != c.encoded {
+				t.Fatalf("encoded mismatch: want %q; got %q", c.encoded, encoded)
+			}
+
+			parsed, err := Parse(encoded, c.format)
+			if err != nil {
+				t.Fatalf("could not parse: %v", err)
+			}
+
+			if parsed != c.parsed {
+				t.Fatalf("parsed mismatch: want %v; got %v", c.parsed, parsed)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		value  string
+		format Format
+	}{
+		{value: "-1", format: FormatMilliseconds},
+		{value: "1s", format: FormatMilliseconds},
+		{value: "99999999999999999", format: FormatMilliseconds},
+		{value: "1", format: FormatGRPC},
+		{value: "100x", format: FormatGRPC},
+		{value: "123456789S", format: FormatGRPC},
+		{value: "99999999H", format: FormatGRPC},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.value, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Parse(c.value, c.format); !errors.Is(err, ErrInvalidValue) {
+				t.Fatalf("error mismatch: want %v; got %v", ErrInvalidValue, err)
+			}
+		})
+	}
+}
+
+func TestInject(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{Header: HeaderRequestTimeout, Margin: time.Millisecond * 100}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		header := http.Header{}
+		if err := Inject(ctx, header, Config{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(header) != 0 {
+			t.Fatalf("unexpected headers: %v", header)
+		}
+	})
+
+	t.Run("no deadline", func(t *testing.T) {
+		t.Parallel()
+
+		header := http.Header{}
+		if err := Inject(context.Background(), header, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(header) != 0 {
+			t.Fatalf("unexpected headers: %v", header)
+		}
+	})
+
+	t.Run("margin subtracted", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		header := http.Header{}
+		if err := Inject(ctx, header, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		millis, err := strconv.Atoi(header.Get(HeaderRequestTimeout))
+		if err != nil {
+			t.Fatalf("invalid header %q: %v", header.Get(HeaderRequestTimeout), err)
+		}
+
+		if millis > 900 || millis < 800 {
+			t.Fatalf("budget mismatch: want ~900ms; got %dms", millis)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+		defer cancel()
+
+		err := Inject(ctx, http.Header{}, cfg)
+		if !errors.Is(err, ErrExpired) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("error mismatch: want %v; got %v", ErrExpired, err)
+		}
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{Header: HeaderGRPCTimeout, Format: FormatGRPC}
+
+	handler := Middleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok := r.Context().Deadline()
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		_, _ = w.Write([]byte(strconv.FormatInt(time.Until(deadline).Milliseconds(), 10)))
+	}))
+
+	cases := []struct {
+		name   string
+		header string
+		code   int
+	}{
+		{name: "with header", header: "500m", code: http.StatusOK},
+		{name: "without header", header: "", code: http.StatusNoContent},
+		{name: "invalid header", header: "soon", code: http.StatusNoContent},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if c.header != "" {
+				req.Header.Set(HeaderGRPCTimeout, c.header)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != c.code {
+				t.Fatalf("code mismatch: want %d; got %d", c.code, rec.Code)
+			}
+
+			if c.code != http.StatusOK {
+				return
+			}
+
+			millis, err := strconv.Atoi(rec.Body.String())
+			if err != nil || millis > 500 || millis < 400 {
+				t.Fatalf("budget mismatch: want ~500ms; got %q", rec.Body.String())
+			}
+		})
+	}
+}
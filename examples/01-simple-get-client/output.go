@@ -2,18 +2,28 @@
 package messageservice
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
-)
 
-// This is needed to have bytes imported when non-body requests are generated.
-var _ = bytes.Buffer{}
+	"github.com/vitaminniy/go-lib-http/auth"
+	"github.com/vitaminniy/go-lib-http/config"
+	"github.com/vitaminniy/go-lib-http/deadline"
+	"github.com/vitaminniy/go-lib-http/logging"
+	"github.com/vitaminniy/go-lib-http/metrics"
+	"github.com/vitaminniy/go-lib-http/middleware"
+	"github.com/vitaminniy/go-lib-http/policy"
+	"github.com/vitaminniy/go-lib-http/retry"
+	"github.com/vitaminniy/go-lib-http/tracing"
+	"github.com/vitaminniy/go-lib-http/validate"
+)
 
 // Option overrides MessageService creation.
 type Option func(*MessageService)
@@ -39,6 +49,53 @@ func WithConfigFunc(configFunc ConfigFunc) Option {
 	}
 }
 
+// WithMiddleware appends middlewares wrapping every HTTP exchange, including
+// retries and hedged requests. The first middleware is the outermost one.
+func WithMiddleware(middlewares ...middleware.Middleware) Option {
+	return func(cl *MessageService) {
+		cl.middlewares = append(cl.middlewares, middlewares...)
+	}
+}
+
+// WithMetrics sets recorder receiving metrics of every call, HTTP exchange,
+// retry, hedged request and circuit breaker rejection.
+func WithMetrics(recorder metrics.Recorder) Option {
+	return func(cl *MessageService) {
+		cl.recorder = recorder
+	}
+}
+
+// WithTracer sets tracer starting span per operation call and per HTTP
+// exchange. W3C trace context from ctx is propagated to upstream even without
+// tracer.
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(cl *MessageService) {
+		cl.tracer = tracer
+	}
+}
+
+// WithLogger sets logger recording start and finish of every HTTP exchange.
+// Sensitive headers and body fields are redacted, see logging options.
+func WithLogger(logger *slog.Logger, opts ...logging.Option) Option {
+	return func(cl *MessageService) {
+		cl.logger = logger
+		cl.logOptions = opts
+	}
+}
+
+// ResponseViolationHandler receives violations found by strict response
+// validation, see validate.Config.StrictResponse.
+type ResponseViolationHandler func(ctx context.Context, operation middleware.Operation, err *validate.Error)
+
+// WithResponseViolationHandler sets handler receiving violations of strict
+// response validation instead of failing calls with *validate.Error, e.g. to
+// alert on contract drift.
+func WithResponseViolationHandler(handler ResponseViolationHandler) Option {
+	return func(cl *MessageService) {
+		cl.violationHandler = handler
+	}
+}
+
 // NewMessageService creates a new MessageService http client.
 func NewMessageService(baseurl string, opts ...Option) (*MessageService, error) {
 	parsed, err := url.Parse(baseurl)
@@ -47,7 +104,8 @@ func NewMessageService(baseurl string, opts ...Option) (*MessageService, error)
 	}
 
 	cli := &MessageService{
-		baseURL: parsed,
+		baseURL:     parsed,
+		credentials: make(map[string]auth.Credentials),
 		httpClient: &http.Client{
 			Timeout: time.Second * 1, // Arbitrary value to avoid hanging forever.
 		},
@@ -57,13 +115,72 @@ func NewMessageService(baseurl string, opts ...Option) (*MessageService, error)
 		opt(cli)
 	}
 
+	cli.policies = policy.NewExecutor(policy.WithObserver(metrics.Observer(cli.recorder)))
+
+	// Tracing middleware is the outermost one so others see trace context,
+	// logging one sees headers set by others, and metrics middleware is the
+	// innermost one to measure exchange only.
+	middlewares := make([]middleware.Middleware, 0, len(cli.middlewares)+3) //nolint:gomnd // Builtin middlewares.
+	middlewares = append(middlewares, tracing.Middleware(cli.tracer))
+	middlewares = append(middlewares, cli.middlewares...)
+	middlewares = append(middlewares, logging.Middleware(cli.logger, cli.logOptions...))
+	middlewares = append(middlewares, metrics.Middleware(cli.recorder))
+
+	cli.handler = middleware.Chain(cli.send, middlewares...)
+
 	return cli, nil
 }
 
 type MessageService struct {
-	baseURL    *url.URL
-	httpClient *http.Client
-	configFunc ConfigFunc
+	baseURL          *url.URL
+	httpClient       *http.Client
+	configFunc       ConfigFunc
+	policies         *policy.Executor
+	middlewares      []middleware.Middleware
+	handler          middleware.Handler
+	recorder         metrics.Recorder
+	tracer           tracing.Tracer
+	logger           *slog.Logger
+	logOptions       []logging.Option
+	credentials      map[string]auth.Credentials
+	violationHandler ResponseViolationHandler
+}
+
+// send performs HTTP exchange; it's the innermost middleware handler.
+func (cl *MessageService) send(_ middleware.Operation, req *http.Request) (*http.Response, error) {
+	return cl.httpClient.Do(req)
+}
+
+// pathParam escapes path parameter value; dot segments are escaped as well
+// so they are not resolved when joined with base URL.
+func pathParam(value string) string {
+	switch value {
+	case ".":
+		return "%2E"
+	case "..":
+		return "%2E%2E"
+	default:
+		return url.PathEscape(value)
+	}
+}
+
+// checkResponse checks response body against component schema. Violations are
+// passed to violation handler if it's set and returned otherwise.
+func (cl *MessageService) checkResponse(
+	ctx context.Context,
+	operation middleware.Operation,
+	schema string,
+	raw []byte,
+) error {
+	err := responseSchemas.Check(schema, raw)
+
+	var violations *validate.Error
+	if cl.violationHandler != nil && errors.As(err, &violations) {
+		cl.violationHandler(ctx, operation, violations)
+		return nil
+	}
+
+	return err
 }
 
 func (cl *MessageService) getConfig() Config {
@@ -74,45 +191,140 @@ func (cl *MessageService) getConfig() Config {
 	return cl.configFunc()
 }
 
-// MethodConfig controls method behavior.
-type MethodConfig struct {
-	Timeout time.Duration
+// MessageServiceAPI lists MessageService operations, so consumers can depend on
+// the interface and stub it with FakeMessageService in tests.
+type MessageServiceAPI interface {
+	GETAPIV1Messages(ctx context.Context, request *GETAPIV1MessagesRequest) (*GETAPIV1MessagesResponse, error)
 }
 
-func (cfg *MethodConfig) context(ctx context.Context) (context.Context, context.CancelFunc) {
-	if cfg.Timeout == 0 {
-		return ctx, func() {}
-	}
+var _ MessageServiceAPI = (*MessageService)(nil)
 
-	return context.WithTimeout(ctx, cfg.Timeout)
-}
+// MethodConfig controls method behavior. Nil fields are treated as unset and
+// are inherited from Config.Default.
+type MethodConfig = config.QOS
 
 // ConfigFunc returns configuration.
 type ConfigFunc func() Config
 
-// Config contains method configurations.
+// Config contains method configurations. Config files use method names as
+// keys.
 type Config struct {
-	GETApiV1Messages MethodConfig
+	// Default is applied to every method; method configs override its fields.
+	Default          MethodConfig `json:"Default" yaml:"Default"`
+	GETAPIV1Messages MethodConfig `json:"GETAPIV1Messages" yaml:"GETAPIV1Messages"`
 }
 
-// DefaultConfig returns default configuration.
-//
-// TODO(max): Handle default config creation.
+// DefaultConfig returns default configuration declared in the spec with
+// x-timeout and x-retries extensions.
 func DefaultConfig() Config {
-	return Config{}
+	return Config{
+		GETAPIV1Messages: MethodConfig{
+			Timeout: config.Ptr(250 * time.Millisecond),
+			Retry: config.Retry{
+				Attempts: config.Ptr[uint](3),
+			},
+		},
+	}
+}
+
+// EnvPrefix is a prefix of environment variables overriding configuration,
+// e.g. MESSAGESERVICE_DEFAULT_TIMEOUT=250ms.
+const EnvPrefix = "MESSAGESERVICE"
+
+// ResolveConfig merges DefaultConfig with layers in order of increasing
+// priority, e.g. file, environment and runtime overrides:
+//
+//	file, err := config.FileLayer[Config]("config.yaml")
+//	env, err := config.EnvLayer[Config](EnvPrefix)
+//	cfg, origins := ResolveConfig(file, env)
+//
+// Returned origins explain which layer set each value.
+func ResolveConfig(layers ...config.Layer[Config]) (Config, config.Origins) {
+	all := make([]config.Layer[Config], 0, len(layers)+1)
+	all = append(all, config.Layer[Config]{Name: config.LayerSpec, Config: DefaultConfig()})
+	all = append(all, layers...)
+
+	return config.Resolve(all...)
 }
 
 type MessagesResponseBody struct {
 	Messages []Message `json:"messages"`
 }
 
+// Validate checks MessagesResponseBody against schema constraints. Violations are
+// reported as *validate.Error. Required strings, numbers and booleans are
+// plain values, so missing ones are not told apart from "", 0 and false.
+func (m *MessagesResponseBody) Validate() error {
+	v := &validate.Validator{}
+	m.validate(v, "")
+
+	return v.Err()
+}
+
+func (m *MessagesResponseBody) validate(v *validate.Validator, path string) {
+	v.Required(path+"/messages", m.Messages != nil)
+	if m.Messages != nil {
+		for i := range m.Messages {
+			m.Messages[i].validate(v, validate.Index(path+"/messages", i))
+		}
+	}
+}
+
 type Message struct {
-	Id       string `json:"id"`
-	SenderId string `json:"sender_id"`
+	ID       string `json:"id"`
+	SenderID string `json:"sender_id"`
 	Text     string `json:"text"`
 }
 
-type GETApiV1MessagesRequest struct {
+// Validate checks Message against schema constraints. Violations are
+// reported as *validate.Error. Required strings, numbers and booleans are
+// plain values, so missing ones are not told apart from "", 0 and false.
+func (m *Message) Validate() error {
+	v := &validate.Validator{}
+	m.validate(v, "")
+
+	return v.Err()
+}
+
+func (m *Message) validate(v *validate.Validator, path string) {
+}
+
+// responseSchemas describe components for strict response validation.
+//
+//nolint:gochecknoglobals // Read-only schemas.
+var responseSchemas = validate.Schemas{
+	"MessagesResponseBody": {
+		Type: "object",
+		Properties: map[string]*validate.Schema{
+			"messages": {
+				Type: "array",
+				Items: &validate.Schema{
+					Ref: "Message",
+				},
+			},
+		},
+		Required: []string{"messages"},
+		Closed:   true,
+	},
+	"Message": {
+		Type: "object",
+		Properties: map[string]*validate.Schema{
+			"id": {
+				Type: "string",
+			},
+			"sender_id": {
+				Type: "string",
+			},
+			"text": {
+				Type: "string",
+			},
+		},
+		Required: []string{"id", "sender_id", "text"},
+		Closed:   true,
+	},
+}
+
+type GETAPIV1MessagesRequest struct {
 	// HeaderUserAgent is "User-Agent" header value.
 	HeaderUserAgent string
 
@@ -121,80 +333,220 @@ type GETApiV1MessagesRequest struct {
 
 	// QueryLimit is "limit" query parameter.
 	QueryLimit string
-	// QuerySenderId is "sender_id" query parameter.
-	QuerySenderId *string
+	// QuerySenderID is "sender_id" query parameter.
+	QuerySenderID *string
+}
+
+// Validate checks parameters and body against schema constraints. Violations
+// are reported as *validate.Error with paths like "/query/limit" and
+// "/body/name". Required strings, numbers and booleans are plain values, so
+// missing ones are not told apart from "", 0 and false.
+func (r *GETAPIV1MessagesRequest) Validate() error {
+	v := &validate.Validator{}
+	v.Required("/header/User-Agent", r.HeaderUserAgent != "")
+	v.Required("/query/limit", r.QueryLimit != "")
+	if r.QueryLimit != "" {
+		v.Number("/query/limit", r.QueryLimit, true)
+	}
+
+	return v.Err()
 }
 
-type GETApiV1MessagesResponse struct {
+type GETAPIV1MessagesResponse struct {
 	Headers map[string][]string
 
 	Body200 *MessagesResponseBody
 }
 
-func (cl *MessageService) GETApiV1Messages(
+//nolint:gochecknoglobals // Operation descriptor passed to middlewares.
+var operationGETAPIV1Messages = middleware.Operation{
+	Name:   "GETAPIV1Messages",
+	Method: "GET",
+	Path:   "/api/v1/messages",
+	Tags:   []string{"messages"},
+}
+
+func (cl *MessageService) GETAPIV1Messages(
 	ctx context.Context,
-	request *GETApiV1MessagesRequest,
-) (*GETApiV1MessagesResponse, error) {
+	request *GETAPIV1MessagesRequest,
+) (*GETAPIV1MessagesResponse, error) {
 	url := cl.baseURL.JoinPath("/api/v1/messages")
-	cfg := cl.getConfig().GETApiV1Messages
+	clientCfg := cl.getConfig()
+	cfg := clientCfg.Default.Merge(clientCfg.GETAPIV1Messages)
 
-	ctx, cancel := cfg.context(ctx)
+	if !cfg.Validation.Config().SkipRequest {
+		if err := request.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+	}
+
+	ctx, cancel := cfg.Context(ctx)
 	defer cancel()
 
+	ctx = middleware.WithOperation(ctx, operationGETAPIV1Messages)
+
 	{
 		query := url.Query()
 
 		query.Add("limit", request.QueryLimit)
-
-		if request.QuerySenderId != nil {
-			query.Add("sender_id", *request.QuerySenderId)
+		if request.QuerySenderID != nil {
+			query.Add("sender_id", *request.QuerySenderID)
 		}
 
 		url.RawQuery = query.Encode()
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url.String(), nil)
-	if err != nil {
-		return nil, fmt.Errorf("could not prepare request: %w", err)
-	}
+	// Operation span covers all attempts; HTTP exchanges are its children.
+	ctx, span := tracing.StartOperation(ctx, cl.tracer, operationGETAPIV1Messages)
 
-	req.Header.Add("Accept", "application/json")
+	result, err := policy.Do(ctx, cl.policies, operationGETAPIV1Messages.Name, cfg, func(ctx context.Context) (*GETAPIV1MessagesResponse, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url.String(), nil)
+		if err != nil {
+			return nil, retry.Abort(fmt.Errorf("could not prepare request: %w", err))
+		}
 
-	req.Header.Add("User-Agent", request.HeaderUserAgent)
+		req.Header.Add("Accept", "application/json")
 
-	for key, value := range request.Headers {
-		req.Header.Set(key, value)
-	}
+		req.Header.Add("User-Agent", request.HeaderUserAgent)
 
-	resp, err := cl.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("could not do http request: %w", err)
-	}
-	defer resp.Body.Close()
+		for key, value := range request.Headers {
+			req.Header.Set(key, value)
+		}
+
+		if err := deadline.Inject(ctx, req.Header, cfg.Deadline.Config()); err != nil {
+			return nil, retry.Abort(err)
+		}
 
-	if resp.StatusCode >= http.StatusBadRequest {
-		raw, err := io.ReadAll(resp.Body)
+		resp, err := cl.handler(operationGETAPIV1Messages, req)
 		if err != nil {
-			return nil, fmt.Errorf("could not read response with status %d: %w", resp.StatusCode, err)
+			return nil, fmt.Errorf("could not do http request: %w", err)
 		}
+		defer resp.Body.Close()
 
-		return nil, fmt.Errorf("got response with status %d: %q", resp.StatusCode, string(raw))
-	}
+		if resp.StatusCode >= http.StatusBadRequest {
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("could not read response with status %d: %w", resp.StatusCode, err)
+			}
 
-	response := &GETApiV1MessagesResponse{
-		Headers: resp.Header,
-	}
+			err = fmt.Errorf("got response with status %d: %q", resp.StatusCode, string(raw))
+
+			// Client errors won't go away on retry.
+			if resp.StatusCode < http.StatusInternalServerError {
+				return nil, retry.Abort(err)
+			}
+
+			return nil, err
+		}
+
+		response := &GETAPIV1MessagesResponse{
+			Headers: resp.Header,
+		}
+
+		if resp.StatusCode == 200 {
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("could not read response [%d]: %w", resp.StatusCode, err)
+			}
 
-	if resp.StatusCode == 200 {
-		var body MessagesResponseBody
-		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
-			return nil, fmt.Errorf("could not decode response [%d]: %w", resp.StatusCode, err)
+			if cfg.Validation.Config().StrictResponse {
+				if err := cl.checkResponse(ctx, operationGETAPIV1Messages, "MessagesResponseBody", raw); err != nil {
+					return nil, retry.Abort(fmt.Errorf("invalid response [%d]: %w", resp.StatusCode, err))
+				}
+			}
+
+			var body MessagesResponseBody
+			if err := json.Unmarshal(raw, &body); err != nil {
+				return nil, retry.Abort(fmt.Errorf("could not decode response [%d]: %w", resp.StatusCode, err))
+			}
+
+			response.Body200 = &body
+
+			return response, nil
 		}
 
-		response.Body200 = &body
+		return nil, retry.Abort(fmt.Errorf("unhandled response code: %d", resp.StatusCode))
+	})
+
+	span.End(err)
+
+	return result, err
+}
+
+var _ MessageServiceAPI = (*FakeMessageService)(nil)
+
+// ErrNotImplemented is returned by FakeMessageService methods without function
+// set.
+var ErrNotImplemented = errors.New("not implemented")
+
+// FakeMessageServiceCall is a call recorded by FakeMessageService.
+type FakeMessageServiceCall struct {
+	// Operation is a canonical operation name, e.g. "GETAPIV1Messages".
+	Operation string
+	// Request is a pointer to operation request, e.g. *GETAPIV1MessagesRequest.
+	Request any
+}
+
+// FakeMessageService is an in-memory MessageServiceAPI. Methods call
+// corresponding functions or return ErrNotImplemented if they are not set.
+// All calls are recorded. It's safe for concurrent use as long as functions
+// are not changed during calls.
+type FakeMessageService struct {
+	GETAPIV1MessagesFunc func(ctx context.Context, request *GETAPIV1MessagesRequest) (*GETAPIV1MessagesResponse, error)
+
+	mu    sync.Mutex
+	calls []FakeMessageServiceCall
+}
+
+// Calls returns recorded calls in order.
+func (f *FakeMessageService) Calls() []FakeMessageServiceCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]FakeMessageServiceCall(nil), f.calls...)
+}
 
-		return response, nil
+// Reset drops recorded calls.
+func (f *FakeMessageService) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = nil
+}
+
+func (f *FakeMessageService) record(operation string, request any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, FakeMessageServiceCall{Operation: operation, Request: request})
+}
+
+// GETAPIV1Messages implements MessageServiceAPI.
+func (f *FakeMessageService) GETAPIV1Messages(
+	ctx context.Context,
+	request *GETAPIV1MessagesRequest,
+) (*GETAPIV1MessagesResponse, error) {
+	f.record("GETAPIV1Messages", request)
+
+	if f.GETAPIV1MessagesFunc == nil {
+		return nil, fmt.Errorf("%w: GETAPIV1Messages", ErrNotImplemented)
+	}
+
+	return f.GETAPIV1MessagesFunc(ctx, request)
+}
+
+// GETAPIV1MessagesCalls returns requests of recorded GETAPIV1Messages calls.
+func (f *FakeMessageService) GETAPIV1MessagesCalls() []*GETAPIV1MessagesRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var requests []*GETAPIV1MessagesRequest
+
+	for _, call := range f.calls {
+		if call.Operation == "GETAPIV1Messages" {
+			requests = append(requests, call.Request.(*GETAPIV1MessagesRequest)) //nolint:forcetypeassert // Recorded by method.
+		}
 	}
 
-	return nil, fmt.Errorf("unhandled response code: %d", resp.StatusCode)
+	return requests
 }
@@ -0,0 +1,177 @@
+// Command 03-metrics shows how to expose generated client metrics in
+// Prometheus text format without depending on Prometheus client library.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/vitaminniy/go-lib-http/examples/03-metrics/messageservice"
+	"github.com/vitaminniy/go-lib-http/metrics"
+)
+
+// buckets are histogram upper bounds in seconds.
+//
+//nolint:gochecknoglobals // Constant list.
+var buckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+type series struct {
+	name   string
+	labels metrics.Labels
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// PrometheusRecorder is a metrics.Recorder exposing values in Prometheus text
+// format.
+type PrometheusRecorder struct {
+	mu         sync.Mutex
+	counters   map[series]uint64
+	histograms map[series]*histogram
+}
+
+// NewPrometheusRecorder creates new PrometheusRecorder.
+func NewPrometheusRecorder() *PrometheusRecorder {
+	return &PrometheusRecorder{
+		counters:   make(map[series]uint64),
+		histograms: make(map[series]*histogram),
+	}
+}
+
+// IncCounter implements metrics.Recorder.
+func (p *PrometheusRecorder) IncCounter(name string, labels metrics.Labels) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.counters[series{name: name, labels: labels}]++
+}
+
+// Observe implements metrics.Recorder.
+func (p *PrometheusRecorder) Observe(name string, labels metrics.Labels, value float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := series{name: name, labels: labels}
+
+	hist, ok := p.histograms[key]
+	if !ok {
+		hist = &histogram{counts: make([]uint64, len(buckets))}
+		p.histograms[key] = hist
+	}
+
+	for i, bound := range buckets {
+		if value <= bound {
+			hist.counts[i]++
+		}
+	}
+
+	hist.count++
+	hist.sum += value
+}
+
+// ServeHTTP writes metrics in Prometheus text format.
+func (p *PrometheusRecorder) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	if err := p.Write(w); err != nil {
+		log.Printf("could not write metrics: %v", err)
+	}
+}
+
+// Write writes metrics in Prometheus text format.
+func (p *PrometheusRecorder) Write(w io.Writer) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var lines []string
+
+	for key, value := range p.counters {
+		lines = append(lines, fmt.Sprintf("%s{%s} %d", key.name, format(key.labels, ""), value))
+	}
+
+	for key, hist := range p.histograms {
+		for i, bound := range buckets {
+			le := strconv.FormatFloat(bound, 'g', -1, 64)
+			lines = append(lines, fmt.Sprintf("%s_bucket{%s} %d", key.name, format(key.labels, le), hist.counts[i]))
+		}
+
+		lines = append(lines,
+			fmt.Sprintf("%s_bucket{%s} %d", key.name, format(key.labels, "+Inf"), hist.count),
+			fmt.Sprintf("%s_sum{%s} %g", key.name, format(key.labels, ""), hist.sum),
+			fmt.Sprintf("%s_count{%s} %d", key.name, format(key.labels, ""), hist.count),
+		)
+	}
+
+	sort.Strings(lines)
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+
+	return err //nolint:wrapcheck // Example.
+}
+
+func format(labels metrics.Labels, le string) string {
+	pairs := []string{
+		fmt.Sprintf("operation=%q", labels.Operation),
+		fmt.Sprintf("status_class=%q", labels.StatusClass),
+		fmt.Sprintf("attempt=%q", strconv.FormatUint(uint64(labels.Attempt), 10)),
+		fmt.Sprintf("error_kind=%q", labels.ErrorKind),
+	}
+
+	if le != "" {
+		pairs = append(pairs, fmt.Sprintf("le=%q", le))
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func main() {
+	var calls atomic.Int32
+
+	// Upstream fails every first request to show retries.
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"messages":[]}`)
+	}))
+	defer upstream.Close()
+
+	recorder := NewPrometheusRecorder()
+
+	client, err := messageservice.NewMessageService(upstream.URL, messageservice.WithMetrics(recorder))
+	if err != nil {
+		log.Fatalf("could not create client: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := client.GETAPIV1Messages(context.Background(), &messageservice.GETAPIV1MessagesRequest{
+			HeaderUserAgent: "03-metrics",
+			QueryLimit:      "10",
+		})
+		if err != nil {
+			log.Printf("call failed: %v", err)
+		}
+	}
+
+	// In a real service recorder is registered as "/metrics" handler.
+	if err := recorder.Write(os.Stdout); err != nil {
+		log.Fatalf("could not write metrics: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+// Command 04-server serves generated ServerInterface and calls it with the
+// generated client.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/vitaminniy/go-lib-http/examples/04-server/messageservice"
+)
+
+// messages is an in-memory messageservice.ServerInterface.
+type messages struct {
+	mu       sync.Mutex
+	messages map[string]messageservice.Message
+}
+
+func (m *messages) GETAPIV1MessagesMessageID(
+	_ context.Context,
+	request *messageservice.GETAPIV1MessagesMessageIDRequest,
+) (*messageservice.GETAPIV1MessagesMessageIDResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	message, ok := m.messages[request.PathMessageID]
+	if !ok {
+		return &messageservice.GETAPIV1MessagesMessageIDResponse{
+			Body404: &messageservice.Error{Message: "message not found"},
+		}, nil
+	}
+
+	return &messageservice.GETAPIV1MessagesMessageIDResponse{Body200: &message}, nil
+}
+
+func (m *messages) POSTAPIV1Messages(
+	_ context.Context,
+	request *messageservice.POSTAPIV1MessagesRequest,
+) (*messageservice.POSTAPIV1MessagesResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages[request.Body.ID] = *request.Body
+
+	return &messageservice.POSTAPIV1MessagesResponse{Body201: request.Body}, nil
+}
+
+func main() {
+	impl := &messages{messages: make(map[string]messageservice.Message)}
+
+	srv := httptest.NewServer(messageservice.NewServerHandler(impl))
+	defer srv.Close()
+
+	client, err := messageservice.NewMessageService(srv.URL)
+	if err != nil {
+		log.Fatalf("could not create client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Sub-clients group operations by tag and share client settings.
+	_, err = client.Messages().POSTAPIV1(ctx, &messageservice.POSTAPIV1MessagesRequest{
+		Body: &messageservice.Message{ID: "a/1", Text: "hello"},
+	})
+	if err != nil {
+		log.Fatalf("could not post message: %v", err)
+	}
+
+	resp, err := client.GETAPIV1MessagesMessageID(ctx, &messageservice.GETAPIV1MessagesMessageIDRequest{
+		PathMessageID:   "a/1",
+		HeaderUserAgent: "04-server",
+	})
+	if err != nil {
+		log.Fatalf("could not get message: %v", err)
+	}
+
+	fmt.Printf("got message: %+v\n", *resp.Body200)
+
+	_, err = client.GETAPIV1MessagesMessageID(ctx, &messageservice.GETAPIV1MessagesMessageIDRequest{
+		PathMessageID:   "missing",
+		HeaderUserAgent: "04-server",
+	})
+	fmt.Printf("missing message: %v\n", err)
+
+	// Requests are validated against schema constraints before sending.
+	_, err = client.POSTAPIV1Messages(ctx, &messageservice.POSTAPIV1MessagesRequest{
+		Body: &messageservice.Message{ID: "A 1"},
+	})
+	fmt.Printf("invalid message: %v\n", err)
+
+	// Required parameters are checked by the handler.
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v1/messages/a%2F1", nil)
+	if err != nil {
+		log.Fatalf("could not prepare request: %v", err)
+	}
+
+	// Empty value prevents http.Client from sending the default one.
+	req.Header.Set("User-Agent", "")
+
+	raw, err := srv.Client().Do(req)
+	if err != nil {
+		log.Fatalf("could not get message: %v", err)
+	}
+	defer raw.Body.Close()
+
+	fmt.Printf("without User-Agent: %d\n", raw.StatusCode)
+}
@@ -0,0 +1,196 @@
+// Code generated by go-gen-http -server -sub-clients -client-name MessageService -output-dir messageservice api.yaml. DO NOT EDIT.
+package messageservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/vitaminniy/go-lib-http/auth"
+	"github.com/vitaminniy/go-lib-http/logging"
+	"github.com/vitaminniy/go-lib-http/metrics"
+	"github.com/vitaminniy/go-lib-http/middleware"
+	"github.com/vitaminniy/go-lib-http/policy"
+	"github.com/vitaminniy/go-lib-http/tracing"
+	"github.com/vitaminniy/go-lib-http/validate"
+)
+
+// Option overrides MessageService creation.
+type Option func(*MessageService)
+
+// WithTransport overrides the default http client transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(cl *MessageService) {
+		cl.httpClient.Transport = transport
+	}
+}
+
+// WithTimeout overrides the default http client timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(cl *MessageService) {
+		cl.httpClient.Timeout = timeout
+	}
+}
+
+// WithConfigFunc overrides the default config function.
+func WithConfigFunc(configFunc ConfigFunc) Option {
+	return func(cl *MessageService) {
+		cl.configFunc = configFunc
+	}
+}
+
+// WithMiddleware appends middlewares wrapping every HTTP exchange, including
+// retries and hedged requests. The first middleware is the outermost one.
+func WithMiddleware(middlewares ...middleware.Middleware) Option {
+	return func(cl *MessageService) {
+		cl.middlewares = append(cl.middlewares, middlewares...)
+	}
+}
+
+// WithMetrics sets recorder receiving metrics of every call, HTTP exchange,
+// retry, hedged request and circuit breaker rejection.
+func WithMetrics(recorder metrics.Recorder) Option {
+	return func(cl *MessageService) {
+		cl.recorder = recorder
+	}
+}
+
+// WithTracer sets tracer starting span per operation call and per HTTP
+// exchange. W3C trace context from ctx is propagated to upstream even without
+// tracer.
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(cl *MessageService) {
+		cl.tracer = tracer
+	}
+}
+
+// WithLogger sets logger recording start and finish of every HTTP exchange.
+// Sensitive headers and body fields are redacted, see logging options.
+func WithLogger(logger *slog.Logger, opts ...logging.Option) Option {
+	return func(cl *MessageService) {
+		cl.logger = logger
+		cl.logOptions = opts
+	}
+}
+
+// ResponseViolationHandler receives violations found by strict response
+// validation, see validate.Config.StrictResponse.
+type ResponseViolationHandler func(ctx context.Context, operation middleware.Operation, err *validate.Error)
+
+// WithResponseViolationHandler sets handler receiving violations of strict
+// response validation instead of failing calls with *validate.Error, e.g. to
+// alert on contract drift.
+func WithResponseViolationHandler(handler ResponseViolationHandler) Option {
+	return func(cl *MessageService) {
+		cl.violationHandler = handler
+	}
+}
+
+// NewMessageService creates a new MessageService http client.
+func NewMessageService(baseurl string, opts ...Option) (*MessageService, error) {
+	parsed, err := url.Parse(baseurl)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse base url: %w", err)
+	}
+
+	cli := &MessageService{
+		baseURL:     parsed,
+		credentials: make(map[string]auth.Credentials),
+		httpClient: &http.Client{
+			Timeout: time.Second * 1, // Arbitrary value to avoid hanging forever.
+		},
+	}
+
+	for _, opt := range opts {
+		opt(cli)
+	}
+
+	cli.policies = policy.NewExecutor(policy.WithObserver(metrics.Observer(cli.recorder)))
+
+	// Tracing middleware is the outermost one so others see trace context,
+	// logging one sees headers set by others, and metrics middleware is the
+	// innermost one to measure exchange only.
+	middlewares := make([]middleware.Middleware, 0, len(cli.middlewares)+3) //nolint:gomnd // Builtin middlewares.
+	middlewares = append(middlewares, tracing.Middleware(cli.tracer))
+	middlewares = append(middlewares, cli.middlewares...)
+	middlewares = append(middlewares, logging.Middleware(cli.logger, cli.logOptions...))
+	middlewares = append(middlewares, metrics.Middleware(cli.recorder))
+
+	cli.handler = middleware.Chain(cli.send, middlewares...)
+
+	return cli, nil
+}
+
+type MessageService struct {
+	baseURL          *url.URL
+	httpClient       *http.Client
+	configFunc       ConfigFunc
+	policies         *policy.Executor
+	middlewares      []middleware.Middleware
+	handler          middleware.Handler
+	recorder         metrics.Recorder
+	tracer           tracing.Tracer
+	logger           *slog.Logger
+	logOptions       []logging.Option
+	credentials      map[string]auth.Credentials
+	violationHandler ResponseViolationHandler
+}
+
+// send performs HTTP exchange; it's the innermost middleware handler.
+func (cl *MessageService) send(_ middleware.Operation, req *http.Request) (*http.Response, error) {
+	return cl.httpClient.Do(req)
+}
+
+// pathParam escapes path parameter value; dot segments are escaped as well
+// so they are not resolved when joined with base URL.
+func pathParam(value string) string {
+	switch value {
+	case ".":
+		return "%2E"
+	case "..":
+		return "%2E%2E"
+	default:
+		return url.PathEscape(value)
+	}
+}
+
+// checkResponse checks response body against component schema. Violations are
+// passed to violation handler if it's set and returned otherwise.
+func (cl *MessageService) checkResponse(
+	ctx context.Context,
+	operation middleware.Operation,
+	schema string,
+	raw []byte,
+) error {
+	err := responseSchemas.Check(schema, raw)
+
+	var violations *validate.Error
+	if cl.violationHandler != nil && errors.As(err, &violations) {
+		cl.violationHandler(ctx, operation, violations)
+		return nil
+	}
+
+	return err
+}
+
+func (cl *MessageService) getConfig() Config {
+	if cl.configFunc == nil {
+		return DefaultConfig()
+	}
+
+	return cl.configFunc()
+}
+
+// MessageServiceAPI lists MessageService operations, so consumers can depend on
+// the interface and stub it with FakeMessageService in tests.
+type MessageServiceAPI interface {
+	GETAPIV1MessagesMessageID(ctx context.Context, request *GETAPIV1MessagesMessageIDRequest) (*GETAPIV1MessagesMessageIDResponse, error)
+	POSTAPIV1Messages(ctx context.Context, request *POSTAPIV1MessagesRequest) (*POSTAPIV1MessagesResponse, error)
+	Messages() *MessageServiceMessages
+}
+
+var _ MessageServiceAPI = (*MessageService)(nil)
@@ -0,0 +1,48 @@
+// Code generated by go-gen-http -server -sub-clients -client-name MessageService -output-dir messageservice api.yaml. DO NOT EDIT.
+package messageservice
+
+import (
+	"github.com/vitaminniy/go-lib-http/config"
+)
+
+// MethodConfig controls method behavior. Nil fields are treated as unset and
+// are inherited from Config.Default.
+type MethodConfig = config.QOS
+
+// ConfigFunc returns configuration.
+type ConfigFunc func() Config
+
+// Config contains method configurations. Config files use method names as
+// keys.
+type Config struct {
+	// Default is applied to every method; method configs override its fields.
+	Default                   MethodConfig `json:"Default" yaml:"Default"`
+	GETAPIV1MessagesMessageID MethodConfig `json:"GETAPIV1MessagesMessageID" yaml:"GETAPIV1MessagesMessageID"`
+	POSTAPIV1Messages         MethodConfig `json:"POSTAPIV1Messages" yaml:"POSTAPIV1Messages"`
+}
+
+// DefaultConfig returns default configuration declared in the spec with
+// x-timeout and x-retries extensions.
+func DefaultConfig() Config {
+	return Config{}
+}
+
+// EnvPrefix is a prefix of environment variables overriding configuration,
+// e.g. MESSAGESERVICE_DEFAULT_TIMEOUT=250ms.
+const EnvPrefix = "MESSAGESERVICE"
+
+// ResolveConfig merges DefaultConfig with layers in order of increasing
+// priority, e.g. file, environment and runtime overrides:
+//
+//	file, err := config.FileLayer[Config]("config.yaml")
+//	env, err := config.EnvLayer[Config](EnvPrefix)
+//	cfg, origins := ResolveConfig(file, env)
+//
+// Returned origins explain which layer set each value.
+func ResolveConfig(layers ...config.Layer[Config]) (Config, config.Origins) {
+	all := make([]config.Layer[Config], 0, len(layers)+1)
+	all = append(all, config.Layer[Config]{Name: config.LayerSpec, Config: DefaultConfig()})
+	all = append(all, layers...)
+
+	return config.Resolve(all...)
+}
@@ -0,0 +1,123 @@
+// Code generated by go-gen-http -server -sub-clients -client-name MessageService -output-dir messageservice api.yaml. DO NOT EDIT.
+package messageservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var _ MessageServiceAPI = (*FakeMessageService)(nil)
+
+// ErrNotImplemented is returned by FakeMessageService methods without function
+// set.
+var ErrNotImplemented = errors.New("not implemented")
+
+// FakeMessageServiceCall is a call recorded by FakeMessageService.
+type FakeMessageServiceCall struct {
+	// Operation is a canonical operation name, e.g. "GETAPIV1MessagesMessageID".
+	Operation string
+	// Request is a pointer to operation request, e.g. *GETAPIV1MessagesMessageIDRequest.
+	Request any
+}
+
+// FakeMessageService is an in-memory MessageServiceAPI. Methods call
+// corresponding functions or return ErrNotImplemented if they are not set.
+// All calls are recorded. It's safe for concurrent use as long as functions
+// are not changed during calls.
+type FakeMessageService struct {
+	GETAPIV1MessagesMessageIDFunc func(ctx context.Context, request *GETAPIV1MessagesMessageIDRequest) (*GETAPIV1MessagesMessageIDResponse, error)
+	POSTAPIV1MessagesFunc         func(ctx context.Context, request *POSTAPIV1MessagesRequest) (*POSTAPIV1MessagesResponse, error)
+
+	mu    sync.Mutex
+	calls []FakeMessageServiceCall
+}
+
+// Calls returns recorded calls in order.
+func (f *FakeMessageService) Calls() []FakeMessageServiceCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]FakeMessageServiceCall(nil), f.calls...)
+}
+
+// Reset drops recorded calls.
+func (f *FakeMessageService) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = nil
+}
+
+func (f *FakeMessageService) record(operation string, request any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, FakeMessageServiceCall{Operation: operation, Request: request})
+}
+
+// GETAPIV1MessagesMessageID implements MessageServiceAPI.
+func (f *FakeMessageService) GETAPIV1MessagesMessageID(
+	ctx context.Context,
+	request *GETAPIV1MessagesMessageIDRequest,
+) (*GETAPIV1MessagesMessageIDResponse, error) {
+	f.record("GETAPIV1MessagesMessageID", request)
+
+	if f.GETAPIV1MessagesMessageIDFunc == nil {
+		return nil, fmt.Errorf("%w: GETAPIV1MessagesMessageID", ErrNotImplemented)
+	}
+
+	return f.GETAPIV1MessagesMessageIDFunc(ctx, request)
+}
+
+// GETAPIV1MessagesMessageIDCalls returns requests of recorded GETAPIV1MessagesMessageID calls.
+func (f *FakeMessageService) GETAPIV1MessagesMessageIDCalls() []*GETAPIV1MessagesMessageIDRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var requests []*GETAPIV1MessagesMessageIDRequest
+
+	for _, call := range f.calls {
+		if call.Operation == "GETAPIV1MessagesMessageID" {
+			requests = append(requests, call.Request.(*GETAPIV1MessagesMessageIDRequest)) //nolint:forcetypeassert // Recorded by method.
+		}
+	}
+
+	return requests
+}
+
+// POSTAPIV1Messages implements MessageServiceAPI.
+func (f *FakeMessageService) POSTAPIV1Messages(
+	ctx context.Context,
+	request *POSTAPIV1MessagesRequest,
+) (*POSTAPIV1MessagesResponse, error) {
+	f.record("POSTAPIV1Messages", request)
+
+	if f.POSTAPIV1MessagesFunc == nil {
+		return nil, fmt.Errorf("%w: POSTAPIV1Messages", ErrNotImplemented)
+	}
+
+	return f.POSTAPIV1MessagesFunc(ctx, request)
+}
+
+// POSTAPIV1MessagesCalls returns requests of recorded POSTAPIV1Messages calls.
+func (f *FakeMessageService) POSTAPIV1MessagesCalls() []*POSTAPIV1MessagesRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var requests []*POSTAPIV1MessagesRequest
+
+	for _, call := range f.calls {
+		if call.Operation == "POSTAPIV1Messages" {
+			requests = append(requests, call.Request.(*POSTAPIV1MessagesRequest)) //nolint:forcetypeassert // Recorded by method.
+		}
+	}
+
+	return requests
+}
+
+// Messages returns operations tagged "messages".
+func (f *FakeMessageService) Messages() *MessageServiceMessages {
+	return &MessageServiceMessages{api: f}
+}
@@ -0,0 +1,319 @@
+// Code generated by go-gen-http -server -sub-clients -client-name MessageService -output-dir messageservice api.yaml. DO NOT EDIT.
+package messageservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/vitaminniy/go-lib-http/deadline"
+	"github.com/vitaminniy/go-lib-http/middleware"
+	"github.com/vitaminniy/go-lib-http/policy"
+	"github.com/vitaminniy/go-lib-http/retry"
+	"github.com/vitaminniy/go-lib-http/tracing"
+	"github.com/vitaminniy/go-lib-http/validate"
+)
+
+type GETAPIV1MessagesMessageIDRequest struct {
+	// HeaderUserAgent is "User-Agent" header value.
+	HeaderUserAgent string
+
+	// PathMessageID is "message-id" path parameter.
+	PathMessageID string
+
+	// Headers is a list of additional headers.
+	Headers map[string]string
+}
+
+// Validate checks parameters and body against schema constraints. Violations
+// are reported as *validate.Error with paths like "/query/limit" and
+// "/body/name". Required strings, numbers and booleans are plain values, so
+// missing ones are not told apart from "", 0 and false.
+func (r *GETAPIV1MessagesMessageIDRequest) Validate() error {
+	v := &validate.Validator{}
+	v.Required("/path/message-id", r.PathMessageID != "")
+	v.Required("/header/User-Agent", r.HeaderUserAgent != "")
+
+	return v.Err()
+}
+
+type GETAPIV1MessagesMessageIDResponse struct {
+	Headers map[string][]string
+
+	Body200 *Message
+
+	// Body404 is a documented error response set by server
+	// implementations; clients return error for it.
+	Body404 *Error
+}
+
+//nolint:gochecknoglobals // Operation descriptor passed to middlewares.
+var operationGETAPIV1MessagesMessageID = middleware.Operation{
+	Name:   "GETAPIV1MessagesMessageID",
+	Method: "GET",
+	Path:   "/api/v1/messages/{message-id}",
+	Tags:   []string{"messages"},
+}
+
+func (cl *MessageService) GETAPIV1MessagesMessageID(
+	ctx context.Context,
+	request *GETAPIV1MessagesMessageIDRequest,
+) (*GETAPIV1MessagesMessageIDResponse, error) {
+	url := cl.baseURL.JoinPath("/api/v1/messages/" + pathParam(request.PathMessageID))
+	clientCfg := cl.getConfig()
+	cfg := clientCfg.Default.Merge(clientCfg.GETAPIV1MessagesMessageID)
+
+	if !cfg.Validation.Config().SkipRequest {
+		if err := request.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+	}
+
+	ctx, cancel := cfg.Context(ctx)
+	defer cancel()
+
+	ctx = middleware.WithOperation(ctx, operationGETAPIV1MessagesMessageID)
+
+	// Operation span covers all attempts; HTTP exchanges are its children.
+	ctx, span := tracing.StartOperation(ctx, cl.tracer, operationGETAPIV1MessagesMessageID)
+
+	result, err := policy.Do(ctx, cl.policies, operationGETAPIV1MessagesMessageID.Name, cfg, func(ctx context.Context) (*GETAPIV1MessagesMessageIDResponse, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url.String(), nil)
+		if err != nil {
+			return nil, retry.Abort(fmt.Errorf("could not prepare request: %w", err))
+		}
+
+		req.Header.Add("Accept", "application/json")
+
+		req.Header.Add("User-Agent", request.HeaderUserAgent)
+
+		for key, value := range request.Headers {
+			req.Header.Set(key, value)
+		}
+
+		if err := deadline.Inject(ctx, req.Header, cfg.Deadline.Config()); err != nil {
+			return nil, retry.Abort(err)
+		}
+
+		resp, err := cl.handler(operationGETAPIV1MessagesMessageID, req)
+		if err != nil {
+			return nil, fmt.Errorf("could not do http request: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("could not read response with status %d: %w", resp.StatusCode, err)
+			}
+
+			err = fmt.Errorf("got response with status %d: %q", resp.StatusCode, string(raw))
+
+			// Client errors won't go away on retry.
+			if resp.StatusCode < http.StatusInternalServerError {
+				return nil, retry.Abort(err)
+			}
+
+			return nil, err
+		}
+
+		response := &GETAPIV1MessagesMessageIDResponse{
+			Headers: resp.Header,
+		}
+
+		if resp.StatusCode == 200 {
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("could not read response [%d]: %w", resp.StatusCode, err)
+			}
+
+			if cfg.Validation.Config().StrictResponse {
+				if err := cl.checkResponse(ctx, operationGETAPIV1MessagesMessageID, "Message", raw); err != nil {
+					return nil, retry.Abort(fmt.Errorf("invalid response [%d]: %w", resp.StatusCode, err))
+				}
+			}
+
+			var body Message
+			if err := json.Unmarshal(raw, &body); err != nil {
+				return nil, retry.Abort(fmt.Errorf("could not decode response [%d]: %w", resp.StatusCode, err))
+			}
+
+			response.Body200 = &body
+
+			return response, nil
+		}
+
+		return nil, retry.Abort(fmt.Errorf("unhandled response code: %d", resp.StatusCode))
+	})
+
+	span.End(err)
+
+	return result, err
+}
+
+type POSTAPIV1MessagesRequest struct {
+	// Headers is a list of additional headers.
+	Headers map[string]string
+
+	// Body is a request body.
+	Body *Message
+}
+
+// Validate checks parameters and body against schema constraints. Violations
+// are reported as *validate.Error with paths like "/query/limit" and
+// "/body/name". Required strings, numbers and booleans are plain values, so
+// missing ones are not told apart from "", 0 and false.
+func (r *POSTAPIV1MessagesRequest) Validate() error {
+	v := &validate.Validator{}
+	v.Required("/body", r.Body != nil)
+	if r.Body != nil {
+		r.Body.validate(v, "/body")
+	}
+
+	return v.Err()
+}
+
+type POSTAPIV1MessagesResponse struct {
+	Headers map[string][]string
+
+	Body201 *Message
+}
+
+//nolint:gochecknoglobals // Operation descriptor passed to middlewares.
+var operationPOSTAPIV1Messages = middleware.Operation{
+	Name:   "POSTAPIV1Messages",
+	Method: "POST",
+	Path:   "/api/v1/messages",
+	Tags:   []string{"messages"},
+}
+
+func (cl *MessageService) POSTAPIV1Messages(
+	ctx context.Context,
+	request *POSTAPIV1MessagesRequest,
+) (*POSTAPIV1MessagesResponse, error) {
+	url := cl.baseURL.JoinPath("/api/v1/messages")
+	clientCfg := cl.getConfig()
+	cfg := clientCfg.Default.Merge(clientCfg.POSTAPIV1Messages)
+
+	if !cfg.Validation.Config().SkipRequest {
+		if err := request.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+	}
+
+	ctx, cancel := cfg.Context(ctx)
+	defer cancel()
+
+	ctx = middleware.WithOperation(ctx, operationPOSTAPIV1Messages)
+
+	body := &bytes.Buffer{}
+	if err := json.NewEncoder(body).Encode(&request.Body); err != nil {
+		return nil, fmt.Errorf("could not encode request body: %w", err)
+	}
+
+	// Operation span covers all attempts; HTTP exchanges are its children.
+	ctx, span := tracing.StartOperation(ctx, cl.tracer, operationPOSTAPIV1Messages)
+
+	result, err := policy.Do(ctx, cl.policies, operationPOSTAPIV1Messages.Name, cfg, func(ctx context.Context) (*POSTAPIV1MessagesResponse, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", url.String(), bytes.NewReader(body.Bytes()))
+		if err != nil {
+			return nil, retry.Abort(fmt.Errorf("could not prepare request: %w", err))
+		}
+
+		req.Header.Add("Content-Type", "application/json")
+
+		req.Header.Add("Accept", "application/json")
+
+		for key, value := range request.Headers {
+			req.Header.Set(key, value)
+		}
+
+		if err := deadline.Inject(ctx, req.Header, cfg.Deadline.Config()); err != nil {
+			return nil, retry.Abort(err)
+		}
+
+		resp, err := cl.handler(operationPOSTAPIV1Messages, req)
+		if err != nil {
+			return nil, fmt.Errorf("could not do http request: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("could not read response with status %d: %w", resp.StatusCode, err)
+			}
+
+			err = fmt.Errorf("got response with status %d: %q", resp.StatusCode, string(raw))
+
+			// Client errors won't go away on retry.
+			if resp.StatusCode < http.StatusInternalServerError {
+				return nil, retry.Abort(err)
+			}
+
+			return nil, err
+		}
+
+		response := &POSTAPIV1MessagesResponse{
+			Headers: resp.Header,
+		}
+
+		if resp.StatusCode == 201 {
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("could not read response [%d]: %w", resp.StatusCode, err)
+			}
+
+			if cfg.Validation.Config().StrictResponse {
+				if err := cl.checkResponse(ctx, operationPOSTAPIV1Messages, "Message", raw); err != nil {
+					return nil, retry.Abort(fmt.Errorf("invalid response [%d]: %w", resp.StatusCode, err))
+				}
+			}
+
+			var body Message
+			if err := json.Unmarshal(raw, &body); err != nil {
+				return nil, retry.Abort(fmt.Errorf("could not decode response [%d]: %w", resp.StatusCode, err))
+			}
+
+			response.Body201 = &body
+
+			return response, nil
+		}
+
+		return nil, retry.Abort(fmt.Errorf("unhandled response code: %d", resp.StatusCode))
+	})
+
+	span.End(err)
+
+	return result, err
+}
+
+// MessageServiceMessages groups MessageService operations tagged "messages". It
+// calls the parent, so transport, config and middleware are shared.
+type MessageServiceMessages struct {
+	api MessageServiceAPI
+}
+
+// Messages returns operations tagged "messages".
+func (cl *MessageService) Messages() *MessageServiceMessages {
+	return &MessageServiceMessages{api: cl}
+}
+
+// GETAPIV1MessagesMessageID calls GETAPIV1MessagesMessageID.
+func (c *MessageServiceMessages) GETAPIV1MessagesMessageID(
+	ctx context.Context,
+	request *GETAPIV1MessagesMessageIDRequest,
+) (*GETAPIV1MessagesMessageIDResponse, error) {
+	return c.api.GETAPIV1MessagesMessageID(ctx, request)
+}
+
+// POSTAPIV1 calls POSTAPIV1Messages.
+func (c *MessageServiceMessages) POSTAPIV1(
+	ctx context.Context,
+	request *POSTAPIV1MessagesRequest,
+) (*POSTAPIV1MessagesResponse, error) {
+	return c.api.POSTAPIV1Messages(ctx, request)
+}
@@ -0,0 +1,73 @@
+// Code generated by go-gen-http -server -sub-clients -client-name MessageService -output-dir messageservice api.yaml. DO NOT EDIT.
+package messageservice
+
+import (
+	"github.com/vitaminniy/go-lib-http/validate"
+)
+
+type Message struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// Validate checks Message against schema constraints. Violations are
+// reported as *validate.Error. Required strings, numbers and booleans are
+// plain values, so missing ones are not told apart from "", 0 and false.
+func (m *Message) Validate() error {
+	v := &validate.Validator{}
+	m.validate(v, "")
+
+	return v.Err()
+}
+
+func (m *Message) validate(v *validate.Validator, path string) {
+	v.Pattern(path+"/id", m.ID, `^[a-z0-9/]+$`)
+	v.MinLength(path+"/text", m.Text, 1)
+	v.MaxLength(path+"/text", m.Text, 280)
+}
+
+type Error struct {
+	Message string `json:"message"`
+}
+
+// Validate checks Error against schema constraints. Violations are
+// reported as *validate.Error. Required strings, numbers and booleans are
+// plain values, so missing ones are not told apart from "", 0 and false.
+func (m *Error) Validate() error {
+	v := &validate.Validator{}
+	m.validate(v, "")
+
+	return v.Err()
+}
+
+func (m *Error) validate(v *validate.Validator, path string) {
+}
+
+// responseSchemas describe components for strict response validation.
+//
+//nolint:gochecknoglobals // Read-only schemas.
+var responseSchemas = validate.Schemas{
+	"Message": {
+		Type: "object",
+		Properties: map[string]*validate.Schema{
+			"id": {
+				Type: "string",
+			},
+			"text": {
+				Type: "string",
+			},
+		},
+		Required: []string{"id", "text"},
+		Closed:   true,
+	},
+	"Error": {
+		Type: "object",
+		Properties: map[string]*validate.Schema{
+			"message": {
+				Type: "string",
+			},
+		},
+		Required: []string{"message"},
+		Closed:   true,
+	},
+}
@@ -0,0 +1,126 @@
+// Code generated by go-gen-http -server -sub-clients -client-name MessageService -output-dir messageservice api.yaml. DO NOT EDIT.
+package messageservice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/vitaminniy/go-lib-http/server"
+)
+
+// ServerInterface is implemented by MessageService server. Methods return
+// response with exactly one body set; errors are written by server error
+// handler.
+type ServerInterface interface {
+	GETAPIV1MessagesMessageID(ctx context.Context, request *GETAPIV1MessagesMessageIDRequest) (*GETAPIV1MessagesMessageIDResponse, error)
+	POSTAPIV1Messages(ctx context.Context, request *POSTAPIV1MessagesRequest) (*POSTAPIV1MessagesResponse, error)
+}
+
+// ServerOption overrides server handler creation.
+type ServerOption func(*serverHandler)
+
+// WithServerErrorHandler overrides server.DefaultErrorHandler.
+func WithServerErrorHandler(handler server.ErrorHandler) ServerOption {
+	return func(s *serverHandler) {
+		s.errorHandler = handler
+	}
+}
+
+type serverHandler struct {
+	impl         ServerInterface
+	errorHandler server.ErrorHandler
+}
+
+// NewServerHandler returns handler routing operations to impl. Requests are
+// decoded and validated against schema constraints before impl is called.
+func NewServerHandler(impl ServerInterface, opts ...ServerOption) http.Handler {
+	s := &serverHandler{
+		impl:         impl,
+		errorHandler: server.DefaultErrorHandler,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/messages/{MessageID}", s.handleGETAPIV1MessagesMessageID)
+	mux.HandleFunc("POST /api/v1/messages", s.handlePOSTAPIV1Messages)
+
+	return mux
+}
+
+func (s *serverHandler) handleGETAPIV1MessagesMessageID(w http.ResponseWriter, r *http.Request) {
+	request := &GETAPIV1MessagesMessageIDRequest{}
+
+	request.PathMessageID = r.PathValue("MessageID")
+
+	request.HeaderUserAgent = r.Header.Get("User-Agent")
+	if request.HeaderUserAgent == "" {
+		s.errorHandler(w, r, &server.RequestError{In: server.InHeader, Name: "User-Agent", Err: server.ErrMissing})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		s.errorHandler(w, r, err)
+		return
+	}
+
+	response, err := s.impl.GETAPIV1MessagesMessageID(r.Context(), request)
+	if err == nil && response == nil {
+		err = fmt.Errorf("%w: GETAPIV1MessagesMessageID", server.ErrNoResponse)
+	}
+
+	if err != nil {
+		s.errorHandler(w, r, err)
+		return
+	}
+
+	server.WriteHeaders(w, response.Headers)
+
+	switch {
+	case response.Body200 != nil:
+		server.WriteJSON(w, 200, response.Body200)
+	case response.Body404 != nil:
+		server.WriteJSON(w, 404, response.Body404)
+	default:
+		s.errorHandler(w, r, fmt.Errorf("%w: GETAPIV1MessagesMessageID", server.ErrNoResponse))
+	}
+}
+
+func (s *serverHandler) handlePOSTAPIV1Messages(w http.ResponseWriter, r *http.Request) {
+	request := &POSTAPIV1MessagesRequest{}
+
+	body := &Message{}
+	if ok, err := server.DecodeJSON(r, body, true); err != nil {
+		s.errorHandler(w, r, err)
+		return
+	} else if ok {
+		request.Body = body
+	}
+
+	if err := request.Validate(); err != nil {
+		s.errorHandler(w, r, err)
+		return
+	}
+
+	response, err := s.impl.POSTAPIV1Messages(r.Context(), request)
+	if err == nil && response == nil {
+		err = fmt.Errorf("%w: POSTAPIV1Messages", server.ErrNoResponse)
+	}
+
+	if err != nil {
+		s.errorHandler(w, r, err)
+		return
+	}
+
+	server.WriteHeaders(w, response.Headers)
+
+	switch {
+	case response.Body201 != nil:
+		server.WriteJSON(w, 201, response.Body201)
+	default:
+		s.errorHandler(w, r, fmt.Errorf("%w: POSTAPIV1Messages", server.ErrNoResponse))
+	}
+}
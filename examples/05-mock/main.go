@@ -0,0 +1,93 @@
+// Command 05-mock serves examples from the spec with generated mock handler
+// and scripts one of the operations.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http/httptest"
+
+	"github.com/vitaminniy/go-lib-http/config"
+	"github.com/vitaminniy/go-lib-http/examples/05-mock/messageservice"
+	"github.com/vitaminniy/go-lib-http/middleware"
+	"github.com/vitaminniy/go-lib-http/validate"
+)
+
+func main() {
+	fake := &messageservice.FakeMessageService{}
+
+	srv := httptest.NewServer(messageservice.NewMockHandler(fake))
+	defer srv.Close()
+
+	client, err := messageservice.NewMessageService(srv.URL)
+	if err != nil {
+		log.Fatalf("could not create client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Served from the example declared in the spec.
+	message, err := client.GETAPIV1MessagesMessageID(ctx, &messageservice.GETAPIV1MessagesMessageIDRequest{
+		PathMessageID: "1",
+	})
+	if err != nil {
+		log.Fatalf("could not get message: %v", err)
+	}
+
+	fmt.Printf("example message: %+v\n", *message.Body200)
+
+	// Synthesized from the schema.
+	list, err := client.GETAPIV1Messages(ctx, &messageservice.GETAPIV1MessagesRequest{})
+	if err != nil {
+		log.Fatalf("could not list messages: %v", err)
+	}
+
+	fmt.Printf("synthesized list: %d messages, first %+v\n", len(list.Body200.Messages), list.Body200.Messages[0])
+
+	// Strict response validation reports contract drift: example id is not a
+	// UUID as declared in the schema.
+	strict, err := messageservice.NewMessageService(srv.URL,
+		messageservice.WithConfigFunc(func() messageservice.Config {
+			cfg := messageservice.DefaultConfig()
+			cfg.Default.Validation.StrictResponse = config.Ptr(true)
+
+			return cfg
+		}),
+		messageservice.WithResponseViolationHandler(func(
+			_ context.Context,
+			operation middleware.Operation,
+			err *validate.Error,
+		) {
+			fmt.Printf("%s contract drift: %v\n", operation.Name, err)
+		}),
+	)
+	if err != nil {
+		log.Fatalf("could not create client: %v", err)
+	}
+
+	if _, err = strict.GETAPIV1MessagesMessageID(ctx, &messageservice.GETAPIV1MessagesMessageIDRequest{
+		PathMessageID: "1",
+	}); err != nil {
+		log.Fatalf("could not get message: %v", err)
+	}
+
+	// Scripted per test.
+	fake.GETAPIV1MessagesMessageIDFunc = func(
+		_ context.Context,
+		_ *messageservice.GETAPIV1MessagesMessageIDRequest,
+	) (*messageservice.GETAPIV1MessagesMessageIDResponse, error) {
+		return &messageservice.GETAPIV1MessagesMessageIDResponse{
+			Body404: &messageservice.Error{Message: "message not found"},
+		}, nil
+	}
+
+	_, err = client.GETAPIV1MessagesMessageID(ctx, &messageservice.GETAPIV1MessagesMessageIDRequest{
+		PathMessageID: "2",
+	})
+	fmt.Printf("scripted response: %v\n", err)
+
+	for _, request := range fake.GETAPIV1MessagesMessageIDCalls() {
+		fmt.Printf("requested message %q\n", request.PathMessageID)
+	}
+}
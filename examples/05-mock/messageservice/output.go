@@ -0,0 +1,1128 @@
+// Code generated by go-gen-http. DO NOT EDIT.
+package messageservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/vitaminniy/go-lib-http/auth"
+	"github.com/vitaminniy/go-lib-http/config"
+	"github.com/vitaminniy/go-lib-http/deadline"
+	"github.com/vitaminniy/go-lib-http/logging"
+	"github.com/vitaminniy/go-lib-http/metrics"
+	"github.com/vitaminniy/go-lib-http/middleware"
+	"github.com/vitaminniy/go-lib-http/policy"
+	"github.com/vitaminniy/go-lib-http/retry"
+	"github.com/vitaminniy/go-lib-http/server"
+	"github.com/vitaminniy/go-lib-http/tracing"
+	"github.com/vitaminniy/go-lib-http/validate"
+)
+
+// Option overrides MessageService creation.
+type Option func(*MessageService)
+
+// WithTransport overrides the default http client transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(cl *MessageService) {
+		cl.httpClient.Transport = transport
+	}
+}
+
+// WithTimeout overrides the default http client timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(cl *MessageService) {
+		cl.httpClient.Timeout = timeout
+	}
+}
+
+// WithConfigFunc overrides the default config function.
+func WithConfigFunc(configFunc ConfigFunc) Option {
+	return func(cl *MessageService) {
+		cl.configFunc = configFunc
+	}
+}
+
+// WithMiddleware appends middlewares wrapping every HTTP exchange, including
+// retries and hedged requests. The first middleware is the outermost one.
+func WithMiddleware(middlewares ...middleware.Middleware) Option {
+	return func(cl *MessageService) {
+		cl.middlewares = append(cl.middlewares, middlewares...)
+	}
+}
+
+// WithMetrics sets recorder receiving metrics of every call, HTTP exchange,
+// retry, hedged request and circuit breaker rejection.
+func WithMetrics(recorder metrics.Recorder) Option {
+	return func(cl *MessageService) {
+		cl.recorder = recorder
+	}
+}
+
+// WithTracer sets tracer starting span per operation call and per HTTP
+// exchange. W3C trace context from ctx is propagated to upstream even without
+// tracer.
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(cl *MessageService) {
+		cl.tracer = tracer
+	}
+}
+
+// WithLogger sets logger recording start and finish of every HTTP exchange.
+// Sensitive headers and body fields are redacted, see logging options.
+func WithLogger(logger *slog.Logger, opts ...logging.Option) Option {
+	return func(cl *MessageService) {
+		cl.logger = logger
+		cl.logOptions = opts
+	}
+}
+
+// ResponseViolationHandler receives violations found by strict response
+// validation, see validate.Config.StrictResponse.
+type ResponseViolationHandler func(ctx context.Context, operation middleware.Operation, err *validate.Error)
+
+// WithResponseViolationHandler sets handler receiving violations of strict
+// response validation instead of failing calls with *validate.Error, e.g. to
+// alert on contract drift.
+func WithResponseViolationHandler(handler ResponseViolationHandler) Option {
+	return func(cl *MessageService) {
+		cl.violationHandler = handler
+	}
+}
+
+// NewMessageService creates a new MessageService http client.
+func NewMessageService(baseurl string, opts ...Option) (*MessageService, error) {
+	parsed, err := url.Parse(baseurl)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse base url: %w", err)
+	}
+
+	cli := &MessageService{
+		baseURL:     parsed,
+		credentials: make(map[string]auth.Credentials),
+		httpClient: &http.Client{
+			Timeout: time.Second * 1, // Arbitrary value to avoid hanging forever.
+		},
+	}
+
+	for _, opt := range opts {
+		opt(cli)
+	}
+
+	cli.policies = policy.NewExecutor(policy.WithObserver(metrics.Observer(cli.recorder)))
+
+	// Tracing middleware is the outermost one so others see trace context,
+	// logging one sees headers set by others, and metrics middleware is the
+	// innermost one to measure exchange only.
+	middlewares := make([]middleware.Middleware, 0, len(cli.middlewares)+3) //nolint:gomnd // Builtin middlewares.
+	middlewares = append(middlewares, tracing.Middleware(cli.tracer))
+	middlewares = append(middlewares, cli.middlewares...)
+	middlewares = append(middlewares, logging.Middleware(cli.logger, cli.logOptions...))
+	middlewares = append(middlewares, metrics.Middleware(cli.recorder))
+
+	cli.handler = middleware.Chain(cli.send, middlewares...)
+
+	return cli, nil
+}
+
+type MessageService struct {
+	baseURL          *url.URL
+	httpClient       *http.Client
+	configFunc       ConfigFunc
+	policies         *policy.Executor
+	middlewares      []middleware.Middleware
+	handler          middleware.Handler
+	recorder         metrics.Recorder
+	tracer           tracing.Tracer
+	logger           *slog.Logger
+	logOptions       []logging.Option
+	credentials      map[string]auth.Credentials
+	violationHandler ResponseViolationHandler
+}
+
+// send performs HTTP exchange; it's the innermost middleware handler.
+func (cl *MessageService) send(_ middleware.Operation, req *http.Request) (*http.Response, error) {
+	return cl.httpClient.Do(req)
+}
+
+// pathParam escapes path parameter value; dot segments are escaped as well
+// so they are not resolved when joined with base URL.
+func pathParam(value string) string {
+	switch value {
+	case ".":
+		return "%2E"
+	case "..":
+		return "%2E%2E"
+	default:
+		return url.PathEscape(value)
+	}
+}
+
+// checkResponse checks response body against component schema. Violations are
+// passed to violation handler if it's set and returned otherwise.
+func (cl *MessageService) checkResponse(
+	ctx context.Context,
+	operation middleware.Operation,
+	schema string,
+	raw []byte,
+) error {
+	err := responseSchemas.Check(schema, raw)
+
+	var violations *validate.Error
+	if cl.violationHandler != nil && errors.As(err, &violations) {
+		cl.violationHandler(ctx, operation, violations)
+		return nil
+	}
+
+	return err
+}
+
+func (cl *MessageService) getConfig() Config {
+	if cl.configFunc == nil {
+		return DefaultConfig()
+	}
+
+	return cl.configFunc()
+}
+
+// MessageServiceAPI lists MessageService operations, so consumers can depend on
+// the interface and stub it with FakeMessageService in tests.
+type MessageServiceAPI interface {
+	GETAPIV1MessagesMessageID(ctx context.Context, request *GETAPIV1MessagesMessageIDRequest) (*GETAPIV1MessagesMessageIDResponse, error)
+	GETAPIV1Messages(ctx context.Context, request *GETAPIV1MessagesRequest) (*GETAPIV1MessagesResponse, error)
+	POSTAPIV1Messages(ctx context.Context, request *POSTAPIV1MessagesRequest) (*POSTAPIV1MessagesResponse, error)
+}
+
+var _ MessageServiceAPI = (*MessageService)(nil)
+
+// MethodConfig controls method behavior. Nil fields are treated as unset and
+// are inherited from Config.Default.
+type MethodConfig = config.QOS
+
+// ConfigFunc returns configuration.
+type ConfigFunc func() Config
+
+// Config contains method configurations. Config files use method names as
+// keys.
+type Config struct {
+	// Default is applied to every method; method configs override its fields.
+	Default                   MethodConfig `json:"Default" yaml:"Default"`
+	GETAPIV1MessagesMessageID MethodConfig `json:"GETAPIV1MessagesMessageID" yaml:"GETAPIV1MessagesMessageID"`
+	GETAPIV1Messages          MethodConfig `json:"GETAPIV1Messages" yaml:"GETAPIV1Messages"`
+	POSTAPIV1Messages         MethodConfig `json:"POSTAPIV1Messages" yaml:"POSTAPIV1Messages"`
+}
+
+// DefaultConfig returns default configuration declared in the spec with
+// x-timeout and x-retries extensions.
+func DefaultConfig() Config {
+	return Config{}
+}
+
+// EnvPrefix is a prefix of environment variables overriding configuration,
+// e.g. MESSAGESERVICE_DEFAULT_TIMEOUT=250ms.
+const EnvPrefix = "MESSAGESERVICE"
+
+// ResolveConfig merges DefaultConfig with layers in order of increasing
+// priority, e.g. file, environment and runtime overrides:
+//
+//	file, err := config.FileLayer[Config]("config.yaml")
+//	env, err := config.EnvLayer[Config](EnvPrefix)
+//	cfg, origins := ResolveConfig(file, env)
+//
+// Returned origins explain which layer set each value.
+func ResolveConfig(layers ...config.Layer[Config]) (Config, config.Origins) {
+	all := make([]config.Layer[Config], 0, len(layers)+1)
+	all = append(all, config.Layer[Config]{Name: config.LayerSpec, Config: DefaultConfig()})
+	all = append(all, layers...)
+
+	return config.Resolve(all...)
+}
+
+type Message struct {
+	ID     string `json:"id"`
+	Text   string `json:"text"`
+	Author Author `json:"author,omitempty"`
+}
+
+// Validate checks Message against schema constraints. Violations are
+// reported as *validate.Error. Required strings, numbers and booleans are
+// plain values, so missing ones are not told apart from "", 0 and false.
+func (m *Message) Validate() error {
+	v := &validate.Validator{}
+	m.validate(v, "")
+
+	return v.Err()
+}
+
+func (m *Message) validate(v *validate.Validator, path string) {
+	v.Format(path+"/id", m.ID, "uuid")
+	if !validate.IsZero(m.Author) {
+		m.Author.validate(v, path+"/author")
+	}
+}
+
+type Author struct {
+	Name string `json:"name,omitempty"`
+}
+
+// Validate checks Author against schema constraints. Violations are
+// reported as *validate.Error. Required strings, numbers and booleans are
+// plain values, so missing ones are not told apart from "", 0 and false.
+func (m *Author) Validate() error {
+	v := &validate.Validator{}
+	m.validate(v, "")
+
+	return v.Err()
+}
+
+func (m *Author) validate(v *validate.Validator, path string) {
+}
+
+type MessageList struct {
+	Messages []Message `json:"messages"`
+	Total    *int64    `json:"total,omitempty"`
+}
+
+// Validate checks MessageList against schema constraints. Violations are
+// reported as *validate.Error. Required strings, numbers and booleans are
+// plain values, so missing ones are not told apart from "", 0 and false.
+func (m *MessageList) Validate() error {
+	v := &validate.Validator{}
+	m.validate(v, "")
+
+	return v.Err()
+}
+
+func (m *MessageList) validate(v *validate.Validator, path string) {
+	v.Required(path+"/messages", m.Messages != nil)
+	if m.Messages != nil {
+		v.MinItems(path+"/messages", len(m.Messages), 2)
+		for i := range m.Messages {
+			m.Messages[i].validate(v, validate.Index(path+"/messages", i))
+		}
+	}
+	if m.Total != nil {
+		v.Minimum(path+"/total", float64(*m.Total), 2, false)
+	}
+}
+
+type Error struct {
+	Message string `json:"message"`
+}
+
+// Validate checks Error against schema constraints. Violations are
+// reported as *validate.Error. Required strings, numbers and booleans are
+// plain values, so missing ones are not told apart from "", 0 and false.
+func (m *Error) Validate() error {
+	v := &validate.Validator{}
+	m.validate(v, "")
+
+	return v.Err()
+}
+
+func (m *Error) validate(v *validate.Validator, path string) {
+}
+
+// responseSchemas describe components for strict response validation.
+//
+//nolint:gochecknoglobals // Read-only schemas.
+var responseSchemas = validate.Schemas{
+	"Message": {
+		Type: "object",
+		Properties: map[string]*validate.Schema{
+			"id": {
+				Type:   "string",
+				Format: "uuid",
+			},
+			"text": {
+				Type: "string",
+			},
+			"author": {
+				Ref: "Author",
+			},
+		},
+		Required: []string{"id", "text"},
+		Closed:   true,
+	},
+	"Author": {
+		Type: "object",
+		Properties: map[string]*validate.Schema{
+			"name": {
+				Type: "string",
+			},
+		},
+		Closed: true,
+	},
+	"MessageList": {
+		Type: "object",
+		Properties: map[string]*validate.Schema{
+			"messages": {
+				Type: "array",
+				Items: &validate.Schema{
+					Ref: "Message",
+				},
+			},
+			"total": {
+				Type: "integer",
+			},
+		},
+		Required: []string{"messages"},
+		Closed:   true,
+	},
+	"Error": {
+		Type: "object",
+		Properties: map[string]*validate.Schema{
+			"message": {
+				Type: "string",
+			},
+		},
+		Required: []string{"message"},
+		Closed:   true,
+	},
+}
+
+type GETAPIV1MessagesMessageIDRequest struct {
+
+	// PathMessageID is "message-id" path parameter.
+	PathMessageID string
+
+	// Headers is a list of additional headers.
+	Headers map[string]string
+}
+
+// Validate checks parameters and body against schema constraints. Violations
+// are reported as *validate.Error with paths like "/query/limit" and
+// "/body/name". Required strings, numbers and booleans are plain values, so
+// missing ones are not told apart from "", 0 and false.
+func (r *GETAPIV1MessagesMessageIDRequest) Validate() error {
+	v := &validate.Validator{}
+	v.Required("/path/message-id", r.PathMessageID != "")
+
+	return v.Err()
+}
+
+type GETAPIV1MessagesMessageIDResponse struct {
+	Headers map[string][]string
+
+	Body200 *Message
+
+	// Body404 is a documented error response set by server
+	// implementations; clients return error for it.
+	Body404 *Error
+}
+
+//nolint:gochecknoglobals // Operation descriptor passed to middlewares.
+var operationGETAPIV1MessagesMessageID = middleware.Operation{
+	Name:   "GETAPIV1MessagesMessageID",
+	Method: "GET",
+	Path:   "/api/v1/messages/{message-id}",
+	Tags:   []string{"messages"},
+}
+
+func (cl *MessageService) GETAPIV1MessagesMessageID(
+	ctx context.Context,
+	request *GETAPIV1MessagesMessageIDRequest,
+) (*GETAPIV1MessagesMessageIDResponse, error) {
+	url := cl.baseURL.JoinPath("/api/v1/messages/" + pathParam(request.PathMessageID))
+	clientCfg := cl.getConfig()
+	cfg := clientCfg.Default.Merge(clientCfg.GETAPIV1MessagesMessageID)
+
+	if !cfg.Validation.Config().SkipRequest {
+		if err := request.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+	}
+
+	ctx, cancel := cfg.Context(ctx)
+	defer cancel()
+
+	ctx = middleware.WithOperation(ctx, operationGETAPIV1MessagesMessageID)
+
+	// Operation span covers all attempts; HTTP exchanges are its children.
+	ctx, span := tracing.StartOperation(ctx, cl.tracer, operationGETAPIV1MessagesMessageID)
+
+	result, err := policy.Do(ctx, cl.policies, operationGETAPIV1MessagesMessageID.Name, cfg, func(ctx context.Context) (*GETAPIV1MessagesMessageIDResponse, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url.String(), nil)
+		if err != nil {
+			return nil, retry.Abort(fmt.Errorf("could not prepare request: %w", err))
+		}
+
+		req.Header.Add("Accept", "application/json")
+
+		for key, value := range request.Headers {
+			req.Header.Set(key, value)
+		}
+
+		if err := deadline.Inject(ctx, req.Header, cfg.Deadline.Config()); err != nil {
+			return nil, retry.Abort(err)
+		}
+
+		resp, err := cl.handler(operationGETAPIV1MessagesMessageID, req)
+		if err != nil {
+			return nil, fmt.Errorf("could not do http request: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("could not read response with status %d: %w", resp.StatusCode, err)
+			}
+
+			err = fmt.Errorf("got response with status %d: %q", resp.StatusCode, string(raw))
+
+			// Client errors won't go away on retry.
+			if resp.StatusCode < http.StatusInternalServerError {
+				return nil, retry.Abort(err)
+			}
+
+			return nil, err
+		}
+
+		response := &GETAPIV1MessagesMessageIDResponse{
+			Headers: resp.Header,
+		}
+
+		if resp.StatusCode == 200 {
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("could not read response [%d]: %w", resp.StatusCode, err)
+			}
+
+			if cfg.Validation.Config().StrictResponse {
+				if err := cl.checkResponse(ctx, operationGETAPIV1MessagesMessageID, "Message", raw); err != nil {
+					return nil, retry.Abort(fmt.Errorf("invalid response [%d]: %w", resp.StatusCode, err))
+				}
+			}
+
+			var body Message
+			if err := json.Unmarshal(raw, &body); err != nil {
+				return nil, retry.Abort(fmt.Errorf("could not decode response [%d]: %w", resp.StatusCode, err))
+			}
+
+			response.Body200 = &body
+
+			return response, nil
+		}
+
+		return nil, retry.Abort(fmt.Errorf("unhandled response code: %d", resp.StatusCode))
+	})
+
+	span.End(err)
+
+	return result, err
+}
+
+type GETAPIV1MessagesRequest struct {
+	// Headers is a list of additional headers.
+	Headers map[string]string
+}
+
+// Validate checks parameters and body against schema constraints. Violations
+// are reported as *validate.Error with paths like "/query/limit" and
+// "/body/name". Required strings, numbers and booleans are plain values, so
+// missing ones are not told apart from "", 0 and false.
+func (r *GETAPIV1MessagesRequest) Validate() error {
+	v := &validate.Validator{}
+
+	return v.Err()
+}
+
+type GETAPIV1MessagesResponse struct {
+	Headers map[string][]string
+
+	Body200 *MessageList
+}
+
+//nolint:gochecknoglobals // Operation descriptor passed to middlewares.
+var operationGETAPIV1Messages = middleware.Operation{
+	Name:   "GETAPIV1Messages",
+	Method: "GET",
+	Path:   "/api/v1/messages",
+	Tags:   []string{"messages"},
+}
+
+func (cl *MessageService) GETAPIV1Messages(
+	ctx context.Context,
+	request *GETAPIV1MessagesRequest,
+) (*GETAPIV1MessagesResponse, error) {
+	url := cl.baseURL.JoinPath("/api/v1/messages")
+	clientCfg := cl.getConfig()
+	cfg := clientCfg.Default.Merge(clientCfg.GETAPIV1Messages)
+
+	if !cfg.Validation.Config().SkipRequest {
+		if err := request.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+	}
+
+	ctx, cancel := cfg.Context(ctx)
+	defer cancel()
+
+	ctx = middleware.WithOperation(ctx, operationGETAPIV1Messages)
+
+	// Operation span covers all attempts; HTTP exchanges are its children.
+	ctx, span := tracing.StartOperation(ctx, cl.tracer, operationGETAPIV1Messages)
+
+	result, err := policy.Do(ctx, cl.policies, operationGETAPIV1Messages.Name, cfg, func(ctx context.Context) (*GETAPIV1MessagesResponse, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url.String(), nil)
+		if err != nil {
+			return nil, retry.Abort(fmt.Errorf("could not prepare request: %w", err))
+		}
+
+		req.Header.Add("Accept", "application/json")
+
+		for key, value := range request.Headers {
+			req.Header.Set(key, value)
+		}
+
+		if err := deadline.Inject(ctx, req.Header, cfg.Deadline.Config()); err != nil {
+			return nil, retry.Abort(err)
+		}
+
+		resp, err := cl.handler(operationGETAPIV1Messages, req)
+		if err != nil {
+			return nil, fmt.Errorf("could not do http request: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("could not read response with status %d: %w", resp.StatusCode, err)
+			}
+
+			err = fmt.Errorf("got response with status %d: %q", resp.StatusCode, string(raw))
+
+			// Client errors won't go away on retry.
+			if resp.StatusCode < http.StatusInternalServerError {
+				return nil, retry.Abort(err)
+			}
+
+			return nil, err
+		}
+
+		response := &GETAPIV1MessagesResponse{
+			Headers: resp.Header,
+		}
+
+		if resp.StatusCode == 200 {
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("could not read response [%d]: %w", resp.StatusCode, err)
+			}
+
+			if cfg.Validation.Config().StrictResponse {
+				if err := cl.checkResponse(ctx, operationGETAPIV1Messages, "MessageList", raw); err != nil {
+					return nil, retry.Abort(fmt.Errorf("invalid response [%d]: %w", resp.StatusCode, err))
+				}
+			}
+
+			var body MessageList
+			if err := json.Unmarshal(raw, &body); err != nil {
+				return nil, retry.Abort(fmt.Errorf("could not decode response [%d]: %w", resp.StatusCode, err))
+			}
+
+			response.Body200 = &body
+
+			return response, nil
+		}
+
+		return nil, retry.Abort(fmt.Errorf("unhandled response code: %d", resp.StatusCode))
+	})
+
+	span.End(err)
+
+	return result, err
+}
+
+type POSTAPIV1MessagesRequest struct {
+	// Headers is a list of additional headers.
+	Headers map[string]string
+
+	// Body is a request body.
+	Body *Message
+}
+
+// Validate checks parameters and body against schema constraints. Violations
+// are reported as *validate.Error with paths like "/query/limit" and
+// "/body/name". Required strings, numbers and booleans are plain values, so
+// missing ones are not told apart from "", 0 and false.
+func (r *POSTAPIV1MessagesRequest) Validate() error {
+	v := &validate.Validator{}
+	v.Required("/body", r.Body != nil)
+	if r.Body != nil {
+		r.Body.validate(v, "/body")
+	}
+
+	return v.Err()
+}
+
+type POSTAPIV1MessagesResponse struct {
+	Headers map[string][]string
+
+	Body201 *Message
+}
+
+//nolint:gochecknoglobals // Operation descriptor passed to middlewares.
+var operationPOSTAPIV1Messages = middleware.Operation{
+	Name:   "POSTAPIV1Messages",
+	Method: "POST",
+	Path:   "/api/v1/messages",
+	Tags:   []string{"messages"},
+}
+
+func (cl *MessageService) POSTAPIV1Messages(
+	ctx context.Context,
+	request *POSTAPIV1MessagesRequest,
+) (*POSTAPIV1MessagesResponse, error) {
+	url := cl.baseURL.JoinPath("/api/v1/messages")
+	clientCfg := cl.getConfig()
+	cfg := clientCfg.Default.Merge(clientCfg.POSTAPIV1Messages)
+
+	if !cfg.Validation.Config().SkipRequest {
+		if err := request.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+	}
+
+	ctx, cancel := cfg.Context(ctx)
+	defer cancel()
+
+	ctx = middleware.WithOperation(ctx, operationPOSTAPIV1Messages)
+
+	body := &bytes.Buffer{}
+	if err := json.NewEncoder(body).Encode(&request.Body); err != nil {
+		return nil, fmt.Errorf("could not encode request body: %w", err)
+	}
+
+	// Operation span covers all attempts; HTTP exchanges are its children.
+	ctx, span := tracing.StartOperation(ctx, cl.tracer, operationPOSTAPIV1Messages)
+
+	result, err := policy.Do(ctx, cl.policies, operationPOSTAPIV1Messages.Name, cfg, func(ctx context.Context) (*POSTAPIV1MessagesResponse, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", url.String(), bytes.NewReader(body.Bytes()))
+		if err != nil {
+			return nil, retry.Abort(fmt.Errorf("could not prepare request: %w", err))
+		}
+
+		req.Header.Add("Content-Type", "application/json")
+
+		req.Header.Add("Accept", "application/json")
+
+		for key, value := range request.Headers {
+			req.Header.Set(key, value)
+		}
+
+		if err := deadline.Inject(ctx, req.Header, cfg.Deadline.Config()); err != nil {
+			return nil, retry.Abort(err)
+		}
+
+		resp, err := cl.handler(operationPOSTAPIV1Messages, req)
+		if err != nil {
+			return nil, fmt.Errorf("could not do http request: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("could not read response with status %d: %w", resp.StatusCode, err)
+			}
+
+			err = fmt.Errorf("got response with status %d: %q", resp.StatusCode, string(raw))
+
+			// Client errors won't go away on retry.
+			if resp.StatusCode < http.StatusInternalServerError {
+				return nil, retry.Abort(err)
+			}
+
+			return nil, err
+		}
+
+		response := &POSTAPIV1MessagesResponse{
+			Headers: resp.Header,
+		}
+
+		if resp.StatusCode == 201 {
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("could not read response [%d]: %w", resp.StatusCode, err)
+			}
+
+			if cfg.Validation.Config().StrictResponse {
+				if err := cl.checkResponse(ctx, operationPOSTAPIV1Messages, "Message", raw); err != nil {
+					return nil, retry.Abort(fmt.Errorf("invalid response [%d]: %w", resp.StatusCode, err))
+				}
+			}
+
+			var body Message
+			if err := json.Unmarshal(raw, &body); err != nil {
+				return nil, retry.Abort(fmt.Errorf("could not decode response [%d]: %w", resp.StatusCode, err))
+			}
+
+			response.Body201 = &body
+
+			return response, nil
+		}
+
+		return nil, retry.Abort(fmt.Errorf("unhandled response code: %d", resp.StatusCode))
+	})
+
+	span.End(err)
+
+	return result, err
+}
+
+var _ MessageServiceAPI = (*FakeMessageService)(nil)
+
+// ErrNotImplemented is returned by FakeMessageService methods without function
+// set.
+var ErrNotImplemented = errors.New("not implemented")
+
+// FakeMessageServiceCall is a call recorded by FakeMessageService.
+type FakeMessageServiceCall struct {
+	// Operation is a canonical operation name, e.g. "GETAPIV1MessagesMessageID".
+	Operation string
+	// Request is a pointer to operation request, e.g. *GETAPIV1MessagesMessageIDRequest.
+	Request any
+}
+
+// FakeMessageService is an in-memory MessageServiceAPI. Methods call
+// corresponding functions or return ErrNotImplemented if they are not set.
+// All calls are recorded. It's safe for concurrent use as long as functions
+// are not changed during calls.
+type FakeMessageService struct {
+	GETAPIV1MessagesMessageIDFunc func(ctx context.Context, request *GETAPIV1MessagesMessageIDRequest) (*GETAPIV1MessagesMessageIDResponse, error)
+	GETAPIV1MessagesFunc          func(ctx context.Context, request *GETAPIV1MessagesRequest) (*GETAPIV1MessagesResponse, error)
+	POSTAPIV1MessagesFunc         func(ctx context.Context, request *POSTAPIV1MessagesRequest) (*POSTAPIV1MessagesResponse, error)
+
+	mu    sync.Mutex
+	calls []FakeMessageServiceCall
+}
+
+// Calls returns recorded calls in order.
+func (f *FakeMessageService) Calls() []FakeMessageServiceCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]FakeMessageServiceCall(nil), f.calls...)
+}
+
+// Reset drops recorded calls.
+func (f *FakeMessageService) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = nil
+}
+
+func (f *FakeMessageService) record(operation string, request any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, FakeMessageServiceCall{Operation: operation, Request: request})
+}
+
+// GETAPIV1MessagesMessageID implements MessageServiceAPI.
+func (f *FakeMessageService) GETAPIV1MessagesMessageID(
+	ctx context.Context,
+	request *GETAPIV1MessagesMessageIDRequest,
+) (*GETAPIV1MessagesMessageIDResponse, error) {
+	f.record("GETAPIV1MessagesMessageID", request)
+
+	if f.GETAPIV1MessagesMessageIDFunc == nil {
+		return nil, fmt.Errorf("%w: GETAPIV1MessagesMessageID", ErrNotImplemented)
+	}
+
+	return f.GETAPIV1MessagesMessageIDFunc(ctx, request)
+}
+
+// GETAPIV1MessagesMessageIDCalls returns requests of recorded GETAPIV1MessagesMessageID calls.
+func (f *FakeMessageService) GETAPIV1MessagesMessageIDCalls() []*GETAPIV1MessagesMessageIDRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var requests []*GETAPIV1MessagesMessageIDRequest
+
+	for _, call := range f.calls {
+		if call.Operation == "GETAPIV1MessagesMessageID" {
+			requests = append(requests, call.Request.(*GETAPIV1MessagesMessageIDRequest)) //nolint:forcetypeassert // Recorded by method.
+		}
+	}
+
+	return requests
+}
+
+// GETAPIV1Messages implements MessageServiceAPI.
+func (f *FakeMessageService) GETAPIV1Messages(
+	ctx context.Context,
+	request *GETAPIV1MessagesRequest,
+) (*GETAPIV1MessagesResponse, error) {
+	f.record("GETAPIV1Messages", request)
+
+	if f.GETAPIV1MessagesFunc == nil {
+		return nil, fmt.Errorf("%w: GETAPIV1Messages", ErrNotImplemented)
+	}
+
+	return f.GETAPIV1MessagesFunc(ctx, request)
+}
+
+// GETAPIV1MessagesCalls returns requests of recorded GETAPIV1Messages calls.
+func (f *FakeMessageService) GETAPIV1MessagesCalls() []*GETAPIV1MessagesRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var requests []*GETAPIV1MessagesRequest
+
+	for _, call := range f.calls {
+		if call.Operation == "GETAPIV1Messages" {
+			requests = append(requests, call.Request.(*GETAPIV1MessagesRequest)) //nolint:forcetypeassert // Recorded by method.
+		}
+	}
+
+	return requests
+}
+
+// POSTAPIV1Messages implements MessageServiceAPI.
+func (f *FakeMessageService) POSTAPIV1Messages(
+	ctx context.Context,
+	request *POSTAPIV1MessagesRequest,
+) (*POSTAPIV1MessagesResponse, error) {
+	f.record("POSTAPIV1Messages", request)
+
+	if f.POSTAPIV1MessagesFunc == nil {
+		return nil, fmt.Errorf("%w: POSTAPIV1Messages", ErrNotImplemented)
+	}
+
+	return f.POSTAPIV1MessagesFunc(ctx, request)
+}
+
+// POSTAPIV1MessagesCalls returns requests of recorded POSTAPIV1Messages calls.
+func (f *FakeMessageService) POSTAPIV1MessagesCalls() []*POSTAPIV1MessagesRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var requests []*POSTAPIV1MessagesRequest
+
+	for _, call := range f.calls {
+		if call.Operation == "POSTAPIV1Messages" {
+			requests = append(requests, call.Request.(*POSTAPIV1MessagesRequest)) //nolint:forcetypeassert // Recorded by method.
+		}
+	}
+
+	return requests
+}
+
+// ServerInterface is implemented by MessageService server. Methods return
+// response with exactly one body set; errors are written by server error
+// handler.
+type ServerInterface interface {
+	GETAPIV1MessagesMessageID(ctx context.Context, request *GETAPIV1MessagesMessageIDRequest) (*GETAPIV1MessagesMessageIDResponse, error)
+	GETAPIV1Messages(ctx context.Context, request *GETAPIV1MessagesRequest) (*GETAPIV1MessagesResponse, error)
+	POSTAPIV1Messages(ctx context.Context, request *POSTAPIV1MessagesRequest) (*POSTAPIV1MessagesResponse, error)
+}
+
+// ServerOption overrides server handler creation.
+type ServerOption func(*serverHandler)
+
+// WithServerErrorHandler overrides server.DefaultErrorHandler.
+func WithServerErrorHandler(handler server.ErrorHandler) ServerOption {
+	return func(s *serverHandler) {
+		s.errorHandler = handler
+	}
+}
+
+type serverHandler struct {
+	impl         ServerInterface
+	errorHandler server.ErrorHandler
+}
+
+// NewServerHandler returns handler routing operations to impl. Requests are
+// decoded and validated against schema constraints before impl is called.
+func NewServerHandler(impl ServerInterface, opts ...ServerOption) http.Handler {
+	s := &serverHandler{
+		impl:         impl,
+		errorHandler: server.DefaultErrorHandler,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/messages/{MessageID}", s.handleGETAPIV1MessagesMessageID)
+	mux.HandleFunc("GET /api/v1/messages", s.handleGETAPIV1Messages)
+	mux.HandleFunc("POST /api/v1/messages", s.handlePOSTAPIV1Messages)
+
+	return mux
+}
+
+func (s *serverHandler) handleGETAPIV1MessagesMessageID(w http.ResponseWriter, r *http.Request) {
+	request := &GETAPIV1MessagesMessageIDRequest{}
+
+	request.PathMessageID = r.PathValue("MessageID")
+
+	if err := request.Validate(); err != nil {
+		s.errorHandler(w, r, err)
+		return
+	}
+
+	response, err := s.impl.GETAPIV1MessagesMessageID(r.Context(), request)
+	if err == nil && response == nil {
+		err = fmt.Errorf("%w: GETAPIV1MessagesMessageID", server.ErrNoResponse)
+	}
+
+	if err != nil {
+		s.errorHandler(w, r, err)
+		return
+	}
+
+	server.WriteHeaders(w, response.Headers)
+
+	switch {
+	case response.Body200 != nil:
+		server.WriteJSON(w, 200, response.Body200)
+	case response.Body404 != nil:
+		server.WriteJSON(w, 404, response.Body404)
+	default:
+		s.errorHandler(w, r, fmt.Errorf("%w: GETAPIV1MessagesMessageID", server.ErrNoResponse))
+	}
+}
+
+func (s *serverHandler) handleGETAPIV1Messages(w http.ResponseWriter, r *http.Request) {
+	request := &GETAPIV1MessagesRequest{}
+
+	if err := request.Validate(); err != nil {
+		s.errorHandler(w, r, err)
+		return
+	}
+
+	response, err := s.impl.GETAPIV1Messages(r.Context(), request)
+	if err == nil && response == nil {
+		err = fmt.Errorf("%w: GETAPIV1Messages", server.ErrNoResponse)
+	}
+
+	if err != nil {
+		s.errorHandler(w, r, err)
+		return
+	}
+
+	server.WriteHeaders(w, response.Headers)
+
+	switch {
+	case response.Body200 != nil:
+		server.WriteJSON(w, 200, response.Body200)
+	default:
+		s.errorHandler(w, r, fmt.Errorf("%w: GETAPIV1Messages", server.ErrNoResponse))
+	}
+}
+
+func (s *serverHandler) handlePOSTAPIV1Messages(w http.ResponseWriter, r *http.Request) {
+	request := &POSTAPIV1MessagesRequest{}
+
+	body := &Message{}
+	if ok, err := server.DecodeJSON(r, body, true); err != nil {
+		s.errorHandler(w, r, err)
+		return
+	} else if ok {
+		request.Body = body
+	}
+
+	if err := request.Validate(); err != nil {
+		s.errorHandler(w, r, err)
+		return
+	}
+
+	response, err := s.impl.POSTAPIV1Messages(r.Context(), request)
+	if err == nil && response == nil {
+		err = fmt.Errorf("%w: POSTAPIV1Messages", server.ErrNoResponse)
+	}
+
+	if err != nil {
+		s.errorHandler(w, r, err)
+		return
+	}
+
+	server.WriteHeaders(w, response.Headers)
+
+	switch {
+	case response.Body201 != nil:
+		server.WriteJSON(w, 201, response.Body201)
+	default:
+		s.errorHandler(w, r, fmt.Errorf("%w: POSTAPIV1Messages", server.ErrNoResponse))
+	}
+}
+
+// NewMockHandler returns handler serving examples from the spec, so it can be
+// passed to httptest.NewServer in integration tests. Requests are decoded and
+// checked as by NewServerHandler. Operations with function set in fake call
+// it to script responses; all calls are recorded by fake.
+func NewMockHandler(fake *FakeMessageService, opts ...ServerOption) http.Handler {
+	return NewServerHandler(&mockServer{fake: fake}, opts...)
+}
+
+type mockServer struct {
+	fake *FakeMessageService
+}
+
+func (m *mockServer) GETAPIV1MessagesMessageID(
+	ctx context.Context,
+	request *GETAPIV1MessagesMessageIDRequest,
+) (*GETAPIV1MessagesMessageIDResponse, error) {
+	if m.fake.GETAPIV1MessagesMessageIDFunc != nil {
+		return m.fake.GETAPIV1MessagesMessageID(ctx, request)
+	}
+
+	m.fake.record("GETAPIV1MessagesMessageID", request)
+
+	response := &GETAPIV1MessagesMessageIDResponse{}
+	if err := json.Unmarshal([]byte(`{"author":{"name":"alice"},"id":"1","text":"hello"}`), &response.Body200); err != nil {
+		return nil, fmt.Errorf("could not decode 200 example: %w", err)
+	}
+
+	return response, nil
+}
+
+func (m *mockServer) GETAPIV1Messages(
+	ctx context.Context,
+	request *GETAPIV1MessagesRequest,
+) (*GETAPIV1MessagesResponse, error) {
+	if m.fake.GETAPIV1MessagesFunc != nil {
+		return m.fake.GETAPIV1Messages(ctx, request)
+	}
+
+	m.fake.record("GETAPIV1Messages", request)
+
+	response := &GETAPIV1MessagesResponse{}
+	if err := json.Unmarshal([]byte(`{"messages":[{"author":{"name":"string"},"id":"00000000-0000-0000-0000-000000000000","text":"synthesized"},{"author":{"name":"string"},"id":"00000000-0000-0000-0000-000000000000","text":"synthesized"}],"total":2}`), &response.Body200); err != nil {
+		return nil, fmt.Errorf("could not decode 200 example: %w", err)
+	}
+
+	return response, nil
+}
+
+func (m *mockServer) POSTAPIV1Messages(
+	ctx context.Context,
+	request *POSTAPIV1MessagesRequest,
+) (*POSTAPIV1MessagesResponse, error) {
+	if m.fake.POSTAPIV1MessagesFunc != nil {
+		return m.fake.POSTAPIV1Messages(ctx, request)
+	}
+
+	m.fake.record("POSTAPIV1Messages", request)
+
+	response := &POSTAPIV1MessagesResponse{}
+	if err := json.Unmarshal([]byte(`{"id":"2","text":"created"}`), &response.Body201); err != nil {
+		return nil, fmt.Errorf("could not decode 201 example: %w", err)
+	}
+
+	return response, nil
+}
@@ -2,7 +2,10 @@ module github.com/vitaminniy/go-lib-http
 
 go 1.22.3
 
-require github.com/pb33f/libopenapi v0.16.8
+require (
+	github.com/pb33f/libopenapi v0.16.8
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/bahlo/generic-list-go v0.2.0 // indirect
@@ -13,5 +16,4 @@ require (
 	github.com/wk8/go-ordered-map/v2 v2.1.8 // indirect
 	golang.org/x/exp v0.0.0-20240213143201-ec583247a57a // indirect
 	golang.org/x/sync v0.6.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,97 @@
+// Package hedge provides facilities to hedge slow operations.
+package hedge
+
+import (
+	"context"
+	"time"
+)
+
+// Config controls hedging behaviour.
+type Config struct {
+	// Delay is an interval after which another concurrent attempt is started
+	// if previous ones didn't complete yet.
+	Delay time.Duration `json:"delay,omitempty" yaml:"delay,omitempty"`
+	// Attempts is a maximum number of concurrent attempts including the first
+	// one. Hedging is disabled when attempts is less than 2 or delay is 0.
+	Attempts uint `json:"attempts,omitempty" yaml:"attempts,omitempty"`
+}
+
+// Enabled reports whether hedging is enabled.
+func (cfg *Config) Enabled() bool {
+	return cfg.Attempts > 1 && cfg.Delay > 0
+}
+
+type attemptKey struct{}
+
+// AttemptFromContext returns number of hedged attempt starting with 1 for the
+// first one. It returns 0 if ctx does not belong to hedged operation.
+func AttemptFromContext(ctx context.Context) uint {
+	attempt, _ := ctx.Value(attemptKey{}).(uint)
+
+	return attempt
+}
+
+type result[T any] struct {
+	value T
+	err   error
+}
+
+// Do executes operation and starts another concurrent attempt every
+// cfg.Delay until one of the attempts succeeds or cfg.Attempts are started.
+//
+// The first successful result is returned and the rest of attempts are
+// canceled. Hedging addresses latency, not errors: if all started attempts
+// fail, the last error is returned without starting new attempts.
+//
+// Attempt number is available to op with AttemptFromContext.
+//
+//nolint:varnamelen // op is a common name for passed functions.
+func Do[T any](
+	ctx context.Context,
+	cfg Config,
+	op func(context.Context) (T, error),
+) (T, error) {
+	if !cfg.Enabled() {
+		return op(context.WithValue(ctx, attemptKey{}, uint(1)))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Buffered so abandoned attempts do not leak.
+	results := make(chan result[T], cfg.Attempts)
+	launch := func(attempt uint) {
+		go func() {
+			value, err := op(context.WithValue(ctx, attemptKey{}, attempt))
+			results <- result[T]{value: value, err: err}
+		}()
+	}
+
+	var (
+		started  uint = 1
+		finished uint
+	)
+
+	launch(started)
+
+	timer := time.NewTimer(cfg.Delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if started < cfg.Attempts {
+				started++
+
+				launch(started)
+				timer.Reset(cfg.Delay)
+			}
+		case res := <-results:
+			finished++
+
+			if res.err == nil || finished == started {
+				return res.value, res.err
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package hedge
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var ErrOperationFailed = errors.New("operation failed")
+
+func TestDoDisabled(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	got, err := Do(context.Background(), Config{Attempts: 3}, func(ctx context.Context) (int, error) {
+		if attempt := AttemptFromContext(ctx); attempt != 1 {
+			t.Errorf("attempt mismatch: want %d; got %d", 1, attempt)
+		}
+
+		calls.Add(1)
+		time.Sleep(time.Millisecond * 10)
+
+		return 1, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got != 1 || calls.Load() != 1 {
+		t.Fatalf("mismatch: want 1 result and 1 call; got %d result and %d calls", got, calls.Load())
+	}
+}
+
+func TestDoFastestWins(t *testing.T) {
+	t.Parallel()
+
+	var (
+		calls    atomic.Int32
+		canceled atomic.Int32
+	)
+
+	cfg := Config{Delay: time.Millisecond * 10, Attempts: 3}
+
+	got, err := Do(context.Background(), cfg, func(ctx context.Context) (int32, error) {
+		calls.Add(1)
+
+		call := int32(AttemptFromContext(ctx))
+		if call == 1 {
+			// The first attempt hangs until it's canceled.
+			<-ctx.Done()
+			canceled.Add(1)
+
+			return 0, ctx.Err()
+		}
+
+		return call, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got != 2 {
+		t.Fatalf("mismatch: want result of attempt %d; got %d", 2, got)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for canceled.Load() != 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if canceled.Load() != 1 {
+		t.Fatal("slow attempt was not canceled")
+	}
+}
+
+func TestDoFailsWithoutStartingNewAttempts(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	cfg := Config{Delay: time.Second, Attempts: 3}
+
+	_, err := Do(context.Background(), cfg, func(context.Context) (int, error) {
+		calls.Add(1)
+
+		return 0, ErrOperationFailed
+	})
+	if !errors.Is(err, ErrOperationFailed) {
+		t.Fatalf("error mismatch: want %v; got %v", ErrOperationFailed, err)
+	}
+
+	if calls.Load() != 1 {
+		t.Fatalf("calls mismatch: want %d; got %d", 1, calls.Load())
+	}
+}
+
+func TestDoAllAttemptsFail(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	cfg := Config{Delay: time.Millisecond * 5, Attempts: 3}
+
+	_, err := Do(context.Background(), cfg, func(context.Context) (int, error) {
+		calls.Add(1)
+		time.Sleep(time.Millisecond * 50)
+
+		return 0, ErrOperationFailed
+	})
+	if !errors.Is(err, ErrOperationFailed) {
+		t.Fatalf("error mismatch: want %v; got %v", ErrOperationFailed, err)
+	}
+
+	if calls.Load() != 3 {
+		t.Fatalf("calls mismatch: want %d; got %d", 3, calls.Load())
+	}
+}
@@ -0,0 +1,109 @@
+// Package limiter provides token bucket rate limiter.
+package limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Config controls rate limiter behaviour.
+type Config struct {
+	// Rate is a number of calls allowed per second. Limiter is disabled when
+	// set to 0.
+	Rate float64 `json:"rate,omitempty" yaml:"rate,omitempty"`
+	// Burst is a number of calls allowed at once. Defaults to 1.
+	Burst uint `json:"burst,omitempty" yaml:"burst,omitempty"`
+}
+
+// Enabled reports whether limiter is enabled.
+func (cfg *Config) Enabled() bool {
+	return cfg.Rate > 0
+}
+
+// burst returns bucket size.
+func (cfg *Config) burst() float64 {
+	if cfg.Burst == 0 {
+		return 1
+	}
+
+	return float64(cfg.Burst)
+}
+
+// New creates new rate limiter. Nil is returned when limiter is disabled; nil
+// limiter never blocks.
+func New(cfg Config) *Limiter {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	return &Limiter{
+		cfg:    cfg,
+		now:    time.Now,
+		tokens: cfg.burst(),
+		last:   time.Now(),
+	}
+}
+
+// Limiter is a token bucket rate limiter.
+type Limiter struct {
+	cfg Config
+	now func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// Wait blocks until call is allowed or ctx is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	wait := l.reserve()
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err() //nolint:wrapcheck // We want to have actual context error.
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes a token and returns time to wait until it's available.
+func (l *Limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	l.tokens += now.Sub(l.last).Seconds() * l.cfg.Rate
+	if burst := l.cfg.burst(); l.tokens > burst {
+		l.tokens = burst
+	}
+
+	l.last = now
+	l.tokens--
+
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.cfg.Rate * float64(time.Second))
+}
+
+// cancel returns reserved token.
+func (l *Limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens++
+}
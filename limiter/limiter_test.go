@@ -0,0 +1,74 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDisabledLimiter(t *testing.T) {
+	t.Parallel()
+
+	l := New(Config{})
+	if l != nil {
+		t.Fatal("expected disabled limiter to be nil")
+	}
+
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLimiterReserve(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+
+	l := New(Config{Rate: 10, Burst: 2})
+	l.now = func() time.Time { return now }
+	l.last = now
+
+	cases := []struct {
+		advance time.Duration
+		want    time.Duration
+	}{
+		{advance: 0, want: 0},
+		{advance: 0, want: 0},
+		{advance: 0, want: time.Millisecond * 100},
+		{advance: 0, want: time.Millisecond * 200},
+		// Three tokens refill and pay off the debt of two reservations.
+		{advance: time.Millisecond * 300, want: 0},
+		{advance: time.Second * 10, want: 0},
+		{advance: 0, want: 0},
+		{advance: 0, want: time.Millisecond * 100},
+	}
+
+	for i, c := range cases {
+		now = now.Add(c.advance)
+
+		got := l.reserve()
+
+		// Allow rounding errors of float arithmetic.
+		if diff := got - c.want; diff > time.Microsecond || diff < -time.Microsecond {
+			t.Fatalf("reservation %d mismatch: want %v; got %v", i, c.want, got)
+		}
+	}
+}
+
+func TestLimiterWaitContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	l := New(Config{Rate: 0.001})
+
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error mismatch: want %v; got %v", context.DeadlineExceeded, err)
+	}
+}
@@ -0,0 +1,230 @@
+// Package logging provides log/slog based logging of generated client
+// requests.
+package logging
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/vitaminniy/go-lib-http/middleware"
+	"github.com/vitaminniy/go-lib-http/policy"
+)
+
+// Redacted replaces sensitive values.
+const Redacted = "REDACTED"
+
+// DefaultMaxBodySize is a default limit of logged body size.
+const DefaultMaxBodySize = 4 << 10
+
+// DefaultRedactedHeaders are headers which are always redacted.
+//
+//nolint:gochecknoglobals // Constant list.
+var DefaultRedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
+type options struct {
+	level       slog.Level
+	headers     bool
+	bodies      bool
+	maxBodySize int
+	sampleRate  float64
+	redacted    redactions
+	random      func() float64
+}
+
+// Option configures Middleware.
+type Option func(*options)
+
+// WithLevel sets level of records about successful requests. Failed requests
+// are logged at least at slog.LevelWarn. Default is slog.LevelInfo.
+func WithLevel(level slog.Level) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// WithHeaders enables logging of request and response headers.
+func WithHeaders() Option {
+	return func(o *options) {
+		o.headers = true
+	}
+}
+
+// WithBodies enables logging of JSON request and response bodies up to
+// maxSize bytes; zero maxSize means DefaultMaxBodySize.
+func WithBodies(maxSize int) Option {
+	return func(o *options) {
+		o.bodies = true
+		o.maxBodySize = maxSize
+
+		if o.maxBodySize <= 0 {
+			o.maxBodySize = DefaultMaxBodySize
+		}
+	}
+}
+
+// WithRedactedHeaders adds headers to be redacted in addition to
+// DefaultRedactedHeaders and headers marked sensitive in the spec.
+func WithRedactedHeaders(headers ...string) Option {
+	return func(o *options) {
+		o.redacted.headers = append(o.redacted.headers, headers...)
+	}
+}
+
+// WithRedactedFields adds JSON body fields to be redacted in addition to
+// fields marked sensitive in the spec.
+func WithRedactedFields(fields ...string) Option {
+	return func(o *options) {
+		o.redacted.fields = append(o.redacted.fields, fields...)
+	}
+}
+
+// WithSampleRate sets fraction of successful requests to log; rate must be in
+// (0, 1] range. Failed requests are always logged. Default is 1.
+func WithSampleRate(rate float64) Option {
+	return func(o *options) {
+		o.sampleRate = rate
+	}
+}
+
+// Middleware logs start and finish of every HTTP exchange. Nil logger disables
+// logging.
+func Middleware(logger *slog.Logger, opts ...Option) middleware.Middleware {
+	o := options{
+		level:      slog.LevelInfo,
+		sampleRate: 1,
+		redacted:   redactions{headers: slices.Clone(DefaultRedactedHeaders)},
+		random:     rand.Float64,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(next middleware.Handler) middleware.Handler {
+		if logger == nil {
+			return next
+		}
+
+		return func(op middleware.Operation, req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			sampled := o.sampleRate >= 1 || o.random() < o.sampleRate
+			redacted := o.redacted.with(op)
+
+			attrs := []slog.Attr{
+				slog.String("operation", op.Name),
+				slog.String("method", op.Method),
+				slog.String("route", op.Path),
+				slog.Uint64("attempt", uint64(policy.AttemptFromContext(ctx))),
+			}
+
+			if sampled && logger.Enabled(ctx, o.level) {
+				logger.LogAttrs(ctx, o.level, "http request started", append(slices.Clip(attrs), o.requestAttrs(req, redacted)...)...)
+			}
+
+			start := time.Now()
+
+			resp, err := next(op, req)
+
+			attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+
+			level := o.level
+			if failed(resp, err) {
+				level = max(level, slog.LevelWarn)
+			} else if !sampled {
+				return resp, err
+			}
+
+			if !logger.Enabled(ctx, level) {
+				return resp, err
+			}
+
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+
+			if resp != nil {
+				attrs = append(attrs, slog.Int("status", resp.StatusCode))
+				attrs = append(attrs, o.responseAttrs(resp, redacted)...)
+			}
+
+			logger.LogAttrs(ctx, level, "http request finished", attrs...)
+
+			return resp, err
+		}
+	}
+}
+
+func failed(resp *http.Response, err error) bool {
+	return err != nil || resp == nil || resp.StatusCode >= http.StatusInternalServerError
+}
+
+func (o *options) requestAttrs(req *http.Request, redacted redactions) []slog.Attr {
+	var attrs []slog.Attr
+
+	if o.headers {
+		attrs = append(attrs, slog.Any("request_headers", redacted.header(req.Header)))
+	}
+
+	if o.bodies && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err == nil {
+			attrs = append(attrs, slog.String("request_body", o.body(body, redacted)))
+			_ = body.Close()
+		}
+	}
+
+	return attrs
+}
+
+func (o *options) responseAttrs(resp *http.Response, redacted redactions) []slog.Attr {
+	var attrs []slog.Attr
+
+	if o.headers {
+		attrs = append(attrs, slog.Any("response_headers", redacted.header(resp.Header)))
+	}
+
+	if o.bodies && resp.Body != nil {
+		// Body is read up to the limit and glued back, so the caller reads
+		// it as a whole.
+		head, err := io.ReadAll(io.LimitReader(resp.Body, int64(o.maxBodySize)+1))
+		resp.Body = readCloser{
+			Reader: io.MultiReader(bytes.NewReader(head), resp.Body),
+			Closer: resp.Body,
+		}
+
+		if err == nil {
+			attrs = append(attrs, slog.String("response_body", o.body(bytes.NewReader(head), redacted)))
+		}
+	}
+
+	return attrs
+}
+
+func (o *options) body(body io.Reader, redacted redactions) string {
+	raw, err := io.ReadAll(io.LimitReader(body, int64(o.maxBodySize)+1))
+	if err != nil {
+		return ""
+	}
+
+	if len(raw) > o.maxBodySize {
+		return "[body too large]"
+	}
+
+	return redacted.body(raw)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
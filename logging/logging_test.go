@@ -0,0 +1,179 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vitaminniy/go-lib-http/middleware"
+)
+
+var ErrOperationFailed = errors.New("operation failed")
+
+type record map[string]any
+
+func newLogger(t *testing.T) (*slog.Logger, func() []record) {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	return logger, func() []record {
+		var records []record
+
+		decoder := json.NewDecoder(&buf)
+		for decoder.More() {
+			var r record
+			if err := decoder.Decode(&r); err != nil {
+				t.Fatalf("could not decode record: %v", err)
+			}
+
+			records = append(records, r)
+		}
+
+		return records
+	}
+}
+
+//nolint:gochecknoglobals // Test fixture.
+var operation = middleware.Operation{
+	Name:             "POSTApiV1Login",
+	Method:           http.MethodPost,
+	Path:             "/api/v1/login",
+	SensitiveHeaders: []string{"X-Session"},
+	SensitiveFields:  []string{"password"},
+}
+
+func TestMiddlewareRedacts(t *testing.T) {
+	t.Parallel()
+
+	logger, records := newLogger(t)
+
+	handler := middleware.Chain(func(_ middleware.Operation, _ *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Set-Cookie": {"session=secret"}},
+			Body:       io.NopCloser(strings.NewReader(`{"user":{"name":"max","password":"secret"}}`)),
+		}, nil
+	}, Middleware(logger, WithHeaders(), WithBodies(0), WithRedactedFields("token")))
+
+	body := `{"login":"max","password":"secret","tokens":[{"token":"secret"}]}`
+
+	req, err := http.NewRequest(operation.Method, "http://localhost"+operation.Path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("could not prepare request: %v", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Session", "secret")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := handler(operation, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("could not read body: %v", err)
+	}
+
+	if !strings.Contains(string(raw), `"password":"secret"`) {
+		t.Fatalf("response body was modified: %s", raw)
+	}
+
+	logged := records()
+	if len(logged) != 2 {
+		t.Fatalf("records mismatch: want %d; got %d", 2, len(logged))
+	}
+
+	all, _ := json.Marshal(logged)
+	if strings.Contains(string(all), "secret") {
+		t.Fatalf("secret is logged: %s", all)
+	}
+
+	if got := logged[0]["request_body"]; got != `{"login":"max","password":"REDACTED","tokens":[{"token":"REDACTED"}]}` {
+		t.Fatalf("request body mismatch: got %v", got)
+	}
+
+	if got := logged[1]["status"]; got != float64(http.StatusOK) {
+		t.Fatalf("status mismatch: want %d; got %v", http.StatusOK, got)
+	}
+
+	if got := logged[1]["route"]; got != operation.Path {
+		t.Fatalf("route mismatch: want %q; got %v", operation.Path, got)
+	}
+}
+
+func TestMiddlewareSampling(t *testing.T) {
+	t.Parallel()
+
+	logger, records := newLogger(t)
+
+	var fail bool
+
+	mw := Middleware(logger, WithSampleRate(0.5), func(o *options) {
+		o.random = func() float64 { return 0.9 }
+	})
+
+	handler := mw(func(_ middleware.Operation, _ *http.Request) (*http.Response, error) {
+		if fail {
+			return nil, ErrOperationFailed
+		}
+
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	req := httptest.NewRequest(operation.Method, operation.Path, nil)
+
+	if _, err := handler(operation, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if logged := records(); len(logged) != 0 {
+		t.Fatalf("unsampled request is logged: %v", logged)
+	}
+
+	// Failures are always logged.
+	fail = true
+
+	if _, err := handler(operation, req); !errors.Is(err, ErrOperationFailed) {
+		t.Fatalf("error mismatch: want %v; got %v", ErrOperationFailed, err)
+	}
+
+	logged := records()
+	if len(logged) != 1 {
+		t.Fatalf("records mismatch: want %d; got %d", 1, len(logged))
+	}
+
+	if got := logged[0]["level"]; got != slog.LevelWarn.String() {
+		t.Fatalf("level mismatch: want %v; got %v", slog.LevelWarn, got)
+	}
+}
+
+func TestMiddlewareNilLogger(t *testing.T) {
+	t.Parallel()
+
+	var called bool
+
+	handler := Middleware(nil)(func(middleware.Operation, *http.Request) (*http.Response, error) {
+		called = true
+
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	if _, err := handler(operation, httptest.NewRequest(http.MethodGet, "/", nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !called {
+		t.Fatal("handler was not called")
+	}
+}
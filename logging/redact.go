@@ -0,0 +1,104 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/vitaminniy/go-lib-http/middleware"
+)
+
+type redactions struct {
+	headers []string
+	fields  []string
+}
+
+func (r redactions) with(op middleware.Operation) redactions {
+	if len(op.SensitiveHeaders) == 0 && len(op.SensitiveFields) == 0 {
+		return r
+	}
+
+	return redactions{
+		headers: append(append([]string(nil), r.headers...), op.SensitiveHeaders...),
+		fields:  append(append([]string(nil), r.fields...), op.SensitiveFields...),
+	}
+}
+
+func (r redactions) header(header http.Header) map[string][]string {
+	result := make(map[string][]string, len(header))
+
+	for key, values := range header {
+		if r.sensitiveHeader(key) {
+			result[key] = []string{Redacted}
+			continue
+		}
+
+		result[key] = values
+	}
+
+	return result
+}
+
+func (r redactions) sensitiveHeader(key string) bool {
+	for _, header := range r.headers {
+		if strings.EqualFold(header, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// body returns JSON body with sensitive fields redacted at any depth. Non-JSON
+// bodies are not logged as they can't be redacted.
+func (r redactions) body(raw []byte) string {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return ""
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return "[non-JSON body]"
+	}
+
+	redacted, err := json.Marshal(r.value(value))
+	if err != nil {
+		return "[non-JSON body]"
+	}
+
+	return string(redacted)
+}
+
+func (r redactions) value(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, field := range value {
+			if r.sensitiveField(key) {
+				value[key] = Redacted
+				continue
+			}
+
+			value[key] = r.value(field)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = r.value(item)
+		}
+	}
+
+	return value
+}
+
+func (r redactions) sensitiveField(key string) bool {
+	for _, field := range r.fields {
+		if field == key {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import "sync"
+
+// Memory is an in-memory Recorder, mostly useful for tests.
+type Memory struct {
+	mu           sync.Mutex
+	counters     map[string]map[Labels]uint64
+	observations map[string]map[Labels][]float64
+}
+
+// NewMemory creates new in-memory recorder.
+func NewMemory() *Memory {
+	return &Memory{
+		counters:     make(map[string]map[Labels]uint64),
+		observations: make(map[string]map[Labels][]float64),
+	}
+}
+
+// IncCounter implements Recorder.
+func (m *Memory) IncCounter(name string, labels Labels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.counters[name] == nil {
+		m.counters[name] = make(map[Labels]uint64)
+	}
+
+	m.counters[name][labels]++
+}
+
+// Observe implements Recorder.
+func (m *Memory) Observe(name string, labels Labels, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.observations[name] == nil {
+		m.observations[name] = make(map[Labels][]float64)
+	}
+
+	m.observations[name][labels] = append(m.observations[name][labels], value)
+}
+
+// Counter returns counter value.
+func (m *Memory) Counter(name string, labels Labels) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.counters[name][labels]
+}
+
+// Counters returns copy of all counter values of metric.
+func (m *Memory) Counters(name string) map[Labels]uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counters := make(map[Labels]uint64, len(m.counters[name]))
+	for labels, value := range m.counters[name] {
+		counters[labels] = value
+	}
+
+	return counters
+}
+
+// Observations returns copy of observed histogram values.
+func (m *Memory) Observations(name string, labels Labels) []float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]float64(nil), m.observations[name][labels]...)
+}
@@ -0,0 +1,149 @@
+// Package metrics provides dependency-free instrumentation of generated
+// clients.
+//
+// Generated clients report metrics to Recorder, which can be adapted to any
+// metrics library, e.g. Prometheus or OpenTelemetry.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vitaminniy/go-lib-http/breaker"
+	"github.com/vitaminniy/go-lib-http/middleware"
+	"github.com/vitaminniy/go-lib-http/policy"
+)
+
+// Metric names reported by generated clients.
+const (
+	// RequestsTotal counts HTTP exchanges, including retries and hedged
+	// requests.
+	RequestsTotal = "http_client_requests_total"
+	// RequestDuration observes duration of HTTP exchanges in seconds.
+	RequestDuration = "http_client_request_duration_seconds"
+	// CallsTotal counts calls of generated methods.
+	CallsTotal = "http_client_calls_total"
+	// CallDuration observes duration of generated method calls in seconds,
+	// including retries.
+	CallDuration = "http_client_call_duration_seconds"
+	// RetriesTotal counts retry attempts.
+	RetriesTotal = "http_client_retries_total"
+	// HedgesTotal counts hedged requests.
+	HedgesTotal = "http_client_hedges_total"
+	// BreakerRejectionsTotal counts calls rejected by open circuit breaker.
+	BreakerRejectionsTotal = "http_client_breaker_rejections_total"
+)
+
+// Error kinds.
+const (
+	ErrorKindNone        = ""
+	ErrorKindTimeout     = "timeout"
+	ErrorKindCanceled    = "canceled"
+	ErrorKindBreakerOpen = "breaker_open"
+	ErrorKindOther       = "error"
+)
+
+// Labels describe reported value.
+type Labels struct {
+	// Operation is a canonical operation name, e.g. "GETAPIV1Messages".
+	Operation string
+	// StatusClass is a response status code class, e.g. "2xx"; empty if
+	// there is no response.
+	StatusClass string
+	// Attempt is a retry attempt number starting with 1.
+	Attempt uint
+	// ErrorKind is one of ErrorKind* constants; empty on success.
+	ErrorKind string
+}
+
+// Recorder receives metrics. Implementations must be safe for concurrent use.
+type Recorder interface {
+	// IncCounter increments counter.
+	IncCounter(name string, labels Labels)
+	// Observe adds value to histogram.
+	Observe(name string, labels Labels, value float64)
+}
+
+// StatusClass returns status code class, e.g. "2xx".
+func StatusClass(code int) string {
+	if code < 100 || code > 599 {
+		return "unknown"
+	}
+
+	return strconv.Itoa(code/100) + "xx" //nolint:gomnd // Status code class.
+}
+
+// ErrorKind classifies err.
+func ErrorKind(err error) string {
+	switch {
+	case err == nil:
+		return ErrorKindNone
+	case errors.Is(err, breaker.ErrOpen):
+		return ErrorKindBreakerOpen
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorKindTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorKindCanceled
+	default:
+		return ErrorKindOther
+	}
+}
+
+// Middleware reports every HTTP exchange to recorder. Nil recorder disables
+// reporting.
+func Middleware(recorder Recorder) middleware.Middleware {
+	return func(next middleware.Handler) middleware.Handler {
+		if recorder == nil {
+			return next
+		}
+
+		return func(op middleware.Operation, req *http.Request) (*http.Response, error) {
+			start := time.Now()
+
+			resp, err := next(op, req)
+
+			labels := Labels{
+				Operation: op.Name,
+				Attempt:   policy.AttemptFromContext(req.Context()),
+				ErrorKind: ErrorKind(err),
+			}
+
+			if resp != nil {
+				labels.StatusClass = StatusClass(resp.StatusCode)
+			}
+
+			recorder.IncCounter(RequestsTotal, labels)
+			recorder.Observe(RequestDuration, labels, time.Since(start).Seconds())
+
+			return resp, err
+		}
+	}
+}
+
+// Observer reports policy events to recorder. It returns nil for nil recorder.
+func Observer(recorder Recorder) policy.Observer {
+	if recorder == nil {
+		return nil
+	}
+
+	return func(event policy.Event) {
+		labels := Labels{Operation: event.Operation, Attempt: event.Attempt}
+
+		switch event.Kind {
+		case policy.EventRetry:
+			recorder.IncCounter(RetriesTotal, labels)
+		case policy.EventHedge:
+			recorder.IncCounter(HedgesTotal, labels)
+		case policy.EventBreakerRejected:
+			labels.ErrorKind = ErrorKindBreakerOpen
+			recorder.IncCounter(BreakerRejectionsTotal, labels)
+		case policy.EventDone:
+			labels.ErrorKind = ErrorKind(event.Err)
+			recorder.IncCounter(CallsTotal, labels)
+			recorder.Observe(CallDuration, labels, event.Duration.Seconds())
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vitaminniy/go-lib-http/breaker"
+	"github.com/vitaminniy/go-lib-http/config"
+	"github.com/vitaminniy/go-lib-http/middleware"
+	"github.com/vitaminniy/go-lib-http/policy"
+)
+
+var ErrOperationFailed = errors.New("operation failed")
+
+func TestErrorKind(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{err: nil, want: ErrorKindNone},
+		{err: fmt.Errorf("call: %w", breaker.ErrOpen), want: ErrorKindBreakerOpen},
+		{err: fmt.Errorf("call: %w", context.DeadlineExceeded), want: ErrorKindTimeout},
+		{err: context.Canceled, want: ErrorKindCanceled},
+		{err: ErrOperationFailed, want: ErrorKindOther},
+	}
+
+	for _, c := range cases {
+		if got := ErrorKind(c.err); got != c.want {
+			t.Fatalf("kind mismatch for %v: want %q; got %q", c.err, c.want, got)
+		}
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	t.Parallel()
+
+	cases := map[int]string{
+		http.StatusOK:                 "2xx",
+		http.StatusNotFound:           "4xx",
+		http.StatusServiceUnavailable: "5xx",
+		0:                             "unknown",
+	}
+
+	for code, want := range cases {
+		if got := StatusClass(code); got != want {
+			t.Fatalf("class mismatch for %d: want %q; got %q", code, want, got)
+		}
+	}
+}
+
+func TestMiddlewareNilRecorder(t *testing.T) {
+	t.Parallel()
+
+	var called bool
+
+	handler := middleware.Chain(func(middleware.Operation, *http.Request) (*http.Response, error) {
+		called = true
+
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}, Middleware(nil))
+
+	if _, err := handler(middleware.Operation{}, httptest.NewRequest(http.MethodGet, "/", nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !called {
+		t.Fatal("handler was not called")
+	}
+
+	if Observer(nil) != nil {
+		t.Fatal("unexpected observer for nil recorder")
+	}
+}
+
+func TestRecordsCall(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewMemory()
+
+	exec := policy.NewExecutor(policy.WithObserver(Observer(recorder)))
+	handler := middleware.Chain(func(_ middleware.Operation, req *http.Request) (*http.Response, error) {
+		if policy.AttemptFromContext(req.Context()) == 1 {
+			return &http.Response{StatusCode: http.StatusServiceUnavailable}, nil
+		}
+
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}, Middleware(recorder))
+
+	op := middleware.Operation{Name: "GETApiV1Messages", Method: http.MethodGet, Path: "/api/v1/messages"}
+	qos := config.QOS{Retry: config.Retry{Attempts: config.Ptr[uint](3)}}
+
+	code, err := policy.Do(context.Background(), exec, op.Name, qos, func(ctx context.Context) (int, error) {
+		req := httptest.NewRequest(op.Method, op.Path, nil).WithContext(ctx)
+
+		resp, err := handler(op, req)
+		if err != nil {
+			return 0, err
+		}
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return 0, ErrOperationFailed
+		}
+
+		return resp.StatusCode, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if code != http.StatusOK {
+		t.Fatalf("code mismatch: want %d; got %d", http.StatusOK, code)
+	}
+
+	wantRequests := map[Labels]uint64{
+		{Operation: op.Name, StatusClass: "5xx", Attempt: 1}: 1,
+		{Operation: op.Name, StatusClass: "2xx", Attempt: 2}: 1,
+	}
+
+	if got := recorder.Counters(RequestsTotal); !reflect.DeepEqual(wantRequests, got) {
+		t.Fatalf("requests mismatch: want %v; got %v", wantRequests, got)
+	}
+
+	if got := recorder.Counter(RetriesTotal, Labels{Operation: op.Name, Attempt: 2}); got != 1 {
+		t.Fatalf("retries mismatch: want %d; got %d", 1, got)
+	}
+
+	callLabels := Labels{Operation: op.Name, Attempt: 2}
+
+	if got := recorder.Counter(CallsTotal, callLabels); got != 1 {
+		t.Fatalf("calls mismatch: want %d; got %d", 1, got)
+	}
+
+	if got := recorder.Observations(CallDuration, callLabels); len(got) != 1 {
+		t.Fatalf("call durations mismatch: want %d; got %v", 1, got)
+	}
+}
+
+func TestRecordsBreakerRejection(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewMemory()
+
+	exec := policy.NewExecutor(policy.WithObserver(Observer(recorder)))
+	qos := config.QOS{Breaker: config.Breaker{Failures: config.Ptr[uint](1), Cooldown: config.Ptr(time.Hour)}}
+
+	op := func(context.Context) (int, error) {
+		return 0, ErrOperationFailed
+	}
+
+	_, _ = policy.Do(context.Background(), exec, "op", qos, op)
+
+	_, err := policy.Do(context.Background(), exec, "op", qos, op)
+	if !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("error mismatch: want %v; got %v", breaker.ErrOpen, err)
+	}
+
+	labels := Labels{Operation: "op", Attempt: 1, ErrorKind: ErrorKindBreakerOpen}
+
+	if got := recorder.Counter(BreakerRejectionsTotal, labels); got != 1 {
+		t.Fatalf("rejections mismatch: want %d; got %d", 1, got)
+	}
+
+	if got := recorder.Counter(CallsTotal, labels); got != 1 {
+		t.Fatalf("calls mismatch: want %d; got %d", 1, got)
+	}
+}
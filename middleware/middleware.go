@@ -0,0 +1,60 @@
+// Package middleware provides operation-aware interceptors for generated
+// clients.
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+// Operation describes generated client operation.
+type Operation struct {
+	// Name is a canonical operation name, e.g. "GETAPIV1Messages".
+	Name string
+	// ID is an operationId as declared in the spec; may be empty.
+	ID string
+	// Method is HTTP method.
+	Method string
+	// Path is a route template as declared in the spec, e.g.
+	// "/api/v1/messages/{id}".
+	Path string
+	// Tags are operation tags as declared in the spec. Must not be modified.
+	Tags []string
+	// SensitiveHeaders are request headers marked with "x-sensitive" or
+	// "format: password" in the spec. Must not be modified.
+	SensitiveHeaders []string
+	// SensitiveFields are JSON request and response body fields marked with
+	// "x-sensitive" or "format: password" in the spec. Must not be modified.
+	SensitiveFields []string
+}
+
+// Handler performs a single HTTP exchange of operation.
+type Handler func(op Operation, req *http.Request) (*http.Response, error)
+
+// Middleware wraps handler, e.g. to add auth headers, logging or metrics.
+type Middleware func(next Handler) Handler
+
+// Chain wraps handler with middlewares; the first middleware is the outermost
+// one, i.e. it's called first.
+func Chain(handler Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
+type operationKey struct{}
+
+// WithOperation returns ctx carrying operation, so code which sees only
+// *http.Request, e.g. http.RoundTripper, knows which operation is called.
+func WithOperation(ctx context.Context, op Operation) context.Context {
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+// OperationFromContext returns operation set with WithOperation.
+func OperationFromContext(ctx context.Context) (Operation, bool) {
+	op, ok := ctx.Value(operationKey{}).(Operation)
+
+	return op, ok
+}
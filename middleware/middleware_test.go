@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(op Operation, req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+":"+op.Name)
+
+				return next(op, req)
+			}
+		}
+	}
+
+	handler := Chain(func(op Operation, req *http.Request) (*http.Response, error) {
+		calls = append(calls, "handler:"+op.Name)
+
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}, record("first"), record("second"))
+
+	op := Operation{Name: "GETApiV1Messages", Method: http.MethodGet, Path: "/api/v1/messages"}
+
+	resp, err := handler(op, httptest.NewRequest(op.Method, op.Path, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status mismatch: want %d; got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	want := []string{
+		"first:GETApiV1Messages",
+		"second:GETApiV1Messages",
+		"handler:GETApiV1Messages",
+	}
+
+	if !reflect.DeepEqual(want, calls) {
+		t.Fatalf("calls mismatch: want %v; got %v", want, calls)
+	}
+}
+
+func TestOperationContext(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := OperationFromContext(context.Background()); ok {
+		t.Fatal("unexpected operation in empty context")
+	}
+
+	want := Operation{Name: "GETApiV1Messages", Tags: []string{"messages"}}
+
+	got, ok := OperationFromContext(WithOperation(context.Background(), want))
+	if !ok || !reflect.DeepEqual(want, got) {
+		t.Fatalf("mismatch: want %+v; got %+v", want, got)
+	}
+}
@@ -0,0 +1,227 @@
+// Package policy applies config.QOS policies to operation calls.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vitaminniy/go-lib-http/breaker"
+	"github.com/vitaminniy/go-lib-http/config"
+	"github.com/vitaminniy/go-lib-http/hedge"
+	"github.com/vitaminniy/go-lib-http/limiter"
+	"github.com/vitaminniy/go-lib-http/retry"
+)
+
+// EventKind is a kind of policy event.
+type EventKind int
+
+const (
+	// EventRetry is emitted before every retry attempt.
+	EventRetry EventKind = iota + 1
+	// EventHedge is emitted before every hedged request.
+	EventHedge
+	// EventBreakerRejected is emitted when call is rejected by open circuit
+	// breaker.
+	EventBreakerRejected
+	// EventDone is emitted when call is finished.
+	EventDone
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventRetry:
+		return "retry"
+	case EventHedge:
+		return "hedge"
+	case EventBreakerRejected:
+		return "breaker_rejected"
+	case EventDone:
+		return "done"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes what happened during the call.
+type Event struct {
+	Kind      EventKind
+	Operation string
+	// Attempt is a retry attempt number starting with 1.
+	Attempt uint
+	// Duration is a duration of the whole call; set for EventDone.
+	Duration time.Duration
+	// Err is an error of the call; set for EventDone.
+	Err error
+}
+
+// Observer is called on policy events. It may be called concurrently.
+type Observer func(Event)
+
+// ExecutorOption overrides Executor creation.
+type ExecutorOption func(*Executor)
+
+// WithObserver sets function which is called on policy events.
+func WithObserver(observer Observer) ExecutorOption {
+	return func(e *Executor) {
+		e.observer = observer
+	}
+}
+
+// NewExecutor creates new policy executor.
+func NewExecutor(opts ...ExecutorOption) *Executor {
+	exec := &Executor{
+		breakers: make(map[string]breakerState),
+		limiters: make(map[string]limiterState),
+	}
+
+	for _, opt := range opts {
+		opt(exec)
+	}
+
+	return exec
+}
+
+type attemptKey struct{}
+
+// AttemptFromContext returns retry attempt number starting with 1. It returns
+// 0 if ctx does not belong to Do call.
+func AttemptFromContext(ctx context.Context) uint {
+	attempt, _ := ctx.Value(attemptKey{}).(uint)
+
+	return attempt
+}
+
+// Executor keeps stateful policies, i.e. circuit breakers and rate limiters,
+// per operation. The state is rebuilt when operation policy changes.
+type Executor struct {
+	observer Observer
+
+	mu       sync.Mutex
+	breakers map[string]breakerState
+	limiters map[string]limiterState
+}
+
+type breakerState struct {
+	cfg     breaker.Config
+	breaker *breaker.Breaker
+}
+
+type limiterState struct {
+	cfg     limiter.Config
+	limiter *limiter.Limiter
+}
+
+// Do calls op according to qos. Every retry attempt waits for rate limiter,
+// checks circuit breaker and is hedged.
+//
+// Errors marked with retry.Abort are not retried and are not counted as
+// breaker failures since they mean that remote side is responsive. Attempts
+// failed after ctx is done are not counted by breaker at all.
+//
+// Timeout is not applied here: callers are expected to derive ctx with
+// QOS.Context before preparing the call.
+//
+// Retry attempt number is available to op with AttemptFromContext.
+//
+//nolint:varnamelen // op is a common name for passed functions.
+func Do[T any](
+	ctx context.Context,
+	exec *Executor,
+	operation string,
+	qos config.QOS,
+	op func(context.Context) (T, error),
+) (T, error) {
+	var (
+		result         T
+		attempt        uint
+		start          = time.Now()
+		rateLimiter    = exec.limiter(operation, qos.Limiter.Config())
+		circuitBreaker = exec.breaker(operation, qos.Breaker.Config())
+	)
+
+	parent := ctx
+
+	err := retry.OnError(ctx, qos.Retry.Config(), func(ctx context.Context) error {
+		attempt++
+		if attempt > 1 {
+			exec.observe(Event{Kind: EventRetry, Operation: operation, Attempt: attempt})
+		}
+
+		ctx = context.WithValue(ctx, attemptKey{}, attempt)
+
+		if err := rateLimiter.Wait(ctx); err != nil {
+			return retry.Abort(fmt.Errorf("policy: rate limiter: %w", err))
+		}
+
+		if err := circuitBreaker.Allow(); err != nil {
+			exec.observe(Event{Kind: EventBreakerRejected, Operation: operation, Attempt: attempt})
+			return retry.Abort(err)
+		}
+
+		value, err := hedge.Do(ctx, qos.Hedging.Config(), func(ctx context.Context) (T, error) {
+			if hedge.AttemptFromContext(ctx) > 1 {
+				exec.observe(Event{Kind: EventHedge, Operation: operation, Attempt: attempt})
+			}
+
+			return op(ctx)
+		})
+		if err != nil && parent.Err() != nil {
+			// Caller's cancellation says nothing about upstream health.
+			circuitBreaker.Cancel()
+		} else {
+			circuitBreaker.Record(err == nil || retry.IsAborted(err))
+		}
+
+		if err != nil {
+			return err
+		}
+
+		result = value
+
+		return nil
+	})
+
+	exec.observe(Event{
+		Kind:      EventDone,
+		Operation: operation,
+		Attempt:   attempt,
+		Duration:  time.Since(start),
+		Err:       err,
+	})
+
+	return result, err //nolint:wrapcheck // Errors are wrapped by op.
+}
+
+func (e *Executor) observe(event Event) {
+	if e.observer != nil {
+		e.observer(event)
+	}
+}
+
+func (e *Executor) breaker(operation string, cfg breaker.Config) *breaker.Breaker {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	state, ok := e.breakers[operation]
+	if !ok || state.cfg != cfg {
+		state = breakerState{cfg: cfg, breaker: breaker.New(cfg)}
+		e.breakers[operation] = state
+	}
+
+	return state.breaker
+}
+
+func (e *Executor) limiter(operation string, cfg limiter.Config) *limiter.Limiter {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	state, ok := e.limiters[operation]
+	if !ok || state.cfg != cfg {
+		state = limiterState{cfg: cfg, limiter: limiter.New(cfg)}
+		e.limiters[operation] = state
+	}
+
+	return state.limiter
+}
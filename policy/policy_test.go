@@ -0,0 +1,230 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vitaminniy/go-lib-http/breaker"
+	"github.com/vitaminniy/go-lib-http/config"
+	"github.com/vitaminniy/go-lib-http/hedge"
+	"github.com/vitaminniy/go-lib-http/retry"
+)
+
+var ErrOperationFailed = errors.New("operation failed")
+
+func TestDoRetries(t *testing.T) {
+	t.Parallel()
+
+	exec := NewExecutor()
+	qos := config.QOS{Retry: config.Retry{Attempts: config.Ptr[uint](3)}}
+
+	var calls int
+
+	got, err := Do(context.Background(), exec, "op", qos, func(context.Context) (int, error) {
+		calls++
+		if calls < 3 {
+			return 0, ErrOperationFailed
+		}
+
+		return calls, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got != 3 {
+		t.Fatalf("mismatch: want %d; got %d", 3, got)
+	}
+}
+
+func TestDoAbortIsNotBreakerFailure(t *testing.T) {
+	t.Parallel()
+
+	exec := NewExecutor()
+	qos := config.QOS{
+		Retry:   config.Retry{Attempts: config.Ptr[uint](3)},
+		Breaker: config.Breaker{Failures: config.Ptr[uint](1), Cooldown: config.Ptr(time.Hour)},
+	}
+
+	var calls int
+
+	_, err := Do(context.Background(), exec, "op", qos, func(context.Context) (int, error) {
+		calls++
+
+		return 0, retry.Abort(ErrOperationFailed)
+	})
+	if !errors.Is(err, ErrOperationFailed) {
+		t.Fatalf("error mismatch: want %v; got %v", ErrOperationFailed, err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("aborted call was retried: %d calls", calls)
+	}
+
+	if state := exec.breaker("op", qos.Breaker.Config()).State(); state != breaker.StateClosed {
+		t.Fatalf("state mismatch: want %v; got %v", breaker.StateClosed, state)
+	}
+}
+
+func TestDoCallerCancelIsNotBreakerFailure(t *testing.T) {
+	t.Parallel()
+
+	exec := NewExecutor()
+	qos := config.QOS{
+		Retry:   config.Retry{Attempts: config.Ptr[uint](3)},
+		Breaker: config.Breaker{Failures: config.Ptr[uint](1), Cooldown: config.Ptr(time.Hour)},
+	}
+
+	for range 3 {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		_, err := Do(ctx, exec, "op", qos, func(ctx context.Context) (int, error) {
+			cancel()
+
+			return 0, ctx.Err()
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("error mismatch: want %v; got %v", context.Canceled, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	_, err := Do(ctx, exec, "op", qos, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error mismatch: want %v; got %v", context.DeadlineExceeded, err)
+	}
+
+	if state := exec.breaker("op", qos.Breaker.Config()).State(); state != breaker.StateClosed {
+		t.Fatalf("state mismatch: want %v; got %v", breaker.StateClosed, state)
+	}
+}
+
+func TestDoBreakerOpens(t *testing.T) {
+	t.Parallel()
+
+	exec := NewExecutor()
+	qos := config.QOS{
+		Retry:   config.Retry{Attempts: config.Ptr[uint](3)},
+		Breaker: config.Breaker{Failures: config.Ptr[uint](2), Cooldown: config.Ptr(time.Hour)},
+	}
+
+	var calls int
+
+	op := func(context.Context) (int, error) {
+		calls++
+
+		return 0, ErrOperationFailed
+	}
+
+	_, err := Do(context.Background(), exec, "op", qos, op)
+	if !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("error mismatch: want %v; got %v", breaker.ErrOpen, err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("calls mismatch: want %d; got %d", 2, calls)
+	}
+
+	// Breakers are kept per operation.
+	calls = 0
+	_, _ = Do(context.Background(), exec, "other", qos, op)
+
+	if calls == 0 {
+		t.Fatal("breaker of another operation is shared")
+	}
+
+	// Reloaded policy with the same values keeps breaker open.
+	qos.Breaker.Failures = config.Ptr[uint](2)
+	calls = 0
+
+	_, err = Do(context.Background(), exec, "op", qos, op)
+	if !errors.Is(err, breaker.ErrOpen) || calls != 0 {
+		t.Fatalf("breaker was rebuilt: %v after %d calls", err, calls)
+	}
+
+	// Changing policy rebuilds breaker.
+	qos.Breaker.Failures = config.Ptr[uint](10)
+	calls = 0
+
+	_, err = Do(context.Background(), exec, "op", qos, op)
+	if !errors.Is(err, ErrOperationFailed) {
+		t.Fatalf("error mismatch: want %v; got %v", ErrOperationFailed, err)
+	}
+
+	if calls != 3 {
+		t.Fatalf("breaker was not rebuilt: want %d calls; got %d", 3, calls)
+	}
+}
+
+func TestDoObserver(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu     sync.Mutex
+		events []EventKind
+	)
+
+	exec := NewExecutor(WithObserver(func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if event.Operation != "op" {
+			t.Errorf("operation mismatch: want %q; got %q", "op", event.Operation)
+		}
+
+		events = append(events, event.Kind)
+	}))
+
+	qos := config.QOS{
+		Retry:   config.Retry{Attempts: config.Ptr[uint](3)},
+		Hedging: config.Hedging{Delay: config.Ptr(time.Millisecond), Attempts: config.Ptr[uint](2)},
+		Breaker: config.Breaker{Failures: config.Ptr[uint](2), Cooldown: config.Ptr(time.Hour)},
+	}
+
+	var attempts []uint
+
+	_, err := Do(context.Background(), exec, "op", qos, func(ctx context.Context) (int, error) {
+		if hedge.AttemptFromContext(ctx) == 1 {
+			mu.Lock()
+			attempts = append(attempts, AttemptFromContext(ctx))
+			mu.Unlock()
+		}
+
+		time.Sleep(time.Millisecond * 20)
+
+		return 0, ErrOperationFailed
+	})
+	if !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("error mismatch: want %v; got %v", breaker.ErrOpen, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if want := []uint{1, 2}; !reflect.DeepEqual(want, attempts) {
+		t.Fatalf("attempts mismatch: want %v; got %v", want, attempts)
+	}
+
+	want := []EventKind{
+		EventHedge,
+		EventRetry,
+		EventHedge,
+		EventRetry,
+		EventBreakerRejected,
+		EventDone,
+	}
+
+	if !reflect.DeepEqual(want, events) {
+		t.Fatalf("events mismatch: want %v; got %v", want, events)
+	}
+}
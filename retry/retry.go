@@ -3,6 +3,7 @@ package retry
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"time"
@@ -12,12 +13,12 @@ import (
 type Config struct {
 	// Attempts is a number of times operation should attempt to execute
 	// successfully before returning.
-	Attempts uint
+	Attempts uint `json:"attempts,omitempty" yaml:"attempts,omitempty"`
 	// Backoff is a backoff interval between attempts.
-	Backoff time.Duration
+	Backoff time.Duration `json:"backoff,omitempty" yaml:"backoff,omitempty"`
 	// Jitter is a random interval added to backoff: rand(0, jitter) + backoff.
 	// Jitter is not applied when backoff set to 0.
-	Jitter time.Duration
+	Jitter time.Duration `json:"jitter,omitempty" yaml:"jitter,omitempty"`
 }
 
 // attempts returns number of attempts.
@@ -54,7 +55,38 @@ func (cfg *Config) backoff(attempt uint) time.Duration {
 	return time.Duration(backoff * int64(attempt))
 }
 
-// OnError retries operation on any occurred error.
+// Abort marks err as non-retriable: OnError stops retrying and returns err
+// as is. Marked err may be wrapped, e.g. with fmt.Errorf; OnError returns it
+// with wrapping then.
+func Abort(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &abortError{err: err}
+}
+
+// IsAborted reports whether err was marked with Abort.
+func IsAborted(err error) bool {
+	var abort *abortError
+
+	return errors.As(err, &abort)
+}
+
+type abortError struct {
+	err error
+}
+
+func (e *abortError) Error() string {
+	return e.err.Error()
+}
+
+func (e *abortError) Unwrap() error {
+	return e.err
+}
+
+// OnError retries operation on any occurred error except ones marked with
+// Abort.
 //
 //nolint:varnamelen // op is a common name for passed functions.
 func OnError(
@@ -72,6 +104,16 @@ func OnError(
 			return nil
 		}
 
+		var abort *abortError
+		if errors.As(err, &abort) {
+			// Marker wrapped by op is kept along with op's wrapping.
+			if err == error(abort) { //nolint:errorlint // Only unwrapped marker is dropped.
+				return abort.err
+			}
+
+			return err
+		}
+
 		backoff := cfg.backoff(attempt)
 		if backoff == 0 {
 			continue
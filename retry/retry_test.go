@@ -3,6 +3,7 @@ package retry
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -111,6 +112,46 @@ func TestOnError(t *testing.T) {
 	}
 }
 
+func TestOnErrorAbort(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+
+	err := OnError(context.Background(), Config{Attempts: 5}, func(context.Context) error {
+		calls++
+
+		return Abort(ErrOperationFailed)
+	})
+
+	compareErrors(t, ErrOperationFailed, err)
+
+	if IsAborted(err) {
+		t.Fatalf("abort wrapper leaked: %v", err)
+	}
+
+	if !IsAborted(fmt.Errorf("wrapped: %w", Abort(ErrOperationFailed))) {
+		t.Fatal("wrapped abort is not detected")
+	}
+
+	if calls != 1 {
+		t.Fatalf("calls mismatch: want %d; got %d", 1, calls)
+	}
+
+	err = OnError(context.Background(), Config{Attempts: 5}, func(context.Context) error {
+		return fmt.Errorf("op: %w", Abort(ErrOperationFailed))
+	})
+
+	compareErrors(t, ErrOperationFailed, err)
+
+	if want := "op: " + ErrOperationFailed.Error(); err.Error() != want {
+		t.Fatalf("message mismatch: want %q; got %q", want, err.Error())
+	}
+
+	if Abort(nil) != nil {
+		t.Fatal("expected nil for nil error")
+	}
+}
+
 func TestOnErrorContextError(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,120 @@
+// Package server provides helpers for generated server handlers.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/vitaminniy/go-lib-http/validate"
+)
+
+// Parameter locations reported in RequestError.
+const (
+	InPath   = "path"
+	InQuery  = "query"
+	InHeader = "header"
+	InBody   = "body"
+)
+
+// ErrMissing is returned when required parameter is missing.
+var ErrMissing = errors.New("required value is missing")
+
+// ErrNoResponse is returned when implementation returns response without any
+// body set.
+var ErrNoResponse = errors.New("server: response is not set")
+
+// RequestError describes invalid request. Default error handler responds
+// with 400 Bad Request to it.
+type RequestError struct {
+	// In is parameter location, e.g. InQuery.
+	In string
+	// Name is parameter name as declared in the spec; empty for body.
+	Name string
+	Err  error
+}
+
+func (e *RequestError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("invalid %s: %v", e.In, e.Err)
+	}
+
+	return fmt.Sprintf("invalid %s parameter %q: %v", e.In, e.Name, e.Err)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorHandler writes response for error returned by implementation or
+// request decoding.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// ErrorResponse is a body written by DefaultErrorHandler.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// DefaultErrorHandler responds with 400 Bad Request to RequestError and
+// *validate.Error and with 500 Internal Server Error to other errors, which
+// are logged and not exposed to the caller.
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Error: requestErr.Error()})
+		return
+	}
+
+	var validationErr *validate.Error
+	if errors.As(err, &validationErr) {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Error: validationErr.Error()})
+		return
+	}
+
+	slog.ErrorContext(r.Context(), "could not handle request",
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.String("error", err.Error()),
+	)
+
+	WriteJSON(w, http.StatusInternalServerError, ErrorResponse{Error: http.StatusText(http.StatusInternalServerError)})
+}
+
+// DecodeJSON decodes JSON request body into dst. Missing body is reported as
+// ErrMissing if it's required and ignored otherwise; ok reports whether body
+// was decoded.
+func DecodeJSON(r *http.Request, dst any, required bool) (ok bool, err error) {
+	err = json.NewDecoder(r.Body).Decode(dst)
+
+	switch {
+	case errors.Is(err, io.EOF) && required:
+		return false, &RequestError{In: InBody, Err: ErrMissing}
+	case errors.Is(err, io.EOF):
+		return false, nil
+	case err != nil:
+		return false, &RequestError{In: InBody, Err: err}
+	default:
+		return true, nil
+	}
+}
+
+// WriteJSON writes JSON response with status code.
+func WriteJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	// Headers are sent already; nothing to do on error.
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// WriteHeaders copies headers to response.
+func WriteHeaders(w http.ResponseWriter, headers map[string][]string) {
+	for key, values := range headers {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vitaminniy/go-lib-http/validate"
+)
+
+var ErrOperationFailed = errors.New("operation failed")
+
+func TestDecodeJSON(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		body     string
+		required bool
+		ok       bool
+		err      error
+	}{
+		{name: "valid", body: `{"text":"hi"}`, required: true, ok: true},
+		{name: "missing required", body: "", required: true, err: ErrMissing},
+		{name: "missing optional", body: "", required: false},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			var dst struct {
+				Text string `json:"text"`
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(c.body))
+
+			ok, err := DecodeJSON(req, &dst, c.required)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("error mismatch: want %v; got %v", c.err, err)
+			}
+
+			if ok != c.ok {
+				t.Fatalf("ok mismatch: want %v; got %v", c.ok, ok)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{"))
+
+	var requestErr *RequestError
+	if _, err := DecodeJSON(req, &struct{}{}, false); !errors.As(err, &requestErr) {
+		t.Fatalf("error mismatch: want %T; got %v", requestErr, err)
+	}
+}
+
+func TestDefaultErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		code int
+		body string
+	}{
+		{
+			name: "request error",
+			err:  &RequestError{In: InQuery, Name: "limit", Err: ErrMissing},
+			code: http.StatusBadRequest,
+			body: `invalid query parameter "limit": required value is missing`,
+		},
+		{
+			name: "validation error",
+			err: &validate.Error{Violations: []validate.Violation{
+				{Path: "/body/text", Err: validate.ErrRequired},
+			}},
+			code: http.StatusBadRequest,
+			body: "validate: /body/text: required value is missing",
+		},
+		{
+			name: "internal error",
+			err:  ErrOperationFailed,
+			code: http.StatusInternalServerError,
+			body: "Internal Server Error",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			DefaultErrorHandler(rec, httptest.NewRequest(http.MethodGet, "/", nil), c.err)
+
+			if rec.Code != c.code {
+				t.Fatalf("code mismatch: want %d; got %d", c.code, rec.Code)
+			}
+
+			var body ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("could not decode body: %v", err)
+			}
+
+			if body.Error != c.body {
+				t.Fatalf("body mismatch: want %q; got %q", c.body, body.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// W3C trace context headers.
+const (
+	HeaderTraceparent = "Traceparent"
+	HeaderTracestate  = "Tracestate"
+)
+
+const (
+	traceparentVersion = "00"
+	traceparentLength  = 55
+
+	flagSampled = 0x01
+)
+
+// ErrInvalidTraceparent is returned when traceparent header value is
+// malformed.
+var ErrInvalidTraceparent = errors.New("tracing: invalid traceparent")
+
+// TraceID is a W3C trace ID.
+type TraceID [16]byte
+
+// IsValid reports whether trace ID is not all zeros.
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanID is a W3C parent span ID.
+type SpanID [8]byte
+
+// IsValid reports whether span ID is not all zeros.
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanContext identifies span across service boundaries.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	// Flags are trace flags, see Sampled.
+	Flags byte
+	// State is an opaque tracestate header value.
+	State string
+}
+
+// IsValid reports whether span context can be propagated.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Sampled reports whether the caller may have recorded trace data.
+func (sc SpanContext) Sampled() bool {
+	return sc.Flags&flagSampled != 0
+}
+
+// Traceparent returns traceparent header value.
+func (sc SpanContext) Traceparent() string {
+	return fmt.Sprintf("%s-%s-%s-%02x", traceparentVersion, sc.TraceID, sc.SpanID, sc.Flags)
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext returns ctx carrying sc.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns span context set with ContextWithSpanContext.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+
+	return sc, ok && sc.IsValid()
+}
+
+// Inject sets trace context headers from span context in ctx. It does nothing
+// if ctx carries no valid span context.
+func Inject(ctx context.Context, header http.Header) {
+	sc, ok := SpanContextFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	header.Set(HeaderTraceparent, sc.Traceparent())
+
+	if sc.State != "" {
+		header.Set(HeaderTracestate, sc.State)
+	} else {
+		header.Del(HeaderTracestate)
+	}
+}
+
+// Extract returns ctx carrying span context from trace context headers. ctx is
+// returned as is if headers are missing or malformed.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	sc, err := ParseTraceparent(header.Get(HeaderTraceparent))
+	if err != nil {
+		return ctx
+	}
+
+	sc.State = strings.Join(header.Values(HeaderTracestate), ",")
+
+	return ContextWithSpanContext(ctx, sc)
+}
+
+// ParseTraceparent parses traceparent header value.
+//
+// Values of future versions are accepted as long as they start with version
+// 00 fields, as required by the specification.
+func ParseTraceparent(value string) (SpanContext, error) {
+	if len(value) < traceparentLength ||
+		(len(value) > traceparentLength && value[traceparentLength] != '-') {
+		return SpanContext{}, fmt.Errorf("%w: %q", ErrInvalidTraceparent, value)
+	}
+
+	parts := strings.SplitN(value[:traceparentLength], "-", 4) //nolint:gomnd // Number of fields.
+	if len(parts) != 4 ||
+		parts[0] == "ff" ||
+		(parts[0] == traceparentVersion && len(value) != traceparentLength) {
+		return SpanContext{}, fmt.Errorf("%w: %q", ErrInvalidTraceparent, value)
+	}
+
+	var (
+		sc      SpanContext
+		version [1]byte
+		flags   [1]byte
+	)
+
+	for _, field := range []struct {
+		dst []byte
+		src string
+	}{
+		{dst: version[:], src: parts[0]},
+		{dst: sc.TraceID[:], src: parts[1]},
+		{dst: sc.SpanID[:], src: parts[2]},
+		{dst: flags[:], src: parts[3]},
+	} {
+		if !isLowerHex(field.src) || hex.EncodedLen(len(field.dst)) != len(field.src) {
+			return SpanContext{}, fmt.Errorf("%w: %q", ErrInvalidTraceparent, value)
+		}
+
+		if _, err := hex.Decode(field.dst, []byte(field.src)); err != nil {
+			return SpanContext{}, fmt.Errorf("%w: %w", ErrInvalidTraceparent, err)
+		}
+	}
+
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("%w: zero trace or span id", ErrInvalidTraceparent)
+	}
+
+	sc.Flags = flags[0]
+
+	return sc, nil
+}
+
+func isLowerHex(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,129 @@
+// Package tracing provides tracing hooks and W3C trace context propagation for
+// generated clients.
+//
+// The package does not depend on any tracing library: Tracer can be adapted to
+// e.g. OpenTelemetry by starting native span and storing its identifiers with
+// ContextWithSpanContext, so outgoing requests carry them.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/vitaminniy/go-lib-http/middleware"
+	"github.com/vitaminniy/go-lib-http/policy"
+)
+
+// Attribute keys set by Middleware. They follow OpenTelemetry semantic
+// conventions.
+const (
+	AttributeMethod     = "http.request.method"
+	AttributeRoute      = "http.route"
+	AttributeStatusCode = "http.response.status_code"
+	AttributeAttempt    = "http.request.resend_count"
+)
+
+// Attribute is a span attribute.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Span is a started span.
+type Span interface {
+	// SetAttributes sets span attributes.
+	SetAttributes(attrs ...Attribute)
+	// End finishes span; err is nil on success.
+	End(err error)
+}
+
+// Tracer starts spans. Implementations must be safe for concurrent use.
+type Tracer interface {
+	// Start starts span as a child of span in ctx. Returned ctx must carry
+	// SpanContext of started span to be propagated to upstream.
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// StartOperation starts span of operation call covering all its HTTP
+// exchanges, e.g. retries and hedged requests, which spans started by
+// Middleware are children of. Span is named after operation ID, or canonical
+// operation name if the spec does not declare one.
+//
+// Nil tracer starts no span; returned span does nothing.
+func StartOperation(ctx context.Context, tracer Tracer, op middleware.Operation) (context.Context, Span) {
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	name := op.ID
+	if name == "" {
+		name = op.Name
+	}
+
+	return tracer.Start(ctx, name)
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+
+func (noopSpan) End(error) {}
+
+// Middleware starts span per HTTP exchange and injects trace context headers
+// into request. Span is named after HTTP method and route, e.g.
+// "GET /api/v1/messages", as OpenTelemetry conventions suggest for client
+// spans; it's a child of operation span, see StartOperation.
+//
+// Nil tracer only propagates trace context already present in request
+// context.
+func Middleware(tracer Tracer) middleware.Middleware {
+	return func(next middleware.Handler) middleware.Handler {
+		return func(op middleware.Operation, req *http.Request) (*http.Response, error) {
+			if tracer == nil {
+				Inject(req.Context(), req.Header)
+
+				return next(op, req)
+			}
+
+			name := op.Method + " " + op.Path
+
+			attrs := []Attribute{
+				{Key: AttributeMethod, Value: op.Method},
+				{Key: AttributeRoute, Value: op.Path},
+			}
+
+			// Resend count is zero for the first attempt.
+			if attempt := policy.AttemptFromContext(req.Context()); attempt > 1 {
+				attrs = append(attrs, Attribute{Key: AttributeAttempt, Value: attempt - 1})
+			}
+
+			ctx, span := tracer.Start(req.Context(), name, attrs...)
+
+			req = req.WithContext(ctx)
+			Inject(ctx, req.Header)
+
+			resp, err := next(op, req)
+			if resp != nil {
+				span.SetAttributes(Attribute{Key: AttributeStatusCode, Value: resp.StatusCode})
+			}
+
+			if err == nil && resp != nil && resp.StatusCode >= http.StatusInternalServerError {
+				span.End(&StatusError{Code: resp.StatusCode})
+			} else {
+				span.End(err)
+			}
+
+			return resp, err
+		}
+	}
+}
+
+// StatusError is passed to Span.End when upstream responds with server error.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return "tracing: server error " + strconv.Itoa(e.Code)
+}
@@ -0,0 +1,260 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/vitaminniy/go-lib-http/middleware"
+)
+
+const (
+	parentTraceparent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	childTraceparent  = "00-0af7651916cd43dd8448eb211c80319c-00f067aa0ba902b7-01"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		value string
+		err   error
+	}{
+		{name: "valid", value: parentTraceparent},
+		{name: "future version", value: "cc-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-what-the-future"},
+		{name: "empty", value: "", err: ErrInvalidTraceparent},
+		{name: "forbidden version", value: "ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", err: ErrInvalidTraceparent},
+		{name: "version 00 with suffix", value: parentTraceparent + "-01", err: ErrInvalidTraceparent},
+		{name: "uppercase", value: "00-0AF7651916CD43DD8448EB211C80319C-B7AD6B7169203331-01", err: ErrInvalidTraceparent},
+		{name: "zero trace id", value: "00-00000000000000000000000000000000-b7ad6b7169203331-01", err: ErrInvalidTraceparent},
+		{name: "zero span id", value: "00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01", err: ErrInvalidTraceparent},
+		{name: "bad separator", value: "00_0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", err: ErrInvalidTraceparent},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			sc, err := ParseTraceparent(c.value)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("error mismatch: want %v; got %v", c.err, err)
+			}
+
+			if c.err != nil {
+				return
+			}
+
+			if !sc.Sampled() {
+				t.Fatal("span context is not sampled")
+			}
+
+			// Values are always propagated with the version we support.
+			want := traceparentVersion + c.value[2:traceparentLength]
+			if got := sc.Traceparent(); got != want {
+				t.Fatalf("traceparent mismatch: want %q; got %q", want, got)
+			}
+		})
+	}
+}
+
+func TestExtractInject(t *testing.T) {
+	t.Parallel()
+
+	incoming := http.Header{}
+	incoming.Set(HeaderTraceparent, parentTraceparent)
+	incoming.Add(HeaderTracestate, "congo=t61rcWkgMzE")
+	incoming.Add(HeaderTracestate, "rojo=00f067aa0ba902b7")
+
+	ctx := Extract(context.Background(), incoming)
+
+	outgoing := http.Header{}
+	Inject(ctx, outgoing)
+
+	if got := outgoing.Get(HeaderTraceparent); got != parentTraceparent {
+		t.Fatalf("traceparent mismatch: want %q; got %q", parentTraceparent, got)
+	}
+
+	if want, got := "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7", outgoing.Get(HeaderTracestate); got != want {
+		t.Fatalf("tracestate mismatch: want %q; got %q", want, got)
+	}
+
+	empty := http.Header{}
+	Inject(Extract(context.Background(), http.Header{}), empty)
+
+	if len(empty) != 0 {
+		t.Fatalf("unexpected headers: %v", empty)
+	}
+}
+
+type testSpan struct {
+	name  string
+	attrs []Attribute
+	// parent is traceparent of parent span context.
+	parent string
+	err    error
+	ended  bool
+}
+
+func (s *testSpan) SetAttributes(attrs ...Attribute) {
+	s.attrs = append(s.attrs, attrs...)
+}
+
+func (s *testSpan) End(err error) {
+	s.err = err
+	s.ended = true
+}
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (tr *testTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	parent, _ := SpanContextFromContext(ctx)
+
+	span := &testSpan{name: name, attrs: attrs, parent: parent.Traceparent()}
+	tr.spans = append(tr.spans, span)
+
+	child, err := ParseTraceparent(childTraceparent)
+	if err != nil {
+		panic(err)
+	}
+
+	child.State = parent.State
+
+	return ContextWithSpanContext(ctx, child), span
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tracer := &testTracer{}
+
+	handler := middleware.Chain(func(_ middleware.Operation, req *http.Request) (*http.Response, error) {
+		if got := req.Header.Get(HeaderTraceparent); got != childTraceparent {
+			t.Errorf("traceparent mismatch: want %q; got %q", childTraceparent, got)
+		}
+
+		return &http.Response{StatusCode: http.StatusServiceUnavailable}, nil
+	}, Middleware(tracer))
+
+	op := middleware.Operation{
+		Name:   "GETApiV1Messages",
+		ID:     "listMessages",
+		Method: http.MethodGet,
+		Path:   "/api/v1/messages",
+	}
+
+	req := httptest.NewRequest(op.Method, op.Path, nil)
+	req.Header.Set(HeaderTraceparent, parentTraceparent)
+	req = req.WithContext(Extract(req.Context(), req.Header))
+
+	if _, err := handler(op, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("spans mismatch: want %d; got %d", 1, len(tracer.spans))
+	}
+
+	span := tracer.spans[0]
+	if want := "GET /api/v1/messages"; span.name != want || !span.ended {
+		t.Fatalf("span mismatch: want ended %q; got %+v", want, span)
+	}
+
+	wantAttrs := []Attribute{
+		{Key: AttributeMethod, Value: http.MethodGet},
+		{Key: AttributeRoute, Value: "/api/v1/messages"},
+		{Key: AttributeStatusCode, Value: http.StatusServiceUnavailable},
+	}
+
+	if !reflect.DeepEqual(wantAttrs, span.attrs) {
+		t.Fatalf("attributes mismatch: want %v; got %v", wantAttrs, span.attrs)
+	}
+
+	var statusErr *StatusError
+	if !errors.As(span.err, &statusErr) || statusErr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("error mismatch: want status error; got %v", span.err)
+	}
+}
+
+func TestStartOperation(t *testing.T) {
+	t.Parallel()
+
+	tracer := &testTracer{}
+	handler := middleware.Chain(func(_ middleware.Operation, _ *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}, Middleware(tracer))
+
+	op := middleware.Operation{Name: "GETApiV1Messages", Method: http.MethodGet, Path: "/api/v1/messages"}
+
+	sc, err := ParseTraceparent(parentTraceparent)
+	if err != nil {
+		t.Fatalf("could not parse: %v", err)
+	}
+
+	ctx, span := StartOperation(ContextWithSpanContext(context.Background(), sc), tracer, op)
+
+	// Retries share operation span.
+	for range 2 {
+		req := httptest.NewRequest(op.Method, op.Path, nil).WithContext(ctx)
+		if _, err := handler(op, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	span.End(nil)
+
+	if len(tracer.spans) != 3 {
+		t.Fatalf("spans mismatch: want %d; got %d", 3, len(tracer.spans))
+	}
+
+	operation := tracer.spans[0]
+	if operation.name != op.Name || operation.parent != parentTraceparent || !operation.ended {
+		t.Fatalf("operation span mismatch: %+v", operation)
+	}
+
+	for _, attempt := range tracer.spans[1:] {
+		if attempt.parent != childTraceparent || !attempt.ended {
+			t.Fatalf("attempt span is not a child of operation one: %+v", attempt)
+		}
+	}
+
+	if ctx, span := StartOperation(context.Background(), nil, op); ctx != context.Background() || span == nil {
+		t.Fatalf("nil tracer started span: %+v", span)
+	}
+}
+
+func TestMiddlewareNilTracer(t *testing.T) {
+	t.Parallel()
+
+	handler := middleware.Chain(func(_ middleware.Operation, req *http.Request) (*http.Response, error) {
+		if got := req.Header.Get(HeaderTraceparent); got != parentTraceparent {
+			t.Errorf("traceparent mismatch: want %q; got %q", parentTraceparent, got)
+		}
+
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}, Middleware(nil))
+
+	sc, err := ParseTraceparent(parentTraceparent)
+	if err != nil {
+		t.Fatalf("could not parse: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(ContextWithSpanContext(req.Context(), sc))
+
+	if _, err := handler(middleware.Operation{}, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package validate
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"time"
+)
+
+var (
+	errNotAbsolute = errors.New("not an absolute URI")
+	errNotAddress  = errors.New("not a plain address")
+	errNotIPv4     = errors.New("not an IPv4 address")
+	errNotIPv6     = errors.New("not an IPv6 address")
+	errNotUUID     = errors.New("not a UUID")
+)
+
+// Formats lists formats checked by Validator.Format.
+//
+//nolint:gochecknoglobals // Read-only lookup table.
+var Formats = []string{"byte", "date", "date-time", "email", "ipv4", "ipv6", "uri", "uuid"}
+
+//nolint:gochecknoglobals // Read-only lookup table.
+var formats = map[string]func(value string) error{
+	"byte":      checkByte,
+	"date":      checkDate,
+	"date-time": checkDateTime,
+	"email":     checkEmail,
+	"ipv4":      checkIPv4,
+	"ipv6":      checkIPv6,
+	"uri":       checkURI,
+	"uuid":      checkUUID,
+}
+
+func checkByte(value string) error {
+	_, err := base64.StdEncoding.DecodeString(value)
+	return err
+}
+
+func checkDate(value string) error {
+	_, err := time.Parse(time.DateOnly, value)
+	return err
+}
+
+func checkDateTime(value string) error {
+	_, err := time.Parse(time.RFC3339, value)
+	return err
+}
+
+func checkEmail(value string) error {
+	address, err := mail.ParseAddress(value)
+	if err != nil {
+		return err
+	}
+
+	if address.Address != value {
+		return errNotAddress
+	}
+
+	return nil
+}
+
+func checkIPv4(value string) error {
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return err
+	}
+
+	if !addr.Is4() {
+		return errNotIPv4
+	}
+
+	return nil
+}
+
+func checkIPv6(value string) error {
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return err
+	}
+
+	if !addr.Is6() {
+		return errNotIPv6
+	}
+
+	return nil
+}
+
+func checkURI(value string) error {
+	uri, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+
+	if !uri.IsAbs() {
+		return errNotAbsolute
+	}
+
+	return nil
+}
+
+func checkUUID(value string) error {
+	if len(value) != len("00000000-0000-0000-0000-000000000000") {
+		return errNotUUID
+	}
+
+	for i, r := range value {
+		switch {
+		case i == 8 || i == 13 || i == 18 || i == 23:
+			if r != '-' {
+				return errNotUUID
+			}
+		case '0' <= r && r <= '9', 'a' <= r && r <= 'f', 'A' <= r && r <= 'F':
+		default:
+			return errNotUUID
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Errors reported by Schemas.Check.
+var (
+	ErrType         = errors.New("unexpected type")
+	ErrUnknownField = errors.New("unknown field")
+)
+
+// Schema types.
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+)
+
+// Schema describes JSON value for Schemas.Check. Empty schema matches any
+// value.
+type Schema struct {
+	// Ref is a name of schema in Schemas; other fields are ignored if set.
+	Ref string
+	// Type is one of Type constants; empty type matches any value.
+	Type     string
+	Nullable bool
+	// Format is checked as by Validator.Format.
+	Format string
+	Enum   []string
+	// Properties and Required describe object; Closed rejects properties not
+	// listed, i.e. additionalProperties is false.
+	Properties map[string]*Schema
+	Required   []string
+	Closed     bool
+	// Items describes array items.
+	Items *Schema
+}
+
+// Schemas are named schemas, e.g. spec components.
+type Schemas map[string]*Schema
+
+// Check checks JSON value against schema name. Unlike Validate methods of
+// generated types, it detects missing required and unknown properties.
+// Violations are reported as *Error; unknown schema matches any value.
+func (s Schemas) Check(name string, raw []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("validate: could not decode value: %w", err)
+	}
+
+	v := &Validator{}
+	s.check(v, "", &Schema{Ref: name}, value)
+
+	return v.Err()
+}
+
+//nolint:cyclop // Flat switch over schema types.
+func (s Schemas) check(v *Validator, path string, schema *Schema, value any) {
+	for schema != nil && schema.Ref != "" {
+		schema = s[schema.Ref]
+	}
+
+	if schema == nil || schema.Type == "" {
+		return
+	}
+
+	if value == nil {
+		if !schema.Nullable {
+			v.Report(path, fmt.Errorf("%w: want %s; got null", ErrType, schema.Type))
+		}
+
+		return
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		if s.checkType(v, path, schema, TypeObject) {
+			s.checkObject(v, path, schema, value)
+		}
+	case []any:
+		if s.checkType(v, path, schema, TypeArray) {
+			for i, item := range value {
+				s.check(v, Index(path, i), schema.Items, item)
+			}
+		}
+	case string:
+		if s.checkType(v, path, schema, TypeString) {
+			if len(schema.Enum) > 0 {
+				v.Enum(path, value, schema.Enum...)
+			}
+
+			v.Format(path, value, schema.Format)
+		}
+	case json.Number:
+		if schema.Type == TypeInteger {
+			if _, err := value.Int64(); err != nil {
+				v.Report(path, fmt.Errorf("%w: want %s; got %s", ErrType, schema.Type, value))
+			}
+
+			return
+		}
+
+		s.checkType(v, path, schema, TypeNumber)
+	case bool:
+		s.checkType(v, path, schema, TypeBoolean)
+	}
+}
+
+func (s Schemas) checkType(v *Validator, path string, schema *Schema, typ string) bool {
+	if schema.Type != typ {
+		v.Report(path, fmt.Errorf("%w: want %s; got %s", ErrType, schema.Type, typ))
+		return false
+	}
+
+	return true
+}
+
+func (s Schemas) checkObject(v *Validator, path string, schema *Schema, value map[string]any) {
+	for _, name := range schema.Required {
+		if _, ok := value[name]; !ok {
+			v.Report(Field(path, name), ErrRequired)
+		}
+	}
+
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+
+	// Sorted for stable violations order.
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		property, ok := schema.Properties[key]
+		if !ok {
+			if schema.Closed {
+				v.Report(Field(path, key), ErrUnknownField)
+			}
+
+			continue
+		}
+
+		s.check(v, Field(path, key), property, value[key])
+	}
+}
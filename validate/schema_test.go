@@ -0,0 +1,125 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+//nolint:gochecknoglobals // Test fixture.
+var testSchemas = Schemas{
+	"Message": {
+		Type:     TypeObject,
+		Required: []string{"id", "text"},
+		Properties: map[string]*Schema{
+			"id":     {Type: TypeString, Format: "uuid"},
+			"text":   {Type: TypeString},
+			"kind":   {Type: TypeString, Enum: []string{"plain", "markdown"}},
+			"likes":  {Type: TypeInteger},
+			"author": {Ref: "Author"},
+			"tags":   {Type: TypeArray, Items: &Schema{Type: TypeString}},
+			"extra":  {},
+		},
+		Closed: true,
+	},
+	"Author": {
+		Type:       TypeObject,
+		Properties: map[string]*Schema{"name": {Type: TypeString}},
+		Nullable:   true,
+	},
+}
+
+func TestSchemasCheck(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		raw        string
+		violations []Violation
+	}{
+		{
+			name: "valid",
+			raw: `{"id":"123e4567-e89b-12d3-a456-426614174000","text":"hi","kind":"plain",` +
+				`"likes":3,"author":{"name":"alice","age":3},"tags":["a"],"extra":[1]}`,
+		},
+		{
+			name: "null author",
+			raw:  `{"id":"123e4567-e89b-12d3-a456-426614174000","text":"hi","author":null}`,
+		},
+		{
+			name: "missing required",
+			raw:  `{"id":"123e4567-e89b-12d3-a456-426614174000"}`,
+			violations: []Violation{
+				{Path: "/text", Err: ErrRequired},
+			},
+		},
+		{
+			name: "unknown field",
+			raw:  `{"id":"123e4567-e89b-12d3-a456-426614174000","text":"hi","a/b":1}`,
+			violations: []Violation{
+				{Path: "/a~1b", Err: ErrUnknownField},
+			},
+		},
+		{
+			name: "invalid values",
+			raw:  `{"id":"1","text":null,"kind":"html","likes":1.5,"tags":["a",2]}`,
+			violations: []Violation{
+				{Path: "/id", Err: ErrFormat},
+				{Path: "/kind", Err: ErrEnum},
+				{Path: "/likes", Err: ErrType},
+				{Path: "/tags/1", Err: ErrType},
+				{Path: "/text", Err: ErrType},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testSchemas.Check("Message", []byte(c.raw))
+			if len(c.violations) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			var validationErr *Error
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("error mismatch: want %T; got %v", validationErr, err)
+			}
+
+			got := make([]Violation, 0, len(validationErr.Violations))
+			for _, violation := range validationErr.Violations {
+				got = append(got, Violation{Path: violation.Path, Err: unwrapSentinel(violation.Err)})
+			}
+
+			if !reflect.DeepEqual(c.violations, got) {
+				t.Fatalf("violations mismatch: want %v; got %v", c.violations, validationErr.Violations)
+			}
+		})
+	}
+
+	if err := testSchemas.Check("Message", []byte("{")); err == nil {
+		t.Fatal("expected decoding error")
+	}
+
+	if err := testSchemas.Check("Unknown", []byte(`{"a":1}`)); err != nil {
+		t.Fatalf("unexpected error for unknown schema: %v", err)
+	}
+}
+
+// unwrapSentinel returns error of this package wrapped by err.
+func unwrapSentinel(err error) error {
+	for _, sentinel := range []error{ErrRequired, ErrUnknownField, ErrType, ErrFormat, ErrEnum} {
+		if errors.Is(err, sentinel) {
+			return sentinel
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,247 @@
+// Package validate checks values against schema constraints in generated
+// Validate methods.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Errors reported by Validator checks; violations wrap them with details.
+var (
+	ErrRequired  = errors.New("required value is missing")
+	ErrMinLength = errors.New("value is too short")
+	ErrMaxLength = errors.New("value is too long")
+	ErrMinimum   = errors.New("value is too small")
+	ErrMaximum   = errors.New("value is too big")
+	ErrPattern   = errors.New("value does not match pattern")
+	ErrFormat    = errors.New("value does not match format")
+	ErrEnum      = errors.New("value is not allowed")
+	ErrMinItems  = errors.New("too few items")
+	ErrMaxItems  = errors.New("too many items")
+)
+
+// Config controls validation of method calls.
+type Config struct {
+	// SkipRequest disables request validation before sending.
+	SkipRequest bool `json:"skipRequest,omitempty" yaml:"skipRequest,omitempty"`
+	// StrictResponse enables checks of response bodies with Schemas.Check
+	// to detect contract drift.
+	StrictResponse bool `json:"strictResponse,omitempty" yaml:"strictResponse,omitempty"`
+}
+
+// Violation is a failed check of value at JSON pointer Path, e.g.
+// "/body/items/0/name".
+type Violation struct {
+	Path string
+	Err  error
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Err.Error()
+}
+
+// Error lists all violations found by Validator. errors.Is matches errors of
+// violations, e.g. ErrRequired.
+type Error struct {
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.String())
+	}
+
+	return "validate: " + strings.Join(messages, "; ")
+}
+
+func (e *Error) Unwrap() []error {
+	errs := make([]error, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		errs = append(errs, violation.Err)
+	}
+
+	return errs
+}
+
+// Validator collects violations. Zero value is ready to use.
+type Validator struct {
+	violations []Violation
+}
+
+// Err returns *Error listing collected violations or nil if there are none.
+func (v *Validator) Err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+
+	return &Error{Violations: v.violations}
+}
+
+// Report adds violation of value at path.
+func (v *Validator) Report(path string, err error) {
+	if path == "" {
+		path = "/"
+	}
+
+	v.violations = append(v.violations, Violation{Path: path, Err: err})
+}
+
+// Required reports ErrRequired unless value is present.
+func (v *Validator) Required(path string, present bool) {
+	if !present {
+		v.Report(path, ErrRequired)
+	}
+}
+
+// MinLength checks that value has at least limit characters.
+func (v *Validator) MinLength(path, value string, limit int) {
+	if n := utf8.RuneCountInString(value); n < limit {
+		v.Report(path, fmt.Errorf("%w: length %d is less than %d", ErrMinLength, n, limit))
+	}
+}
+
+// MaxLength checks that value has at most limit characters.
+func (v *Validator) MaxLength(path, value string, limit int) {
+	if n := utf8.RuneCountInString(value); n > limit {
+		v.Report(path, fmt.Errorf("%w: length %d is greater than %d", ErrMaxLength, n, limit))
+	}
+}
+
+// Minimum checks that value is not less than limit, or greater than limit if
+// exclusive is set.
+func (v *Validator) Minimum(path string, value, limit float64, exclusive bool) {
+	switch {
+	case exclusive && value <= limit:
+		v.Report(path, fmt.Errorf("%w: %v is not greater than %v", ErrMinimum, value, limit))
+	case value < limit:
+		v.Report(path, fmt.Errorf("%w: %v is less than %v", ErrMinimum, value, limit))
+	}
+}
+
+// Maximum checks that value is not greater than limit, or less than limit if
+// exclusive is set.
+func (v *Validator) Maximum(path string, value, limit float64, exclusive bool) {
+	switch {
+	case exclusive && value >= limit:
+		v.Report(path, fmt.Errorf("%w: %v is not less than %v", ErrMaximum, value, limit))
+	case value > limit:
+		v.Report(path, fmt.Errorf("%w: %v is greater than %v", ErrMaximum, value, limit))
+	}
+}
+
+//nolint:gochecknoglobals // Compiled patterns are shared by all validators.
+var patterns sync.Map
+
+// Pattern checks that value matches regular expression. Compiled expressions
+// are cached.
+func (v *Validator) Pattern(path, value, pattern string) {
+	re, ok := patterns.Load(pattern)
+	if !ok {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			v.Report(path, fmt.Errorf("%w %q: %w", ErrPattern, pattern, err))
+			return
+		}
+
+		re, _ = patterns.LoadOrStore(pattern, compiled)
+	}
+
+	if !re.(*regexp.Regexp).MatchString(value) { //nolint:forcetypeassert // Only *regexp.Regexp is stored.
+		v.Report(path, fmt.Errorf("%w %q", ErrPattern, pattern))
+	}
+}
+
+// Format checks that value is formatted as declared. Unknown formats are not
+// checked; see Formats.
+func (v *Validator) Format(path, value, format string) {
+	check, ok := formats[format]
+	if !ok {
+		return
+	}
+
+	if err := check(value); err != nil {
+		v.Report(path, fmt.Errorf("%w %q: %w", ErrFormat, format, err))
+	}
+}
+
+// Enum checks that value is one of allowed values.
+func (v *Validator) Enum(path, value string, allowed ...string) {
+	for _, candidate := range allowed {
+		if value == candidate {
+			return
+		}
+	}
+
+	v.Report(path, fmt.Errorf("%w: %q is not one of %q", ErrEnum, value, allowed))
+}
+
+// NumberEnum checks that numeric value is one of allowed values.
+func (v *Validator) NumberEnum(path string, value float64, allowed ...float64) {
+	for _, candidate := range allowed {
+		if value == candidate {
+			return
+		}
+	}
+
+	v.Report(path, fmt.Errorf("%w: %v is not one of %v", ErrEnum, value, allowed))
+}
+
+// Number parses numeric parameter value, e.g. query parameter, for range
+// checks. ErrType is reported unless value is a number, or an integer if
+// integer is set.
+func (v *Validator) Number(path, value string, integer bool) (float64, bool) {
+	if integer {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			v.Report(path, fmt.Errorf("%w: want %s; got %q", ErrType, TypeInteger, value))
+			return 0, false
+		}
+
+		return float64(n), true
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		v.Report(path, fmt.Errorf("%w: want %s; got %q", ErrType, TypeNumber, value))
+		return 0, false
+	}
+
+	return n, true
+}
+
+// MinItems checks that there are at least limit items.
+func (v *Validator) MinItems(path string, n, limit int) {
+	if n < limit {
+		v.Report(path, fmt.Errorf("%w: %d is less than %d", ErrMinItems, n, limit))
+	}
+}
+
+// MaxItems checks that there are at most limit items.
+func (v *Validator) MaxItems(path string, n, limit int) {
+	if n > limit {
+		v.Report(path, fmt.Errorf("%w: %d is greater than %d", ErrMaxItems, n, limit))
+	}
+}
+
+// Field returns JSON pointer to object property.
+func Field(path, name string) string {
+	return path + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
+
+// Index returns JSON pointer to array item.
+func Index(path string, i int) string {
+	return path + "/" + strconv.Itoa(i)
+}
+
+// IsZero reports whether value is zero, e.g. optional object is not set.
+func IsZero[T any](value T) bool {
+	return reflect.ValueOf(&value).Elem().IsZero()
+}
@@ -0,0 +1,144 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidator(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		check func(v *Validator)
+		err   error
+	}{
+		{name: "required", check: func(v *Validator) { v.Required("/a", false) }, err: ErrRequired},
+		{name: "present", check: func(v *Validator) { v.Required("/a", true) }},
+		{name: "min length", check: func(v *Validator) { v.MinLength("/a", "ab", 3) }, err: ErrMinLength},
+		{name: "min length runes", check: func(v *Validator) { v.MinLength("/a", "äöü", 3) }},
+		{name: "max length", check: func(v *Validator) { v.MaxLength("/a", "abcd", 3) }, err: ErrMaxLength},
+		{name: "minimum", check: func(v *Validator) { v.Minimum("/a", 1, 2, false) }, err: ErrMinimum},
+		{name: "minimum equal", check: func(v *Validator) { v.Minimum("/a", 2, 2, false) }},
+		{name: "exclusive minimum", check: func(v *Validator) { v.Minimum("/a", 2, 2, true) }, err: ErrMinimum},
+		{name: "maximum", check: func(v *Validator) { v.Maximum("/a", 3, 2, false) }, err: ErrMaximum},
+		{name: "exclusive maximum", check: func(v *Validator) { v.Maximum("/a", 2, 2, true) }, err: ErrMaximum},
+		{name: "pattern", check: func(v *Validator) { v.Pattern("/a", "abc", "^[0-9]+$") }, err: ErrPattern},
+		{name: "pattern match", check: func(v *Validator) { v.Pattern("/a", "123", "^[0-9]+$") }},
+		{name: "invalid pattern", check: func(v *Validator) { v.Pattern("/a", "123", "(") }, err: ErrPattern},
+		{name: "enum", check: func(v *Validator) { v.Enum("/a", "c", "a", "b") }, err: ErrEnum},
+		{name: "enum match", check: func(v *Validator) { v.Enum("/a", "b", "a", "b") }},
+		{name: "number enum", check: func(v *Validator) { v.NumberEnum("/a", 0, 1, 2) }, err: ErrEnum},
+		{name: "number enum match", check: func(v *Validator) { v.NumberEnum("/a", 2, 1, 2) }},
+		{name: "number", check: func(v *Validator) { v.Number("/a", "1.5", false) }},
+		{name: "not number", check: func(v *Validator) { v.Number("/a", "ten", false) }, err: ErrType},
+		{name: "integer", check: func(v *Validator) { v.Number("/a", "-10", true) }},
+		{name: "not integer", check: func(v *Validator) { v.Number("/a", "1.5", true) }, err: ErrType},
+		{name: "min items", check: func(v *Validator) { v.MinItems("/a", 1, 2) }, err: ErrMinItems},
+		{name: "max items", check: func(v *Validator) { v.MaxItems("/a", 3, 2) }, err: ErrMaxItems},
+		{name: "unknown format", check: func(v *Validator) { v.Format("/a", "x", "password") }},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			var v Validator
+			c.check(&v)
+
+			err := v.Err()
+			if c.err == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !errors.Is(err, c.err) {
+				t.Fatalf("error mismatch: want %v; got %v", c.err, err)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		format  string
+		valid   string
+		invalid string
+	}{
+		{format: "byte", valid: "aGVsbG8=", invalid: "hello!"},
+		{format: "date", valid: "2024-02-29", invalid: "2023-02-29"},
+		{format: "date-time", valid: "2024-01-02T15:04:05+03:00", invalid: "2024-01-02 15:04:05"},
+		{format: "email", valid: "user@example.com", invalid: "User <user@example.com>"},
+		{format: "ipv4", valid: "192.0.2.1", invalid: "2001:db8::1"},
+		{format: "ipv6", valid: "2001:db8::1", invalid: "192.0.2.1"},
+		{format: "uri", valid: "https://example.com/a", invalid: "/a"},
+		{format: "uuid", valid: "123e4567-e89b-12d3-a456-426614174000", invalid: "123e4567e89b12d3a456426614174000"},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.format, func(t *testing.T) {
+			t.Parallel()
+
+			var v Validator
+
+			v.Format("/valid", c.valid, c.format)
+
+			if err := v.Err(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			v.Format("/invalid", c.invalid, c.format)
+
+			if err := v.Err(); !errors.Is(err, ErrFormat) {
+				t.Fatalf("error mismatch: want %v; got %v", ErrFormat, err)
+			}
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	t.Parallel()
+
+	var v Validator
+
+	v.Required("/body/name", false)
+	v.MinItems(Index("/body/items", 0), 0, 1)
+	v.Report("", ErrEnum)
+
+	var validationErr *Error
+	if err := v.Err(); !errors.As(err, &validationErr) {
+		t.Fatalf("error mismatch: want %T; got %v", validationErr, err)
+	}
+
+	paths := []string{"/body/name", "/body/items/0", "/"}
+
+	if len(validationErr.Violations) != len(paths) {
+		t.Fatalf("violations mismatch: want %d; got %v", len(paths), validationErr.Violations)
+	}
+
+	for i, path := range paths {
+		if got := validationErr.Violations[i].Path; got != path {
+			t.Fatalf("path mismatch: want %q; got %q", path, got)
+		}
+	}
+
+	const want = "validate: /body/name: required value is missing; " +
+		"/body/items/0: too few items: 0 is less than 1; /: value is not allowed"
+
+	if got := validationErr.Error(); got != want {
+		t.Fatalf("message mismatch: want %q; got %q", want, got)
+	}
+
+	if got, want := Field("/body", "a/b~c"), "/body/a~1b~0c"; got != want {
+		t.Fatalf("field mismatch: want %q; got %q", want, got)
+	}
+
+	if IsZero(struct{ A []int }{A: []int{}}) || !IsZero(struct{ A []int }{}) {
+		t.Fatal("IsZero mismatch")
+	}
+}